@@ -5,6 +5,7 @@ import (
 	"database/sql"
 	"fmt"
 	"log"
+	"net"
 	"os"
 	"os/signal"
 	"syscall"
@@ -15,15 +16,32 @@ import (
 	"github.com/gofiber/fiber/v2/middleware/requestid"
 	"github.com/gofiber/fiber/v2/middleware/recover"
 	"github.com/Pallavi566/Go-Backend/config"
+	"github.com/Pallavi566/Go-Backend/internal/age"
+	"github.com/Pallavi566/Go-Backend/internal/cli"
+	"github.com/Pallavi566/Go-Backend/internal/events"
 	"github.com/Pallavi566/Go-Backend/internal/handler"
+	"github.com/Pallavi566/Go-Backend/internal/health"
+	"github.com/Pallavi566/Go-Backend/internal/jobs"
 	"github.com/Pallavi566/Go-Backend/internal/logger"
+	"github.com/Pallavi566/Go-Backend/internal/metrics"
+	"github.com/Pallavi566/Go-Backend/internal/middleware"
 	"github.com/Pallavi566/Go-Backend/internal/repository"
 	"github.com/Pallavi566/Go-Backend/internal/routes"
 	"github.com/Pallavi566/Go-Backend/internal/service"
+	"github.com/Pallavi566/Go-Backend/internal/tracing"
+	"github.com/Pallavi566/Go-Backend/internal/webhook"
 	"go.uber.org/zap"
 )
 
+// requestTimeout bounds the time each request may take.
+const requestTimeout = 30 * time.Second
+
 func main() {
+	// Subcommands run instead of the server
+	if len(os.Args) > 1 && os.Args[1] == "import" {
+		os.Exit(cli.Import(os.Args[2:], os.Stdout, os.Stderr))
+	}
+
 	// Initialize logger
 	if err := logger.InitLogger(); err != nil {
 		log.Fatalf("Failed to initialize logger: %v", err)
@@ -42,6 +60,16 @@ func main() {
 
 	logger.Log.Info("Starting User API Server...")
 
+	// Initialize tracing
+	shutdownTracing, err := tracing.Setup(ctx, tracing.Config{
+		Exporter:    cfg.TracingExporter,
+		Endpoint:    cfg.TracingEndpoint,
+		ServiceName: "user-api",
+	})
+	if err != nil {
+		logger.Log.Fatal("Failed to set up tracing", zap.Error(err))
+	}
+
 	// Initialize database connection
 	db, err := sql.Open("mysql", cfg.GetDSN())
 	if err != nil {
@@ -52,16 +80,64 @@ func main() {
 	if err := db.PingContext(ctx); err != nil {
 		logger.Log.Fatal("Failed to ping database", zap.Error(err))
 	}
+	if err := metrics.RegisterDB(db, cfg.DBName); err != nil {
+		logger.Log.Fatal("Failed to register database metrics", zap.Error(err))
+	}
 
 	// Initialize repository, service, and handler
 	userRepo := repository.NewUserRepository(db)
-	userService := service.NewUserService(*userRepo)
+	ages := age.NewCalculator(age.SystemClock, cfg.AgeLeapDayRule, cfg.AgeLocation)
+	userService := service.NewUserService(*userRepo, ages)
 	userHandler := handler.NewUserHandler(userService, logger.Log)
+	eventFeed := events.NewFeed(repository.NewEventRepository(db), cfg.EventsPollInterval, cfg.EventsGapTimeout, logger.Log)
+	eventHandler := handler.NewEventHandler(eventFeed, logger.Log)
+	webhookRepo := repository.NewWebhookRepository(db)
+	webhookService := service.NewWebhookService(*webhookRepo)
+	webhookHandler := handler.NewWebhookHandler(webhookService, logger.Log)
+	if err := os.MkdirAll(cfg.ExportDir, 0o755); err != nil {
+		logger.Log.Fatal("Failed to create export directory", zap.Error(err))
+	}
+	exportService := service.NewExportJobService(*repository.NewExportJobRepository(db), userService, cfg.ExportDir, cfg.ExportRetention)
+	exportHandler := handler.NewExportHandler(exportService, logger.Log)
+	statsService := service.NewStatsService(userService, cfg.StatsCacheTTL)
+	statsHandler := handler.NewStatsHandler(statsService, logger.Log)
+	idempotencyRepo := repository.NewIdempotencyRepository(db)
+	idempotency := middleware.Idempotency(idempotencyRepo, cfg.IdempotencyTTL, 2*requestTimeout, logger.Log)
+	healthRepo := repository.NewHealthRepository(db)
+	checks := health.NewRegistry(cfg.HealthCheckTimeout, logger.Log)
+	checks.Register("database", healthRepo.Ping)
+	checks.Register("migrations", healthRepo.CheckSchema)
+	healthHandler := handler.NewHealthHandler(checks)
+
+	// Start background jobs; they stop when ctx is cancelled
+	go jobs.RunPurge(ctx, userService, cfg.UserRetention, cfg.PurgeInterval, logger.Log)
+	go jobs.RunIdempotencyPurge(ctx, idempotencyRepo, cfg.PurgeInterval, logger.Log)
+	go jobs.RunExportPurge(ctx, exportService, cfg.PurgeInterval, logger.Log)
+	go jobs.RunOutboxPurge(ctx, webhookRepo, cfg.OutboxRetention, cfg.PurgeInterval, logger.Log)
+	go jobs.RunBirthdayDigest(ctx, userService, cfg.BirthdayDigestInterval, logger.Log)
+	dispatcher := webhook.NewDispatcher(webhookRepo, webhook.Config{
+		PollInterval: cfg.WebhookPollInterval,
+		Timeout:      cfg.WebhookTimeout,
+		MaxAttempts:  cfg.WebhookMaxAttempts,
+		BackoffBase:  cfg.WebhookBackoffBase,
+		BackoffMax:   cfg.WebhookBackoffMax,
+	}, logger.Log)
+	go dispatcher.Run(ctx)
+	// Cancelling ctx also ends open change feed streams, so that the
+	// graceful shutdown below is not held up by them.
+	go eventFeed.Run(ctx)
+	// Export jobs still running at shutdown are requeued, which needs the
+	// database, so they are waited for before it is closed.
+	exportsDone := make(chan struct{})
+	go func() {
+		defer close(exportsDone)
+		jobs.RunExportWorkers(ctx, exportService, cfg.ExportWorkers, cfg.ExportPollInterval, logger.Log)
+	}()
 
 	// Initialize Fiber app with context support
 	app := fiber.New(fiber.Config{
 		AppName:               "User API",
-		ErrorHandler:          errorHandler,
+		ErrorHandler:          middleware.ErrorHandler(logger.Log),
 		DisableStartupMessage: true,
 	})
 
@@ -71,8 +147,10 @@ func main() {
 
 	// Add context to each request
 	app.Use(func(c *fiber.Ctx) error {
-		// Set the context with timeout for each request
-		reqCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+		// Set the context with timeout for each request. It is not cancelled
+		// by the shutdown signal, so that requests answered while draining
+		// and those in flight at shutdown can finish.
+		reqCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), requestTimeout)
 		defer cancel()
 
 		// Set the context in the locals
@@ -85,10 +163,18 @@ func main() {
 	})
 
 	// Setup routes
-	routes.SetupRoutes(app, userHandler, logger.Log)
+	routes.SetupRoutes(app, userHandler, eventHandler, webhookHandler, exportHandler, statsHandler, healthHandler, idempotency, logger.Log)
+
+	// The admin server serves metrics on a port of its own
+	admin := fiber.New(fiber.Config{
+		AppName:               "User API admin",
+		ErrorHandler:          middleware.ErrorHandler(logger.Log),
+		DisableStartupMessage: true,
+	})
+	routes.SetupAdminRoutes(admin)
 
-	// Start server in a goroutine
-	serverShutdown := make(chan error, 1)
+	// Start servers in goroutines
+	serverShutdown := make(chan error, 2)
 	go func() {
 		addr := "0.0.0.0:" + cfg.GetPort()
 		logger.Log.Info("Server starting", zap.String("address", addr))
@@ -96,11 +182,27 @@ func main() {
 			serverShutdown <- fmt.Errorf("server error: %w", err)
 		}
 	}()
+	go func() {
+		addr := net.JoinHostPort(cfg.AdminAddr, cfg.AdminPort)
+		logger.Log.Info("Admin server starting", zap.String("address", addr))
+		if err := admin.Listen(addr); err != nil {
+			serverShutdown <- fmt.Errorf("admin server error: %w", err)
+		}
+	}()
 
 	// Wait for interrupt signal or server error
 	select {
 	case <-ctx.Done():
 		logger.Log.Info("Received shutdown signal")
+		// Keep serving while reporting not ready, until traffic has been
+		// routed elsewhere
+		checks.Drain()
+		logger.Log.Info("Draining", zap.Duration("delay", cfg.ShutdownDrainDelay))
+		select {
+		case <-time.After(cfg.ShutdownDrainDelay):
+		case err := <-serverShutdown:
+			logger.Log.Error("Server error", zap.Error(err))
+		}
 	case err := <-serverShutdown:
 		logger.Log.Error("Server error", zap.Error(err))
 	}
@@ -113,18 +215,27 @@ func main() {
 	if err := app.ShutdownWithContext(shutdownCtx); err != nil {
 		logger.Log.Error("Server forced to shutdown", zap.Error(err))
 	}
+	if err := admin.ShutdownWithContext(shutdownCtx); err != nil {
+		logger.Log.Error("Admin server forced to shutdown", zap.Error(err))
+	}
+
+	// Wait for export workers to requeue their jobs
+	stop()
+	select {
+	case <-exportsDone:
+	case <-shutdownCtx.Done():
+		logger.Log.Warn("Export workers did not stop in time")
+	}
 
 	// Close database connection
 	if err := db.Close(); err != nil {
 		logger.Log.Error("Error closing database connection", zap.Error(err))
 	}
 
-	logger.Log.Info("Server gracefully stopped")
-}
+	// Flush the remaining trace spans
+	if err := shutdownTracing(shutdownCtx); err != nil {
+		logger.Log.Error("Error flushing traces", zap.Error(err))
+	}
 
-func errorHandler(c *fiber.Ctx, err error) error {
-	logger.Log.Error("Unhandled error", zap.Error(err))
-	return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-		"error": "Internal server error",
-	})
+	logger.Log.Info("Server gracefully stopped")
 }
@@ -3,7 +3,10 @@ package config
 import (
 	"fmt"
 	"os"
+	"strconv"
+	"time"
 
+	"github.com/Pallavi566/Go-Backend/internal/age"
 	"github.com/joho/godotenv"
 )
 
@@ -14,19 +17,228 @@ type Config struct {
 	DBPassword string
 	DBName     string
 	ServerPort string
+	// AdminAddr and AdminPort are the address and port the admin server,
+	// which serves /metrics apart from the API, listens on. AdminAddr
+	// defaults to the loopback interface, so that metrics are not exposed.
+	AdminAddr string
+	AdminPort string
+
+	// UserRetention is how long soft-deleted users are kept before the
+	// purge job removes them for good.
+	UserRetention time.Duration
+	// PurgeInterval is how often the purge job runs.
+	PurgeInterval time.Duration
+
+	// WebhookPollInterval is how often the webhook dispatcher polls the
+	// outbox and due deliveries.
+	WebhookPollInterval time.Duration
+	// WebhookTimeout bounds a single webhook delivery attempt.
+	WebhookTimeout time.Duration
+	// WebhookMaxAttempts is the number of attempts after which a delivery
+	// is dead-lettered.
+	WebhookMaxAttempts int
+	// WebhookBackoffBase and WebhookBackoffMax bound the exponential delay
+	// between delivery attempts.
+	WebhookBackoffBase time.Duration
+	WebhookBackoffMax  time.Duration
+	// OutboxRetention is how long dispatched outbox events, and their
+	// webhook deliveries, are kept before the purge job removes them. It
+	// bounds how far back webhook deliveries can be replayed and change
+	// feed clients can resume.
+	OutboxRetention time.Duration
+
+	// EventsPollInterval is how often the change feed polls for new user
+	// events.
+	EventsPollInterval time.Duration
+	// EventsGapTimeout is how long the change feed waits for an event whose
+	// transaction has not committed yet before skipping it, holding back
+	// the events after it meanwhile.
+	EventsGapTimeout time.Duration
+
+	// ExportDir is the directory export jobs write their files to.
+	ExportDir string
+	// ExportWorkers is the number of export jobs run concurrently.
+	ExportWorkers int
+	// ExportPollInterval is how often the export workers poll for queued
+	// jobs.
+	ExportPollInterval time.Duration
+	// ExportRetention is how long finished export jobs, and their files,
+	// are kept before the purge job removes them.
+	ExportRetention time.Duration
+
+	// IdempotencyTTL is how long responses to requests sent with an
+	// Idempotency-Key are replayed for retries.
+	IdempotencyTTL time.Duration
+
+	// BirthdayDigestInterval is how often the birthday digest job checks
+	// whether today's digest has been published.
+	BirthdayDigestInterval time.Duration
+
+	// StatsCacheTTL is how long user statistics are cached for.
+	StatsCacheTTL time.Duration
+
+	// HealthCheckTimeout bounds each dependency check of the readiness
+	// probe.
+	HealthCheckTimeout time.Duration
+	// ShutdownDrainDelay is how long the server keeps serving after being
+	// asked to stop, reporting itself not ready, so that traffic is routed
+	// elsewhere before it stops accepting connections.
+	ShutdownDrainDelay time.Duration
+
+	// TracingExporter is where trace spans are sent: none, otlp or stdout.
+	TracingExporter string
+	// TracingEndpoint is the URL of the OTLP/HTTP collector spans are sent
+	// to by the otlp exporter.
+	TracingEndpoint string
+
+	// AgeLeapDayRule decides whether people born on February 29 turn a
+	// year older on February 28 or March 1 in common years.
+	AgeLeapDayRule age.LeapDayRule
+	// AgeLocation is the time zone ages are computed in for users without
+	// one, and the one age filters are evaluated in.
+	AgeLocation *time.Location
 }
 
 func LoadConfig() (*Config, error) {
 	// Load .env file if it exists
 	_ = godotenv.Load()
 
+	userRetention, err := getDurationEnv("USER_RETENTION", 30*24*time.Hour)
+	if err != nil {
+		return nil, err
+	}
+	purgeInterval, err := getPositiveDurationEnv("PURGE_INTERVAL", time.Hour)
+	if err != nil {
+		return nil, err
+	}
+
+	webhookPollInterval, err := getPositiveDurationEnv("WEBHOOK_POLL_INTERVAL", 5*time.Second)
+	if err != nil {
+		return nil, err
+	}
+	webhookTimeout, err := getPositiveDurationEnv("WEBHOOK_TIMEOUT", 10*time.Second)
+	if err != nil {
+		return nil, err
+	}
+	webhookMaxAttempts, err := getIntEnv("WEBHOOK_MAX_ATTEMPTS", 8)
+	if err != nil {
+		return nil, err
+	}
+	webhookBackoffBase, err := getPositiveDurationEnv("WEBHOOK_BACKOFF_BASE", 30*time.Second)
+	if err != nil {
+		return nil, err
+	}
+	webhookBackoffMax, err := getPositiveDurationEnv("WEBHOOK_BACKOFF_MAX", time.Hour)
+	if err != nil {
+		return nil, err
+	}
+
+	outboxRetention, err := getDurationEnv("OUTBOX_RETENTION", 7*24*time.Hour)
+	if err != nil {
+		return nil, err
+	}
+
+	eventsPollInterval, err := getPositiveDurationEnv("EVENTS_POLL_INTERVAL", time.Second)
+	if err != nil {
+		return nil, err
+	}
+
+	eventsGapTimeout, err := getPositiveDurationEnv("EVENTS_GAP_TIMEOUT", 10*time.Second)
+	if err != nil {
+		return nil, err
+	}
+
+	exportWorkers, err := getIntEnv("EXPORT_WORKERS", 2)
+	if err != nil {
+		return nil, err
+	}
+	if exportWorkers < 1 {
+		return nil, fmt.Errorf("invalid EXPORT_WORKERS: must be at least 1")
+	}
+	exportPollInterval, err := getPositiveDurationEnv("EXPORT_POLL_INTERVAL", 2*time.Second)
+	if err != nil {
+		return nil, err
+	}
+
+	exportRetention, err := getDurationEnv("EXPORT_RETENTION", 24*time.Hour)
+	if err != nil {
+		return nil, err
+	}
+
+	idempotencyTTL, err := getDurationEnv("IDEMPOTENCY_TTL", 24*time.Hour)
+	if err != nil {
+		return nil, err
+	}
+
+	birthdayDigestInterval, err := getPositiveDurationEnv("BIRTHDAY_DIGEST_INTERVAL", 15*time.Minute)
+	if err != nil {
+		return nil, err
+	}
+
+	statsCacheTTL, err := getDurationEnv("STATS_CACHE_TTL", time.Minute)
+	if err != nil {
+		return nil, err
+	}
+
+	healthCheckTimeout, err := getPositiveDurationEnv("HEALTH_CHECK_TIMEOUT", 2*time.Second)
+	if err != nil {
+		return nil, err
+	}
+	shutdownDrainDelay, err := getDurationEnv("SHUTDOWN_DRAIN_DELAY", 5*time.Second)
+	if err != nil {
+		return nil, err
+	}
+
+	ageLeapDayRule, err := age.ParseLeapDayRule(getEnv("AGE_LEAP_DAY_RULE", "feb28"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid AGE_LEAP_DAY_RULE: %w", err)
+	}
+	ageLocation, err := time.LoadLocation(getEnv("AGE_DEFAULT_TIMEZONE", "UTC"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid AGE_DEFAULT_TIMEZONE: %w", err)
+	}
+
 	return &Config{
-		DBHost:     getEnv("DB_HOST", "localhost"),
-		DBPort:     getEnv("DB_PORT", "3306"),
-		DBUser:     getEnv("DB_USER", "user"),
-		DBPassword: getEnv("DB_PASSWORD", "password"),
-		DBName:     getEnv("DB_NAME", "userdb"),
-		ServerPort: getEnv("SERVER_PORT", "8080"),
+		DBHost:        getEnv("DB_HOST", "localhost"),
+		DBPort:        getEnv("DB_PORT", "3306"),
+		DBUser:        getEnv("DB_USER", "user"),
+		DBPassword:    getEnv("DB_PASSWORD", "password"),
+		DBName:        getEnv("DB_NAME", "userdb"),
+		ServerPort:    getEnv("SERVER_PORT", "8080"),
+		AdminAddr:     getEnv("ADMIN_ADDR", "127.0.0.1"),
+		AdminPort:     getEnv("ADMIN_PORT", "9090"),
+		UserRetention: userRetention,
+		PurgeInterval: purgeInterval,
+
+		WebhookPollInterval: webhookPollInterval,
+		WebhookTimeout:      webhookTimeout,
+		WebhookMaxAttempts:  webhookMaxAttempts,
+		WebhookBackoffBase:  webhookBackoffBase,
+		WebhookBackoffMax:   webhookBackoffMax,
+		OutboxRetention:     outboxRetention,
+
+		EventsPollInterval: eventsPollInterval,
+		EventsGapTimeout:   eventsGapTimeout,
+
+		ExportDir:          getEnv("EXPORT_DIR", "./exports"),
+		ExportWorkers:      exportWorkers,
+		ExportPollInterval: exportPollInterval,
+		ExportRetention:    exportRetention,
+
+		IdempotencyTTL: idempotencyTTL,
+
+		BirthdayDigestInterval: birthdayDigestInterval,
+
+		StatsCacheTTL: statsCacheTTL,
+
+		HealthCheckTimeout: healthCheckTimeout,
+		ShutdownDrainDelay: shutdownDrainDelay,
+
+		TracingExporter: getEnv("TRACING_EXPORTER", "none"),
+		TracingEndpoint: getEnv("TRACING_OTLP_ENDPOINT", ""),
+
+		AgeLeapDayRule: ageLeapDayRule,
+		AgeLocation:    ageLocation,
 	}, nil
 }
 
@@ -37,6 +249,45 @@ func getEnv(key, defaultValue string) string {
 	return defaultValue
 }
 
+// getDurationEnv reads a duration such as "720h" from the environment.
+func getDurationEnv(key string, defaultValue time.Duration) (time.Duration, error) {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue, nil
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s: %w", key, err)
+	}
+	return d, nil
+}
+
+// getPositiveDurationEnv reads a duration that must be greater than zero,
+// such as the interval of a ticker, from the environment.
+func getPositiveDurationEnv(key string, defaultValue time.Duration) (time.Duration, error) {
+	d, err := getDurationEnv(key, defaultValue)
+	if err != nil {
+		return 0, err
+	}
+	if d <= 0 {
+		return 0, fmt.Errorf("invalid %s: must be greater than zero", key)
+	}
+	return d, nil
+}
+
+// getIntEnv reads an integer from the environment.
+func getIntEnv(key string, defaultValue int) (int, error) {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue, nil
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s: %w", key, err)
+	}
+	return n, nil
+}
+
 func (c *Config) GetDSN() string {
 	return fmt.Sprintf("%s:%s@tcp(%s:%s)/%s?parseTime=true",
 		c.DBUser,
@@ -0,0 +1,2 @@
+-- Row version used for optimistic concurrency control (ETag / If-Match).
+ALTER TABLE users ADD COLUMN version INT NOT NULL DEFAULT 1;
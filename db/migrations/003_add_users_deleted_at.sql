@@ -0,0 +1,5 @@
+-- Soft delete: rows with deleted_at set are hidden from regular reads and
+-- hard-deleted by the purge job once the retention period has passed.
+ALTER TABLE users
+    ADD COLUMN deleted_at TIMESTAMP NULL DEFAULT NULL,
+    ADD INDEX idx_users_deleted_at (deleted_at);
@@ -0,0 +1,14 @@
+-- Change history of users. Each row holds the state before and after a
+-- change along with who made it. Rows are kept after the user is purged.
+CREATE TABLE IF NOT EXISTS user_history (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    user_id INT NOT NULL,
+    action VARCHAR(16) NOT NULL,
+    version INT NOT NULL,
+    before_data JSON NULL,
+    after_data JSON NULL,
+    actor VARCHAR(255) NOT NULL,
+    request_id VARCHAR(64) NOT NULL DEFAULT '',
+    changed_at TIMESTAMP(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
+    INDEX idx_user_history_user_changed (user_id, changed_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
@@ -0,0 +1,20 @@
+-- Seed the history of users that existed before it was recorded, so they
+-- can be read "as of" any time since their last change.
+INSERT INTO user_history (user_id, action, version, before_data, after_data, actor, request_id, changed_at)
+SELECT
+    u.id,
+    'backfilled',
+    u.version,
+    NULL,
+    JSON_OBJECT(
+        'id', u.id,
+        'name', u.name,
+        'dob', DATE_FORMAT(u.dob, '%Y-%m-%d'),
+        'version', u.version,
+        'deleted', IF(u.deleted_at IS NULL, CAST('false' AS JSON), CAST('true' AS JSON))
+    ),
+    'migration',
+    '',
+    COALESCE(u.updated_at, u.created_at, CURRENT_TIMESTAMP(6))
+FROM users u
+WHERE NOT EXISTS (SELECT 1 FROM user_history h WHERE h.user_id = u.id);
@@ -0,0 +1,23 @@
+-- Asynchronous user exports. Workers claim queued jobs and write the file
+-- to the export directory; running jobs refresh heartbeat_at so that jobs
+-- orphaned by a crash can be requeued.
+CREATE TABLE IF NOT EXISTS export_jobs (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    -- queued, running, succeeded or failed
+    status VARCHAR(16) NOT NULL DEFAULT 'queued',
+    format VARCHAR(16) NOT NULL,
+    filters JSON NOT NULL,
+    total_rows BIGINT NULL,
+    processed_rows BIGINT NOT NULL DEFAULT 0,
+    file_path VARCHAR(1024) NULL,
+    file_size BIGINT NULL,
+    error TEXT NULL,
+    requested_by VARCHAR(255) NOT NULL,
+    request_id VARCHAR(64) NOT NULL DEFAULT '',
+    attempts INT NOT NULL DEFAULT 0,
+    created_at TIMESTAMP(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
+    started_at TIMESTAMP(6) NULL DEFAULT NULL,
+    heartbeat_at TIMESTAMP(6) NULL DEFAULT NULL,
+    finished_at TIMESTAMP(6) NULL DEFAULT NULL,
+    INDEX idx_export_jobs_status (status, id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
@@ -0,0 +1,17 @@
+-- Responses to requests sent with an Idempotency-Key header, replayed when
+-- the request is retried. A row without a status_code is a request still in
+-- flight.
+CREATE TABLE IF NOT EXISTS idempotency_keys (
+    -- actor, method and path the key was used for
+    scope VARCHAR(512) NOT NULL,
+    idempotency_key VARCHAR(255) NOT NULL,
+    -- SHA-256 of the request, to detect a key reused for another payload
+    request_hash CHAR(64) NOT NULL,
+    status_code INT NULL,
+    content_type VARCHAR(255) NULL,
+    response_body MEDIUMBLOB NULL,
+    created_at TIMESTAMP(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
+    expires_at TIMESTAMP(6) NOT NULL,
+    PRIMARY KEY (scope, idempotency_key),
+    INDEX idx_idempotency_keys_expires_at (expires_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
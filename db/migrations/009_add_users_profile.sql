@@ -0,0 +1,8 @@
+-- Contact and localisation details. All are optional; emails are unique
+-- among all users, including soft-deleted ones, and stored lower-cased.
+ALTER TABLE users
+    ADD COLUMN email VARCHAR(254) NULL DEFAULT NULL,
+    ADD COLUMN phone VARCHAR(16) NULL DEFAULT NULL,
+    ADD COLUMN timezone VARCHAR(64) NULL DEFAULT NULL,
+    ADD COLUMN locale VARCHAR(35) NULL DEFAULT NULL,
+    ADD UNIQUE INDEX uq_users_email (email);
@@ -0,0 +1,18 @@
+-- Compliance log of age lookups and age-gating checks. Each row records the
+-- date of birth the answer was computed from, so that it can be reproduced,
+-- and who asked. Rows are kept after the user is purged.
+CREATE TABLE IF NOT EXISTS age_checks (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    user_id INT NOT NULL,
+    dob DATE NOT NULL,
+    as_of DATE NOT NULL,
+    age INT NOT NULL,
+    -- NULL for plain age lookups
+    min_age INT NULL,
+    passed BOOLEAN NULL,
+    leap_day_rule VARCHAR(8) NOT NULL,
+    actor VARCHAR(255) NOT NULL,
+    request_id VARCHAR(64) NOT NULL DEFAULT '',
+    checked_at TIMESTAMP(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
+    INDEX idx_age_checks_user_checked (user_id, checked_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
@@ -0,0 +1,16 @@
+-- Birthday lookups match on the month and day of dob whatever the year,
+-- which an index on dob cannot serve. birthday holds them as MMDD (e.g. 1231
+-- for December 31) in a stored generated column so that a birthday window
+-- becomes one or two index range scans.
+ALTER TABLE users
+    ADD COLUMN birthday SMALLINT AS (MONTH(dob) * 100 + DAY(dob)) STORED,
+    ADD INDEX idx_users_birthday (birthday, id);
+
+-- One row per day a birthday digest was published, claimed in the
+-- transaction publishing it so that each day's digest goes out once however
+-- many servers run the job.
+CREATE TABLE IF NOT EXISTS birthday_digests (
+    digest_date DATE PRIMARY KEY,
+    user_count INT NOT NULL,
+    created_at TIMESTAMP(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
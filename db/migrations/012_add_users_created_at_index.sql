@@ -0,0 +1,2 @@
+-- Signup statistics count users by creation date over a recent period.
+ALTER TABLE users ADD INDEX idx_users_created_at (created_at);
@@ -0,0 +1,5 @@
+-- Headers of stored responses that are replayed with them, such as the
+-- Location and ETag of a created user.
+ALTER TABLE idempotency_keys ADD COLUMN response_headers JSON NULL AFTER content_type;
+
+INSERT IGNORE INTO schema_migrations (version) VALUES (14);
@@ -0,0 +1,3 @@
+-- name: CreateAgeCheck :exec
+INSERT INTO age_checks (user_id, dob, as_of, age, min_age, passed, leap_day_rule, actor, request_id)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?);
@@ -0,0 +1,5 @@
+-- name: ClaimBirthdayDigest :execrows
+INSERT IGNORE INTO birthday_digests (digest_date, user_count) VALUES (?, ?);
+
+-- name: BirthdayDigestExists :one
+SELECT EXISTS(SELECT 1 FROM birthday_digests WHERE digest_date = ?);
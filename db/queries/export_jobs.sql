@@ -0,0 +1,55 @@
+-- name: CreateExportJob :execresult
+INSERT INTO export_jobs (format, filters, requested_by, request_id) VALUES (?, ?, ?, ?);
+
+-- name: GetExportJob :one
+SELECT id, status, format, filters, total_rows, processed_rows, file_path, file_size, error,
+       requested_by, request_id, attempts, created_at, started_at, heartbeat_at, finished_at
+FROM export_jobs
+WHERE id = ? LIMIT 1;
+
+-- name: ListQueuedExportJobIDs :many
+SELECT id FROM export_jobs WHERE status = 'queued' ORDER BY id LIMIT ?;
+
+-- name: ClaimExportJob :execrows
+UPDATE export_jobs
+SET status = 'running', attempts = attempts + 1, started_at = ?, heartbeat_at = ?,
+    processed_rows = 0, error = NULL
+WHERE id = ? AND status = 'queued';
+
+-- name: UpdateExportJobProgress :execrows
+UPDATE export_jobs SET total_rows = ?, processed_rows = ?, heartbeat_at = ?
+WHERE id = ? AND status = 'running' AND attempts = ?;
+
+-- name: HeartbeatExportJob :execrows
+UPDATE export_jobs SET processed_rows = ?, heartbeat_at = ?
+WHERE id = ? AND status = 'running' AND attempts = ?;
+
+-- name: CompleteExportJob :execrows
+UPDATE export_jobs
+SET status = 'succeeded', processed_rows = ?, file_path = ?, file_size = ?, finished_at = ?
+WHERE id = ? AND status = 'running' AND attempts = ?;
+
+-- name: FailExportJob :execrows
+UPDATE export_jobs SET status = 'failed', error = ?, finished_at = ?
+WHERE id = ? AND status = 'running' AND attempts = ?;
+
+-- name: RequeueExportJob :execrows
+UPDATE export_jobs SET status = 'queued', heartbeat_at = NULL
+WHERE id = ? AND status = 'running' AND attempts = ?;
+
+-- name: RequeueStaleExportJobs :execrows
+UPDATE export_jobs SET status = 'queued', heartbeat_at = NULL
+WHERE status = 'running' AND heartbeat_at < ? AND attempts < ?;
+
+-- name: FailStaleExportJobs :execrows
+UPDATE export_jobs SET status = 'failed', error = ?, finished_at = ?
+WHERE status = 'running' AND heartbeat_at < ? AND attempts >= ?;
+
+-- name: ListExpiredExportJobs :many
+SELECT id, file_path FROM export_jobs
+WHERE status IN ('succeeded', 'failed') AND finished_at < ?
+ORDER BY id
+LIMIT ?;
+
+-- name: DeleteExportJob :exec
+DELETE FROM export_jobs WHERE id = ?;
@@ -0,0 +1,22 @@
+-- name: ReserveIdempotencyKey :execrows
+INSERT IGNORE INTO idempotency_keys (scope, idempotency_key, request_hash, expires_at)
+VALUES (?, ?, ?, ?);
+
+-- name: GetIdempotencyKey :one
+SELECT scope, idempotency_key, request_hash, status_code, content_type, response_headers, response_body, created_at, expires_at
+FROM idempotency_keys
+WHERE scope = ? AND idempotency_key = ? LIMIT 1;
+
+-- name: CompleteIdempotencyKey :exec
+UPDATE idempotency_keys
+SET status_code = ?, content_type = ?, response_headers = ?, response_body = ?, expires_at = ?
+WHERE scope = ? AND idempotency_key = ?;
+
+-- name: DeleteIdempotencyKey :exec
+DELETE FROM idempotency_keys WHERE scope = ? AND idempotency_key = ?;
+
+-- name: DeleteExpiredIdempotencyKey :exec
+DELETE FROM idempotency_keys WHERE scope = ? AND idempotency_key = ? AND expires_at <= ?;
+
+-- name: PurgeExpiredIdempotencyKeys :execrows
+DELETE FROM idempotency_keys WHERE expires_at <= ? LIMIT ?;
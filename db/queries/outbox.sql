@@ -0,0 +1,31 @@
+-- name: CreateOutboxEvent :execresult
+INSERT INTO outbox_events (event_type, aggregate_id, payload) VALUES (?, ?, ?);
+
+-- name: ListUndispatchedOutboxEvents :many
+SELECT id, event_type, aggregate_id, payload, created_at, dispatched_at
+FROM outbox_events
+WHERE dispatched_at IS NULL
+ORDER BY id
+LIMIT ?;
+
+-- name: MarkOutboxEventDispatched :exec
+UPDATE outbox_events SET dispatched_at = CURRENT_TIMESTAMP(6) WHERE id = ?;
+
+-- name: ListOutboxEventsAfter :many
+SELECT id, event_type, aggregate_id, payload, created_at, dispatched_at
+FROM outbox_events
+WHERE id > ?
+ORDER BY id
+LIMIT ?;
+
+-- name: GetLatestOutboxEventID :one
+SELECT id FROM outbox_events ORDER BY id DESC LIMIT 1;
+
+-- name: PurgeDispatchedOutboxEvents :execrows
+DELETE FROM outbox_events
+WHERE dispatched_at < ?
+  AND NOT EXISTS (
+    SELECT 1 FROM webhook_deliveries d
+    WHERE d.event_id = outbox_events.id AND d.status = 'pending'
+  )
+LIMIT ?;
@@ -0,0 +1,16 @@
+-- name: CreateUserHistory :exec
+INSERT INTO user_history (user_id, action, version, before_data, after_data, actor, request_id)
+VALUES (?, ?, ?, ?, ?, ?, ?);
+
+-- name: GetUserHistoryAsOf :one
+SELECT id, user_id, action, version, before_data, after_data, actor, request_id, changed_at
+FROM user_history
+WHERE user_id = ? AND changed_at <= ?
+ORDER BY changed_at DESC, id DESC
+LIMIT 1;
+
+-- name: ListUserHistory :many
+SELECT id, user_id, action, version, before_data, after_data, actor, request_id, changed_at
+FROM user_history
+WHERE user_id = ?
+ORDER BY id;
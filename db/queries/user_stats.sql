@@ -0,0 +1,35 @@
+-- name: CountActiveUsersByAge :many
+SELECT CAST(GREATEST(0, sqlc.arg(year) - YEAR(dob) - (IF(birthday = 229, sqlc.arg(leap_birthday), birthday) > sqlc.arg(today))) AS SIGNED) AS age,
+       COUNT(*) AS count
+FROM users
+WHERE deleted_at IS NULL
+GROUP BY age
+ORDER BY age;
+
+-- name: CountActiveUsersByBirthDecade :many
+SELECT CAST(FLOOR(YEAR(dob) / 10) * 10 AS SIGNED) AS decade, COUNT(*) AS count
+FROM users
+WHERE deleted_at IS NULL
+GROUP BY decade
+ORDER BY decade;
+
+-- name: CountSignupsByDay :many
+SELECT DATE(created_at) AS period, COUNT(*) AS count
+FROM users
+WHERE created_at >= ?
+GROUP BY period
+ORDER BY period;
+
+-- name: CountSignupsByWeek :many
+SELECT DATE(DATE_SUB(created_at, INTERVAL WEEKDAY(created_at) DAY)) AS period, COUNT(*) AS count
+FROM users
+WHERE created_at >= ?
+GROUP BY period
+ORDER BY period;
+
+-- name: CountSignupsByMonth :many
+SELECT DATE(DATE_FORMAT(created_at, '%Y-%m-01')) AS period, COUNT(*) AS count
+FROM users
+WHERE created_at >= ?
+GROUP BY period
+ORDER BY period;
@@ -1,22 +1,37 @@
 -- name: CreateUser :execresult
-INSERT INTO users (name, dob) VALUES (?, ?);
+INSERT INTO users (name, dob, email, phone, timezone, locale) VALUES (?, ?, ?, ?, ?, ?);
 
 -- name: GetUserByID :one
-SELECT id, name, dob FROM users WHERE id = ? LIMIT 1;
+SELECT id, name, dob, email, phone, timezone, locale, version FROM users WHERE id = ? AND deleted_at IS NULL LIMIT 1;
+
+-- name: GetUserByEmail :one
+SELECT id, name, dob, email, phone, timezone, locale, version FROM users WHERE email = ? AND deleted_at IS NULL LIMIT 1;
+
+-- name: GetUserByIDForUpdate :one
+SELECT id, name, dob, email, phone, timezone, locale, version FROM users WHERE id = ? AND deleted_at IS NULL LIMIT 1 FOR UPDATE;
+
+-- name: GetDeletedUserByIDForUpdate :one
+SELECT id, name, dob, email, phone, timezone, locale, version, deleted_at FROM users WHERE id = ? AND deleted_at IS NOT NULL LIMIT 1 FOR UPDATE;
 
 -- name: GetAllUsers :many
-SELECT id, name, dob FROM users ORDER BY id;
+SELECT id, name, dob, email, phone, timezone, locale, version FROM users WHERE deleted_at IS NULL ORDER BY id;
 
--- name: UpdateUser :exec
-UPDATE users SET name = ?, dob = ? WHERE id = ?;
+-- name: UpdateUser :execresult
+UPDATE users
+SET name = ?, dob = ?, email = ?, phone = ?, timezone = ?, locale = ?, version = version + 1
+WHERE id = ? AND version = ? AND deleted_at IS NULL;
 
--- name: DeleteUser :exec
-DELETE FROM users WHERE id = ?;
+-- name: DeleteUser :execresult
+UPDATE users SET deleted_at = NOW(), version = version + 1 WHERE id = ? AND deleted_at IS NULL;
 
--- name: GetUsersPaginated :many
-SELECT id, name, dob FROM users ORDER BY id LIMIT ? OFFSET ?;
+-- name: RestoreUser :execresult
+UPDATE users SET deleted_at = NULL, version = version + 1 WHERE id = ? AND deleted_at IS NOT NULL;
 
--- name: CountUsers :one
-SELECT COUNT(*) FROM users;
+-- name: PurgeDeletedUsers :execrows
+DELETE FROM users WHERE deleted_at IS NOT NULL AND deleted_at < ?;
 
+-- name: GetUsersPaginated :many
+SELECT id, name, dob FROM users WHERE deleted_at IS NULL ORDER BY id LIMIT ? OFFSET ?;
 
+-- name: CountUsers :one
+SELECT COUNT(*) FROM users WHERE deleted_at IS NULL;
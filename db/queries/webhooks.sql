@@ -0,0 +1,67 @@
+-- name: CreateWebhookSubscription :execresult
+INSERT INTO webhook_subscriptions (url, secret, event_types) VALUES (?, ?, ?);
+
+-- name: GetWebhookSubscription :one
+SELECT id, url, secret, event_types, active, created_at, updated_at
+FROM webhook_subscriptions
+WHERE id = ? LIMIT 1;
+
+-- name: ListWebhookSubscriptions :many
+SELECT id, url, secret, event_types, active, created_at, updated_at
+FROM webhook_subscriptions
+ORDER BY id;
+
+-- name: ListActiveWebhookSubscriptions :many
+SELECT id, url, secret, event_types, active, created_at, updated_at
+FROM webhook_subscriptions
+WHERE active = TRUE
+ORDER BY id;
+
+-- name: SetWebhookSubscriptionActive :execresult
+UPDATE webhook_subscriptions SET active = ? WHERE id = ?;
+
+-- name: CreateWebhookDelivery :exec
+INSERT IGNORE INTO webhook_deliveries (event_id, subscription_id) VALUES (?, ?);
+
+-- name: ListDueWebhookDeliveries :many
+SELECT d.id, d.event_id, d.subscription_id, d.attempts, d.next_attempt_at,
+       e.event_type, e.payload, e.created_at AS event_created_at,
+       s.url, s.secret
+FROM webhook_deliveries d
+JOIN outbox_events e ON e.id = d.event_id
+JOIN webhook_subscriptions s ON s.id = d.subscription_id
+WHERE d.status = 'pending' AND d.next_attempt_at <= ? AND s.active = TRUE
+ORDER BY d.next_attempt_at, d.id
+LIMIT ?;
+
+-- name: ClaimWebhookDelivery :execrows
+UPDATE webhook_deliveries SET next_attempt_at = ?
+WHERE id = ? AND status = 'pending' AND next_attempt_at = ?;
+
+-- name: MarkWebhookDeliveryDelivered :exec
+UPDATE webhook_deliveries
+SET status = 'delivered', attempts = attempts + 1, last_status_code = ?, last_error = NULL,
+    delivered_at = CURRENT_TIMESTAMP(6)
+WHERE id = ?;
+
+-- name: MarkWebhookDeliveryFailed :exec
+UPDATE webhook_deliveries
+SET status = ?, attempts = attempts + 1, last_status_code = ?, last_error = ?, next_attempt_at = ?
+WHERE id = ?;
+
+-- name: ListWebhookDeliveries :many
+SELECT id, event_id, subscription_id, status, attempts, next_attempt_at, last_status_code, last_error, delivered_at, created_at
+FROM webhook_deliveries
+WHERE subscription_id = ?
+ORDER BY id DESC
+LIMIT ?;
+
+-- name: ReplayDeadWebhookDeliveries :execrows
+UPDATE webhook_deliveries
+SET status = 'pending', attempts = 0, next_attempt_at = CURRENT_TIMESTAMP(6)
+WHERE subscription_id = ? AND status = 'dead';
+
+-- name: ReplayWebhookDeliveriesSince :execrows
+UPDATE webhook_deliveries
+SET status = 'pending', attempts = 0, next_attempt_at = CURRENT_TIMESTAMP(6)
+WHERE subscription_id = ? AND created_at >= ?;
@@ -6,13 +6,121 @@ package sqlc
 
 import (
 	"database/sql"
+	"encoding/json"
 	"time"
 )
 
+type AgeCheck struct {
+	ID          int64         `json:"id"`
+	UserID      int32         `json:"user_id"`
+	Dob         time.Time     `json:"dob"`
+	AsOf        time.Time     `json:"as_of"`
+	Age         int32         `json:"age"`
+	MinAge      sql.NullInt32 `json:"min_age"`
+	Passed      sql.NullBool  `json:"passed"`
+	LeapDayRule string        `json:"leap_day_rule"`
+	Actor       string        `json:"actor"`
+	RequestID   string        `json:"request_id"`
+	CheckedAt   time.Time     `json:"checked_at"`
+}
+
+type BirthdayDigest struct {
+	DigestDate time.Time `json:"digest_date"`
+	UserCount  int32     `json:"user_count"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+type ExportJob struct {
+	ID            int64           `json:"id"`
+	Status        string          `json:"status"`
+	Format        string          `json:"format"`
+	Filters       json.RawMessage `json:"filters"`
+	TotalRows     sql.NullInt64   `json:"total_rows"`
+	ProcessedRows int64           `json:"processed_rows"`
+	FilePath      sql.NullString  `json:"file_path"`
+	FileSize      sql.NullInt64   `json:"file_size"`
+	Error         sql.NullString  `json:"error"`
+	RequestedBy   string          `json:"requested_by"`
+	RequestID     string          `json:"request_id"`
+	Attempts      int32           `json:"attempts"`
+	CreatedAt     time.Time       `json:"created_at"`
+	StartedAt     sql.NullTime    `json:"started_at"`
+	HeartbeatAt   sql.NullTime    `json:"heartbeat_at"`
+	FinishedAt    sql.NullTime    `json:"finished_at"`
+}
+
+type IdempotencyKey struct {
+	Scope           string          `json:"scope"`
+	IdempotencyKey  string          `json:"idempotency_key"`
+	RequestHash     string          `json:"request_hash"`
+	StatusCode      sql.NullInt32   `json:"status_code"`
+	ContentType     sql.NullString  `json:"content_type"`
+	ResponseHeaders json.RawMessage `json:"response_headers"`
+	ResponseBody    []byte          `json:"response_body"`
+	CreatedAt       time.Time       `json:"created_at"`
+	ExpiresAt       time.Time       `json:"expires_at"`
+}
+
+type OutboxEvent struct {
+	ID           int64           `json:"id"`
+	EventType    string          `json:"event_type"`
+	AggregateID  int32           `json:"aggregate_id"`
+	Payload      json.RawMessage `json:"payload"`
+	CreatedAt    time.Time       `json:"created_at"`
+	DispatchedAt sql.NullTime    `json:"dispatched_at"`
+}
+
+type SchemaMigration struct {
+	Version   int32     `json:"version"`
+	AppliedAt time.Time `json:"applied_at"`
+}
+
 type User struct {
-	ID        int32        `json:"id"`
-	Name      string       `json:"name"`
-	Dob       time.Time    `json:"dob"`
-	CreatedAt sql.NullTime `json:"created_at"`
-	UpdatedAt sql.NullTime `json:"updated_at"`
+	ID        int32          `json:"id"`
+	Name      string         `json:"name"`
+	Dob       time.Time      `json:"dob"`
+	CreatedAt sql.NullTime   `json:"created_at"`
+	UpdatedAt sql.NullTime   `json:"updated_at"`
+	Version   int32          `json:"version"`
+	DeletedAt sql.NullTime   `json:"deleted_at"`
+	Email     sql.NullString `json:"email"`
+	Phone     sql.NullString `json:"phone"`
+	Timezone  sql.NullString `json:"timezone"`
+	Locale    sql.NullString `json:"locale"`
+	Birthday  sql.NullInt16  `json:"birthday"`
+}
+
+type UserHistory struct {
+	ID         int64           `json:"id"`
+	UserID     int32           `json:"user_id"`
+	Action     string          `json:"action"`
+	Version    int32           `json:"version"`
+	BeforeData json.RawMessage `json:"before_data"`
+	AfterData  json.RawMessage `json:"after_data"`
+	Actor      string          `json:"actor"`
+	RequestID  string          `json:"request_id"`
+	ChangedAt  time.Time       `json:"changed_at"`
+}
+
+type WebhookDelivery struct {
+	ID             int64          `json:"id"`
+	EventID        int64          `json:"event_id"`
+	SubscriptionID int32          `json:"subscription_id"`
+	Status         string         `json:"status"`
+	Attempts       int32          `json:"attempts"`
+	NextAttemptAt  time.Time      `json:"next_attempt_at"`
+	LastStatusCode sql.NullInt32  `json:"last_status_code"`
+	LastError      sql.NullString `json:"last_error"`
+	DeliveredAt    sql.NullTime   `json:"delivered_at"`
+	CreatedAt      time.Time      `json:"created_at"`
+}
+
+type WebhookSubscription struct {
+	ID         int32        `json:"id"`
+	Url        string       `json:"url"`
+	Secret     string       `json:"secret"`
+	EventTypes string       `json:"event_types"`
+	Active     bool         `json:"active"`
+	CreatedAt  sql.NullTime `json:"created_at"`
+	UpdatedAt  sql.NullTime `json:"updated_at"`
 }
@@ -7,16 +7,72 @@ package sqlc
 import (
 	"context"
 	"database/sql"
+	"time"
 )
 
 type Querier interface {
+	BirthdayDigestExists(ctx context.Context, digestDate time.Time) (bool, error)
+	ClaimBirthdayDigest(ctx context.Context, arg ClaimBirthdayDigestParams) (int64, error)
+	ClaimExportJob(ctx context.Context, arg ClaimExportJobParams) (int64, error)
+	ClaimWebhookDelivery(ctx context.Context, arg ClaimWebhookDeliveryParams) (int64, error)
+	CompleteExportJob(ctx context.Context, arg CompleteExportJobParams) (int64, error)
+	CompleteIdempotencyKey(ctx context.Context, arg CompleteIdempotencyKeyParams) error
+	CountActiveUsersByAge(ctx context.Context, arg CountActiveUsersByAgeParams) ([]CountActiveUsersByAgeRow, error)
+	CountActiveUsersByBirthDecade(ctx context.Context) ([]CountActiveUsersByBirthDecadeRow, error)
+	CountSignupsByDay(ctx context.Context, createdAt time.Time) ([]CountSignupsByDayRow, error)
+	CountSignupsByMonth(ctx context.Context, createdAt time.Time) ([]CountSignupsByMonthRow, error)
+	CountSignupsByWeek(ctx context.Context, createdAt time.Time) ([]CountSignupsByWeekRow, error)
 	CountUsers(ctx context.Context) (int64, error)
+	CreateAgeCheck(ctx context.Context, arg CreateAgeCheckParams) error
+	CreateExportJob(ctx context.Context, arg CreateExportJobParams) (sql.Result, error)
+	CreateOutboxEvent(ctx context.Context, arg CreateOutboxEventParams) (sql.Result, error)
 	CreateUser(ctx context.Context, arg CreateUserParams) (sql.Result, error)
-	DeleteUser(ctx context.Context, id int32) error
+	CreateUserHistory(ctx context.Context, arg CreateUserHistoryParams) error
+	CreateWebhookDelivery(ctx context.Context, arg CreateWebhookDeliveryParams) error
+	CreateWebhookSubscription(ctx context.Context, arg CreateWebhookSubscriptionParams) (sql.Result, error)
+	DeleteExpiredIdempotencyKey(ctx context.Context, arg DeleteExpiredIdempotencyKeyParams) error
+	DeleteExportJob(ctx context.Context, id int64) error
+	DeleteIdempotencyKey(ctx context.Context, arg DeleteIdempotencyKeyParams) error
+	DeleteUser(ctx context.Context, id int32) (sql.Result, error)
+	FailExportJob(ctx context.Context, arg FailExportJobParams) (int64, error)
+	FailStaleExportJobs(ctx context.Context, arg FailStaleExportJobsParams) (int64, error)
 	GetAllUsers(ctx context.Context) ([]GetAllUsersRow, error)
+	GetDeletedUserByIDForUpdate(ctx context.Context, id int32) (GetDeletedUserByIDForUpdateRow, error)
+	GetExportJob(ctx context.Context, id int64) (ExportJob, error)
+	GetIdempotencyKey(ctx context.Context, arg GetIdempotencyKeyParams) (IdempotencyKey, error)
+	GetLatestOutboxEventID(ctx context.Context) (int64, error)
+	GetSchemaVersion(ctx context.Context) (int32, error)
+	GetUserByEmail(ctx context.Context, email sql.NullString) (GetUserByEmailRow, error)
 	GetUserByID(ctx context.Context, id int32) (GetUserByIDRow, error)
+	GetUserByIDForUpdate(ctx context.Context, id int32) (GetUserByIDForUpdateRow, error)
+	GetUserHistoryAsOf(ctx context.Context, arg GetUserHistoryAsOfParams) (UserHistory, error)
 	GetUsersPaginated(ctx context.Context, arg GetUsersPaginatedParams) ([]GetUsersPaginatedRow, error)
-	UpdateUser(ctx context.Context, arg UpdateUserParams) error
+	GetWebhookSubscription(ctx context.Context, id int32) (WebhookSubscription, error)
+	HeartbeatExportJob(ctx context.Context, arg HeartbeatExportJobParams) (int64, error)
+	ListActiveWebhookSubscriptions(ctx context.Context) ([]WebhookSubscription, error)
+	ListDueWebhookDeliveries(ctx context.Context, arg ListDueWebhookDeliveriesParams) ([]ListDueWebhookDeliveriesRow, error)
+	ListExpiredExportJobs(ctx context.Context, arg ListExpiredExportJobsParams) ([]ListExpiredExportJobsRow, error)
+	ListOutboxEventsAfter(ctx context.Context, arg ListOutboxEventsAfterParams) ([]OutboxEvent, error)
+	ListQueuedExportJobIDs(ctx context.Context, limit int32) ([]int64, error)
+	ListUndispatchedOutboxEvents(ctx context.Context, limit int32) ([]OutboxEvent, error)
+	ListUserHistory(ctx context.Context, userID int32) ([]UserHistory, error)
+	ListWebhookDeliveries(ctx context.Context, arg ListWebhookDeliveriesParams) ([]WebhookDelivery, error)
+	ListWebhookSubscriptions(ctx context.Context) ([]WebhookSubscription, error)
+	MarkOutboxEventDispatched(ctx context.Context, id int64) error
+	MarkWebhookDeliveryDelivered(ctx context.Context, arg MarkWebhookDeliveryDeliveredParams) error
+	MarkWebhookDeliveryFailed(ctx context.Context, arg MarkWebhookDeliveryFailedParams) error
+	PurgeDeletedUsers(ctx context.Context, deletedAt sql.NullTime) (int64, error)
+	PurgeDispatchedOutboxEvents(ctx context.Context, arg PurgeDispatchedOutboxEventsParams) (int64, error)
+	PurgeExpiredIdempotencyKeys(ctx context.Context, arg PurgeExpiredIdempotencyKeysParams) (int64, error)
+	ReplayDeadWebhookDeliveries(ctx context.Context, subscriptionID int32) (int64, error)
+	ReplayWebhookDeliveriesSince(ctx context.Context, arg ReplayWebhookDeliveriesSinceParams) (int64, error)
+	RequeueExportJob(ctx context.Context, arg RequeueExportJobParams) (int64, error)
+	RequeueStaleExportJobs(ctx context.Context, arg RequeueStaleExportJobsParams) (int64, error)
+	ReserveIdempotencyKey(ctx context.Context, arg ReserveIdempotencyKeyParams) (int64, error)
+	RestoreUser(ctx context.Context, id int32) (sql.Result, error)
+	SetWebhookSubscriptionActive(ctx context.Context, arg SetWebhookSubscriptionActiveParams) (sql.Result, error)
+	UpdateExportJobProgress(ctx context.Context, arg UpdateExportJobProgressParams) (int64, error)
+	UpdateUser(ctx context.Context, arg UpdateUserParams) (sql.Result, error)
 }
 
 var _ Querier = (*Queries)(nil)
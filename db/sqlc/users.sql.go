@@ -12,7 +12,7 @@ import (
 )
 
 const countUsers = `-- name: CountUsers :one
-SELECT COUNT(*) FROM users
+SELECT COUNT(*) FROM users WHERE deleted_at IS NULL
 `
 
 func (q *Queries) CountUsers(ctx context.Context) (int64, error) {
@@ -23,35 +23,50 @@ func (q *Queries) CountUsers(ctx context.Context) (int64, error) {
 }
 
 const createUser = `-- name: CreateUser :execresult
-INSERT INTO users (name, dob) VALUES (?, ?)
+INSERT INTO users (name, dob, email, phone, timezone, locale) VALUES (?, ?, ?, ?, ?, ?)
 `
 
 type CreateUserParams struct {
-	Name string    `json:"name"`
-	Dob  time.Time `json:"dob"`
+	Name     string         `json:"name"`
+	Dob      time.Time      `json:"dob"`
+	Email    sql.NullString `json:"email"`
+	Phone    sql.NullString `json:"phone"`
+	Timezone sql.NullString `json:"timezone"`
+	Locale   sql.NullString `json:"locale"`
 }
 
 func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (sql.Result, error) {
-	return q.db.ExecContext(ctx, createUser, arg.Name, arg.Dob)
+	return q.db.ExecContext(ctx, createUser,
+		arg.Name,
+		arg.Dob,
+		arg.Email,
+		arg.Phone,
+		arg.Timezone,
+		arg.Locale,
+	)
 }
 
-const deleteUser = `-- name: DeleteUser :exec
-DELETE FROM users WHERE id = ?
+const deleteUser = `-- name: DeleteUser :execresult
+UPDATE users SET deleted_at = NOW(), version = version + 1 WHERE id = ? AND deleted_at IS NULL
 `
 
-func (q *Queries) DeleteUser(ctx context.Context, id int32) error {
-	_, err := q.db.ExecContext(ctx, deleteUser, id)
-	return err
+func (q *Queries) DeleteUser(ctx context.Context, id int32) (sql.Result, error) {
+	return q.db.ExecContext(ctx, deleteUser, id)
 }
 
 const getAllUsers = `-- name: GetAllUsers :many
-SELECT id, name, dob FROM users ORDER BY id
+SELECT id, name, dob, email, phone, timezone, locale, version FROM users WHERE deleted_at IS NULL ORDER BY id
 `
 
 type GetAllUsersRow struct {
-	ID   int32     `json:"id"`
-	Name string    `json:"name"`
-	Dob  time.Time `json:"dob"`
+	ID       int32          `json:"id"`
+	Name     string         `json:"name"`
+	Dob      time.Time      `json:"dob"`
+	Email    sql.NullString `json:"email"`
+	Phone    sql.NullString `json:"phone"`
+	Timezone sql.NullString `json:"timezone"`
+	Locale   sql.NullString `json:"locale"`
+	Version  int32          `json:"version"`
 }
 
 func (q *Queries) GetAllUsers(ctx context.Context) ([]GetAllUsersRow, error) {
@@ -63,7 +78,16 @@ func (q *Queries) GetAllUsers(ctx context.Context) ([]GetAllUsersRow, error) {
 	var items []GetAllUsersRow
 	for rows.Next() {
 		var i GetAllUsersRow
-		if err := rows.Scan(&i.ID, &i.Name, &i.Dob); err != nil {
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Dob,
+			&i.Email,
+			&i.Phone,
+			&i.Timezone,
+			&i.Locale,
+			&i.Version,
+		); err != nil {
 			return nil, err
 		}
 		items = append(items, i)
@@ -77,25 +101,134 @@ func (q *Queries) GetAllUsers(ctx context.Context) ([]GetAllUsersRow, error) {
 	return items, nil
 }
 
+const getDeletedUserByIDForUpdate = `-- name: GetDeletedUserByIDForUpdate :one
+SELECT id, name, dob, email, phone, timezone, locale, version, deleted_at FROM users WHERE id = ? AND deleted_at IS NOT NULL LIMIT 1 FOR UPDATE
+`
+
+type GetDeletedUserByIDForUpdateRow struct {
+	ID        int32          `json:"id"`
+	Name      string         `json:"name"`
+	Dob       time.Time      `json:"dob"`
+	Email     sql.NullString `json:"email"`
+	Phone     sql.NullString `json:"phone"`
+	Timezone  sql.NullString `json:"timezone"`
+	Locale    sql.NullString `json:"locale"`
+	Version   int32          `json:"version"`
+	DeletedAt sql.NullTime   `json:"deleted_at"`
+}
+
+func (q *Queries) GetDeletedUserByIDForUpdate(ctx context.Context, id int32) (GetDeletedUserByIDForUpdateRow, error) {
+	row := q.db.QueryRowContext(ctx, getDeletedUserByIDForUpdate, id)
+	var i GetDeletedUserByIDForUpdateRow
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Dob,
+		&i.Email,
+		&i.Phone,
+		&i.Timezone,
+		&i.Locale,
+		&i.Version,
+		&i.DeletedAt,
+	)
+	return i, err
+}
+
+const getUserByEmail = `-- name: GetUserByEmail :one
+SELECT id, name, dob, email, phone, timezone, locale, version FROM users WHERE email = ? AND deleted_at IS NULL LIMIT 1
+`
+
+type GetUserByEmailRow struct {
+	ID       int32          `json:"id"`
+	Name     string         `json:"name"`
+	Dob      time.Time      `json:"dob"`
+	Email    sql.NullString `json:"email"`
+	Phone    sql.NullString `json:"phone"`
+	Timezone sql.NullString `json:"timezone"`
+	Locale   sql.NullString `json:"locale"`
+	Version  int32          `json:"version"`
+}
+
+func (q *Queries) GetUserByEmail(ctx context.Context, email sql.NullString) (GetUserByEmailRow, error) {
+	row := q.db.QueryRowContext(ctx, getUserByEmail, email)
+	var i GetUserByEmailRow
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Dob,
+		&i.Email,
+		&i.Phone,
+		&i.Timezone,
+		&i.Locale,
+		&i.Version,
+	)
+	return i, err
+}
+
 const getUserByID = `-- name: GetUserByID :one
-SELECT id, name, dob FROM users WHERE id = ? LIMIT 1
+SELECT id, name, dob, email, phone, timezone, locale, version FROM users WHERE id = ? AND deleted_at IS NULL LIMIT 1
 `
 
 type GetUserByIDRow struct {
-	ID   int32     `json:"id"`
-	Name string    `json:"name"`
-	Dob  time.Time `json:"dob"`
+	ID       int32          `json:"id"`
+	Name     string         `json:"name"`
+	Dob      time.Time      `json:"dob"`
+	Email    sql.NullString `json:"email"`
+	Phone    sql.NullString `json:"phone"`
+	Timezone sql.NullString `json:"timezone"`
+	Locale   sql.NullString `json:"locale"`
+	Version  int32          `json:"version"`
 }
 
 func (q *Queries) GetUserByID(ctx context.Context, id int32) (GetUserByIDRow, error) {
 	row := q.db.QueryRowContext(ctx, getUserByID, id)
 	var i GetUserByIDRow
-	err := row.Scan(&i.ID, &i.Name, &i.Dob)
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Dob,
+		&i.Email,
+		&i.Phone,
+		&i.Timezone,
+		&i.Locale,
+		&i.Version,
+	)
+	return i, err
+}
+
+const getUserByIDForUpdate = `-- name: GetUserByIDForUpdate :one
+SELECT id, name, dob, email, phone, timezone, locale, version FROM users WHERE id = ? AND deleted_at IS NULL LIMIT 1 FOR UPDATE
+`
+
+type GetUserByIDForUpdateRow struct {
+	ID       int32          `json:"id"`
+	Name     string         `json:"name"`
+	Dob      time.Time      `json:"dob"`
+	Email    sql.NullString `json:"email"`
+	Phone    sql.NullString `json:"phone"`
+	Timezone sql.NullString `json:"timezone"`
+	Locale   sql.NullString `json:"locale"`
+	Version  int32          `json:"version"`
+}
+
+func (q *Queries) GetUserByIDForUpdate(ctx context.Context, id int32) (GetUserByIDForUpdateRow, error) {
+	row := q.db.QueryRowContext(ctx, getUserByIDForUpdate, id)
+	var i GetUserByIDForUpdateRow
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Dob,
+		&i.Email,
+		&i.Phone,
+		&i.Timezone,
+		&i.Locale,
+		&i.Version,
+	)
 	return i, err
 }
 
 const getUsersPaginated = `-- name: GetUsersPaginated :many
-SELECT id, name, dob FROM users ORDER BY id LIMIT ? OFFSET ?
+SELECT id, name, dob FROM users WHERE deleted_at IS NULL ORDER BY id LIMIT ? OFFSET ?
 `
 
 type GetUsersPaginatedParams struct {
@@ -132,17 +265,52 @@ func (q *Queries) GetUsersPaginated(ctx context.Context, arg GetUsersPaginatedPa
 	return items, nil
 }
 
-const updateUser = `-- name: UpdateUser :exec
-UPDATE users SET name = ?, dob = ? WHERE id = ?
+const purgeDeletedUsers = `-- name: PurgeDeletedUsers :execrows
+DELETE FROM users WHERE deleted_at IS NOT NULL AND deleted_at < ?
+`
+
+func (q *Queries) PurgeDeletedUsers(ctx context.Context, deletedAt sql.NullTime) (int64, error) {
+	result, err := q.db.ExecContext(ctx, purgeDeletedUsers, deletedAt)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const restoreUser = `-- name: RestoreUser :execresult
+UPDATE users SET deleted_at = NULL, version = version + 1 WHERE id = ? AND deleted_at IS NOT NULL
+`
+
+func (q *Queries) RestoreUser(ctx context.Context, id int32) (sql.Result, error) {
+	return q.db.ExecContext(ctx, restoreUser, id)
+}
+
+const updateUser = `-- name: UpdateUser :execresult
+UPDATE users
+SET name = ?, dob = ?, email = ?, phone = ?, timezone = ?, locale = ?, version = version + 1
+WHERE id = ? AND version = ? AND deleted_at IS NULL
 `
 
 type UpdateUserParams struct {
-	Name string    `json:"name"`
-	Dob  time.Time `json:"dob"`
-	ID   int32     `json:"id"`
+	Name     string         `json:"name"`
+	Dob      time.Time      `json:"dob"`
+	Email    sql.NullString `json:"email"`
+	Phone    sql.NullString `json:"phone"`
+	Timezone sql.NullString `json:"timezone"`
+	Locale   sql.NullString `json:"locale"`
+	ID       int32          `json:"id"`
+	Version  int32          `json:"version"`
 }
 
-func (q *Queries) UpdateUser(ctx context.Context, arg UpdateUserParams) error {
-	_, err := q.db.ExecContext(ctx, updateUser, arg.Name, arg.Dob, arg.ID)
-	return err
+func (q *Queries) UpdateUser(ctx context.Context, arg UpdateUserParams) (sql.Result, error) {
+	return q.db.ExecContext(ctx, updateUser,
+		arg.Name,
+		arg.Dob,
+		arg.Email,
+		arg.Phone,
+		arg.Timezone,
+		arg.Locale,
+		arg.ID,
+		arg.Version,
+	)
 }
@@ -0,0 +1,210 @@
+// Package age computes ages from dates of birth.
+//
+// Dates of birth are calendar dates: only their year, month and day are
+// used, whatever their time and location. An age is the number of completed
+// years on a calendar date, and the date an instant falls on depends on the
+// time zone it is observed in, so ages are computed in the user's own time
+// zone. People born on February 29 have their birthday on February 28 or
+// March 1 in common years, depending on the LeapDayRule.
+package age
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	// Embed the IANA time zone database so that user time zones resolve
+	// even on hosts without one.
+	_ "time/tzdata"
+)
+
+// LeapDayRule decides when people born on February 29 have their birthday
+// in common years.
+type LeapDayRule int
+
+const (
+	// Feb28 celebrates leap day birthdays on February 28 in common years.
+	Feb28 LeapDayRule = iota
+	// Mar1 celebrates leap day birthdays on March 1 in common years.
+	Mar1
+)
+
+// ParseLeapDayRule parses "feb28" or "mar1".
+func ParseLeapDayRule(s string) (LeapDayRule, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "feb28":
+		return Feb28, nil
+	case "mar1":
+		return Mar1, nil
+	}
+	return 0, fmt.Errorf("unknown leap day rule %q, expected feb28 or mar1", s)
+}
+
+func (r LeapDayRule) String() string {
+	if r == Mar1 {
+		return "mar1"
+	}
+	return "feb28"
+}
+
+// Clock tells the current time. It is injected so that ages can be computed
+// as of a fixed instant in tests.
+type Clock interface {
+	Now() time.Time
+}
+
+// ClockFunc adapts a function to a Clock.
+type ClockFunc func() time.Time
+
+func (f ClockFunc) Now() time.Time { return f() }
+
+// SystemClock reads the system time.
+var SystemClock Clock = ClockFunc(time.Now)
+
+// FixedClock always returns t.
+func FixedClock(t time.Time) Clock {
+	return ClockFunc(func() time.Time { return t })
+}
+
+// Date returns the calendar date of t as midnight UTC, the form dates of
+// birth are stored in.
+func Date(t time.Time) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
+
+// IsLeapYear reports whether year has a February 29.
+func IsLeapYear(year int) bool {
+	return year%4 == 0 && (year%100 != 0 || year%400 == 0)
+}
+
+// Birthday returns the date in year on which someone born on dob has their
+// birthday.
+func Birthday(dob time.Time, year int, rule LeapDayRule) time.Time {
+	_, m, d := dob.Date()
+	if m == time.February && d == 29 && !IsLeapYear(year) {
+		if rule == Mar1 {
+			return time.Date(year, time.March, 1, 0, 0, 0, 0, time.UTC)
+		}
+		d = 28
+	}
+	return time.Date(year, m, d, 0, 0, 0, 0, time.UTC)
+}
+
+// Years returns the number of years someone born on dob has completed on the
+// calendar date of on. It is zero before their first birthday, including
+// for dates of birth after on.
+func Years(dob, on time.Time, rule LeapDayRule) int {
+	dob, on = Date(dob), Date(on)
+	if on.Before(dob) {
+		return 0
+	}
+	years := on.Year() - dob.Year()
+	if on.Before(Birthday(dob, on.Year(), rule)) {
+		years--
+	}
+	return years
+}
+
+// LatestBirthDate returns the latest date of birth of someone who is at
+// least years old on the calendar date of on. Everyone born on or before it
+// is.
+func LatestBirthDate(on time.Time, years int, rule LeapDayRule) time.Time {
+	on = Date(on)
+	if years <= 0 {
+		// Everyone born up to on has completed zero years.
+		return on
+	}
+	y, m, d := on.Date()
+	candidate := time.Date(y-years, m, d, 0, 0, 0, 0, time.UTC)
+	if m == time.February && d == 29 && !IsLeapYear(y-years) {
+		candidate = time.Date(y-years, time.February, 28, 0, 0, 0, 0, time.UTC)
+	}
+
+	// The leap day rule moves the answer by at most a day either way.
+	for Years(candidate.AddDate(0, 0, 1), on, rule) >= years {
+		candidate = candidate.AddDate(0, 0, 1)
+	}
+	for Years(candidate, on, rule) < years {
+		candidate = candidate.AddDate(0, 0, -1)
+	}
+	return candidate
+}
+
+// Calculator computes ages as of the time of its clock, in the users' time
+// zones.
+type Calculator struct {
+	clock    Clock
+	rule     LeapDayRule
+	location *time.Location
+
+	mu        sync.RWMutex
+	locations map[string]*time.Location
+}
+
+// NewCalculator returns a calculator applying rule. Users without a valid
+// time zone are assumed to be in defaultLocation.
+func NewCalculator(clock Clock, rule LeapDayRule, defaultLocation *time.Location) *Calculator {
+	return &Calculator{
+		clock:     clock,
+		rule:      rule,
+		location:  defaultLocation,
+		locations: make(map[string]*time.Location),
+	}
+}
+
+// Now returns the current time according to the calculator's clock.
+func (c *Calculator) Now() time.Time {
+	return c.clock.Now()
+}
+
+// Rule returns the leap day rule the calculator applies.
+func (c *Calculator) Rule() LeapDayRule {
+	return c.rule
+}
+
+// DefaultLocation returns the time zone of users without one.
+func (c *Calculator) DefaultLocation() *time.Location {
+	return c.location
+}
+
+// Location resolves an IANA time zone name, falling back to the default
+// location when it is empty or unknown.
+func (c *Calculator) Location(tz string) *time.Location {
+	if tz == "" {
+		return c.location
+	}
+	c.mu.RLock()
+	loc, ok := c.locations[tz]
+	c.mu.RUnlock()
+	if ok {
+		return loc
+	}
+
+	loc, err := time.LoadLocation(tz)
+	if err != nil {
+		loc = c.location
+	}
+	c.mu.Lock()
+	c.locations[tz] = loc
+	c.mu.Unlock()
+	return loc
+}
+
+// Today returns the current calendar date in the time zone tz.
+func (c *Calculator) Today(tz string) time.Time {
+	return Date(c.clock.Now().In(c.Location(tz)))
+}
+
+// Age returns the current age of someone born on dob who lives in the time
+// zone tz.
+func (c *Calculator) Age(dob time.Time, tz string) int {
+	return c.AgeAt(dob, c.clock.Now(), tz)
+}
+
+// AgeAt returns the age at the instant at of someone born on dob who lives
+// in the time zone tz.
+func (c *Calculator) AgeAt(dob, at time.Time, tz string) int {
+	return Years(dob, at.In(c.Location(tz)), c.rule)
+}
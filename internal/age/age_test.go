@@ -0,0 +1,194 @@
+package age
+
+import (
+	"testing"
+	"time"
+)
+
+func date(y int, m time.Month, d int) time.Time {
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
+
+func TestYears(t *testing.T) {
+	tests := []struct {
+		name string
+		dob  time.Time
+		on   time.Time
+		rule LeapDayRule
+		want int
+	}{
+		{"Day of birth", date(1990, 5, 10), date(1990, 5, 10), Feb28, 0},
+		{"Before birth", date(1990, 5, 10), date(1989, 5, 10), Feb28, 0},
+		{"Day before first birthday", date(1990, 5, 10), date(1991, 5, 9), Feb28, 0},
+		{"First birthday", date(1990, 5, 10), date(1991, 5, 10), Feb28, 1},
+		{"Day before birthday", date(1990, 5, 10), date(2021, 5, 9), Feb28, 30},
+		{"On birthday", date(1990, 5, 10), date(2021, 5, 10), Feb28, 31},
+		{"Day after birthday", date(1990, 5, 10), date(2021, 5, 11), Feb28, 31},
+		{"New Year's Eve birthday on New Year's Day", date(2000, 12, 31), date(2001, 1, 1), Feb28, 0},
+		{"New Year's Eve birthday", date(2000, 12, 31), date(2001, 12, 31), Feb28, 1},
+		{"New Year's Day birthday on New Year's Eve", date(2000, 1, 1), date(2000, 12, 31), Feb28, 0},
+		{"Very old", date(1900, 1, 1), date(2024, 6, 15), Feb28, 124},
+
+		// YearDay differs by one after February in leap years; comparing it
+		// got these wrong.
+		{"Mar 1 birthday on Feb 29 of a leap year", date(2001, 3, 1), date(2004, 2, 29), Feb28, 2},
+		{"Mar 1 birthday in a leap year", date(2001, 3, 1), date(2004, 3, 1), Feb28, 3},
+		{"Born Mar 1 of a leap year, day before in a common year", date(2004, 3, 1), date(2005, 2, 28), Feb28, 0},
+		{"Born Mar 1 of a leap year, birthday in a common year", date(2004, 3, 1), date(2005, 3, 1), Feb28, 1},
+		{"Born Dec 31 of a leap year, day before", date(2004, 12, 31), date(2005, 12, 30), Feb28, 0},
+		{"Born Dec 31 of a leap year, birthday", date(2004, 12, 31), date(2005, 12, 31), Feb28, 1},
+		{"Born Dec 31 of a common year, day before in a leap year", date(2003, 12, 31), date(2008, 12, 30), Feb28, 4},
+
+		// Leap day birthdays.
+		{"Leap day, Feb 27 of a common year", date(2000, 2, 29), date(2001, 2, 27), Feb28, 0},
+		{"Leap day, Feb 28 of a common year, feb28 rule", date(2000, 2, 29), date(2001, 2, 28), Feb28, 1},
+		{"Leap day, Feb 28 of a common year, mar1 rule", date(2000, 2, 29), date(2001, 2, 28), Mar1, 0},
+		{"Leap day, Mar 1 of a common year, feb28 rule", date(2000, 2, 29), date(2001, 3, 1), Feb28, 1},
+		{"Leap day, Mar 1 of a common year, mar1 rule", date(2000, 2, 29), date(2001, 3, 1), Mar1, 1},
+		{"Leap day, Feb 28 of a leap year, feb28 rule", date(2000, 2, 29), date(2004, 2, 28), Feb28, 3},
+		{"Leap day, Feb 28 of a leap year, mar1 rule", date(2000, 2, 29), date(2004, 2, 28), Mar1, 3},
+		{"Leap day, Feb 29 of a leap year, feb28 rule", date(2000, 2, 29), date(2004, 2, 29), Feb28, 4},
+		{"Leap day, Feb 29 of a leap year, mar1 rule", date(2000, 2, 29), date(2004, 2, 29), Mar1, 4},
+		{"Leap day, century common year, feb28 rule", date(2096, 2, 29), date(2100, 2, 28), Feb28, 4},
+		{"Leap day, century common year, mar1 rule", date(2096, 2, 29), date(2100, 2, 28), Mar1, 3},
+		{"Leap day, 400-year leap year", date(1996, 2, 29), date(2000, 2, 29), Mar1, 4},
+
+		// Only the calendar dates count.
+		{"Times are ignored", time.Date(1990, 5, 10, 23, 59, 0, 0, time.UTC), time.Date(2021, 5, 10, 0, 1, 0, 0, time.UTC), Feb28, 31},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Years(tt.dob, tt.on, tt.rule); got != tt.want {
+				t.Errorf("Years(%s, %s, %s) = %d, want %d", tt.dob.Format("2006-01-02"), tt.on.Format("2006-01-02"), tt.rule, got, tt.want)
+			}
+		})
+	}
+}
+
+// TestYearsExhaustive checks every date of birth in a leap and a common year
+// against every day of the following eight years, comparing with a plain
+// (month, day) comparison against the birthday.
+func TestYearsExhaustive(t *testing.T) {
+	for _, rule := range []LeapDayRule{Feb28, Mar1} {
+		for dob := date(2003, 1, 1); dob.Before(date(2005, 1, 1)); dob = dob.AddDate(0, 0, 1) {
+			prev := 0
+			for on := dob; on.Before(dob.AddDate(8, 0, 1)); on = on.AddDate(0, 0, 1) {
+				got := Years(dob, on, rule)
+
+				bm, bd := dob.Month(), dob.Day()
+				if bm == time.February && bd == 29 && !IsLeapYear(on.Year()) {
+					bm, bd = time.February, 28
+					if rule == Mar1 {
+						bm, bd = time.March, 1
+					}
+				}
+				want := on.Year() - dob.Year()
+				if on.Month() < bm || (on.Month() == bm && on.Day() < bd) {
+					want--
+				}
+
+				if got != want {
+					t.Fatalf("Years(%s, %s, %s) = %d, want %d", dob.Format("2006-01-02"), on.Format("2006-01-02"), rule, got, want)
+				}
+				if got < prev || got > prev+1 {
+					t.Fatalf("Years(%s, %s, %s) = %d after %d the day before", dob.Format("2006-01-02"), on.Format("2006-01-02"), rule, got, prev)
+				}
+				prev = got
+			}
+		}
+	}
+}
+
+func TestBirthday(t *testing.T) {
+	tests := []struct {
+		dob  time.Time
+		year int
+		rule LeapDayRule
+		want time.Time
+	}{
+		{date(1990, 5, 10), 2024, Feb28, date(2024, 5, 10)},
+		{date(2000, 2, 29), 2024, Feb28, date(2024, 2, 29)},
+		{date(2000, 2, 29), 2024, Mar1, date(2024, 2, 29)},
+		{date(2000, 2, 29), 2023, Feb28, date(2023, 2, 28)},
+		{date(2000, 2, 29), 2023, Mar1, date(2023, 3, 1)},
+		{date(2000, 2, 28), 2023, Mar1, date(2023, 2, 28)},
+	}
+	for _, tt := range tests {
+		if got := Birthday(tt.dob, tt.year, tt.rule); !got.Equal(tt.want) {
+			t.Errorf("Birthday(%s, %d, %s) = %s, want %s", tt.dob.Format("2006-01-02"), tt.year, tt.rule, got.Format("2006-01-02"), tt.want.Format("2006-01-02"))
+		}
+	}
+}
+
+func TestLatestBirthDate(t *testing.T) {
+	tests := []struct {
+		name  string
+		on    time.Time
+		years int
+		rule  LeapDayRule
+		want  time.Time
+	}{
+		{"Ordinary date", date(2024, 6, 15), 18, Feb28, date(2006, 6, 15)},
+		{"Zero years", date(2024, 6, 15), 0, Feb28, date(2024, 6, 15)},
+		{"Feb 28 of a common year, feb28 rule", date(2023, 2, 28), 3, Feb28, date(2020, 2, 29)},
+		{"Feb 28 of a common year, mar1 rule", date(2023, 2, 28), 3, Mar1, date(2020, 2, 28)},
+		{"Mar 1 of a common year, mar1 rule", date(2023, 3, 1), 3, Mar1, date(2020, 3, 1)},
+		{"Feb 29 back to a common year", date(2024, 2, 29), 1, Feb28, date(2023, 2, 28)},
+		{"Feb 29 back to a leap year", date(2024, 2, 29), 4, Mar1, date(2020, 2, 29)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := LatestBirthDate(tt.on, tt.years, tt.rule)
+			if !got.Equal(tt.want) {
+				t.Fatalf("LatestBirthDate() = %s, want %s", got.Format("2006-01-02"), tt.want.Format("2006-01-02"))
+			}
+			if tt.years > 0 && (Years(got, tt.on, tt.rule) < tt.years || Years(got.AddDate(0, 0, 1), tt.on, tt.rule) >= tt.years) {
+				t.Errorf("LatestBirthDate() = %s is not the boundary", got.Format("2006-01-02"))
+			}
+		})
+	}
+}
+
+func TestCalculatorTimeZones(t *testing.T) {
+	// 2024-05-10 03:00 UTC is still May 9 in New York but already May 10
+	// in Tokyo.
+	clock := FixedClock(time.Date(2024, 5, 10, 3, 0, 0, 0, time.UTC))
+	c := NewCalculator(clock, Feb28, time.UTC)
+	dob := date(1990, 5, 10)
+
+	tests := []struct {
+		tz   string
+		want int
+	}{
+		{"", 34},
+		{"UTC", 34},
+		{"Asia/Tokyo", 34},
+		{"America/New_York", 33},
+		{"Not/AZone", 34},
+	}
+	for _, tt := range tests {
+		if got := c.Age(dob, tt.tz); got != tt.want {
+			t.Errorf("Age(%q) = %d, want %d", tt.tz, got, tt.want)
+		}
+	}
+
+	ny := NewCalculator(clock, Feb28, c.Location("America/New_York"))
+	if got := ny.Age(dob, ""); got != 33 {
+		t.Errorf("Age() with a New York default = %d, want 33", got)
+	}
+	if got := c.Today("America/New_York"); !got.Equal(date(2024, 5, 9)) {
+		t.Errorf("Today(America/New_York) = %s, want 2024-05-09", got.Format("2006-01-02"))
+	}
+}
+
+func TestParseLeapDayRule(t *testing.T) {
+	for in, want := range map[string]LeapDayRule{"feb28": Feb28, "MAR1": Mar1} {
+		if got, err := ParseLeapDayRule(in); err != nil || got != want {
+			t.Errorf("ParseLeapDayRule(%q) = %v, %v; want %v", in, got, err, want)
+		}
+	}
+	if _, err := ParseLeapDayRule("feb29"); err == nil {
+		t.Error("ParseLeapDayRule(feb29) accepted")
+	}
+}
@@ -0,0 +1,87 @@
+package age
+
+import "time"
+
+// Breakdown is an age in completed years, months and days, as in "3 years,
+// 4 months and 2 days", along with the total number of days lived.
+type Breakdown struct {
+	Years     int
+	Months    int
+	Days      int
+	TotalDays int
+}
+
+// Detail breaks down the age of someone born on dob on the calendar date of
+// on. Months are counted from the last birthday on the day of the month of
+// dob, or the last day of shorter months, and days from the last such month
+// anniversary, or from the birthday within the first month. It is zero for
+// dates of birth after on.
+func Detail(dob, on time.Time, rule LeapDayRule) Breakdown {
+	dob, on = Date(dob), Date(on)
+	if on.Before(dob) {
+		return Breakdown{}
+	}
+	years := Years(dob, on, rule)
+
+	// Months stop at 11: with the mar1 rule, the twelfth anniversary of a
+	// leap day birth, on February 28, comes the day before the birthday.
+	months := 0
+	for months < 11 && !on.Before(addMonths(dob, 12*years+months+1)) {
+		months++
+	}
+	from := Birthday(dob, dob.Year()+years, rule)
+	if months > 0 {
+		from = addMonths(dob, 12*years+months)
+	}
+	return Breakdown{
+		Years:     years,
+		Months:    months,
+		Days:      daysBetween(from, on),
+		TotalDays: daysBetween(dob, on),
+	}
+}
+
+// NextBirthday returns the date of the first birthday on or after the
+// calendar date of on of someone born on dob. Before birth, it is the first
+// birthday.
+func NextBirthday(dob, on time.Time, rule LeapDayRule) time.Time {
+	dob, on = Date(dob), Date(on)
+	if on.Before(dob) {
+		return Birthday(dob, dob.Year()+1, rule)
+	}
+	if next := Birthday(dob, on.Year(), rule); !next.Before(on) {
+		return next
+	}
+	return Birthday(dob, on.Year()+1, rule)
+}
+
+// addMonths returns the date n months after d, on the same day of the month
+// or the last day of the month when it is shorter.
+func addMonths(d time.Time, n int) time.Time {
+	y, m, day := d.Date()
+	first := time.Date(y, m+time.Month(n), 1, 0, 0, 0, 0, time.UTC)
+	if last := first.AddDate(0, 1, -1).Day(); day > last {
+		day = last
+	}
+	return first.AddDate(0, 0, day-1)
+}
+
+// daysBetween returns the number of days from the date from to the date to.
+func daysBetween(from, to time.Time) int {
+	return int(to.Sub(from).Hours() / 24)
+}
+
+// DetailAt breaks down the age at the instant at of someone born on dob who
+// lives in the time zone tz.
+func (c *Calculator) DetailAt(dob, at time.Time, tz string) Breakdown {
+	return Detail(dob, at.In(c.Location(tz)), c.rule)
+}
+
+// NextBirthdayAt returns the next birthday, as of the instant at, of someone
+// born on dob who lives in the time zone tz, and the number of days until
+// it, which is zero on the birthday itself.
+func (c *Calculator) NextBirthdayAt(dob, at time.Time, tz string) (time.Time, int) {
+	today := Date(at.In(c.Location(tz)))
+	next := NextBirthday(dob, today, c.rule)
+	return next, daysBetween(today, next)
+}
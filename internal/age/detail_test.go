@@ -0,0 +1,80 @@
+package age
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDetail(t *testing.T) {
+	tests := []struct {
+		name string
+		dob  time.Time
+		on   time.Time
+		rule LeapDayRule
+		want Breakdown
+	}{
+		{"Day of birth", date(2021, 2, 10), date(2021, 2, 10), Feb28, Breakdown{}},
+		{"Before birth", date(2021, 2, 10), date(2021, 2, 9), Feb28, Breakdown{}},
+		{"Days only", date(2021, 2, 10), date(2021, 3, 1), Feb28, Breakdown{Days: 19, TotalDays: 19}},
+		{"Years, months and days", date(2021, 2, 10), date(2024, 6, 12), Feb28, Breakdown{Years: 3, Months: 4, Days: 2, TotalDays: 1218}},
+		{"Day before birthday", date(2021, 2, 10), date(2024, 2, 9), Feb28, Breakdown{Years: 2, Months: 11, Days: 30, TotalDays: 1094}},
+		{"On birthday", date(2021, 2, 10), date(2024, 2, 10), Feb28, Breakdown{Years: 3, TotalDays: 1095}},
+		{"Month anniversary in a shorter month", date(2023, 1, 31), date(2023, 2, 28), Feb28, Breakdown{Months: 1, TotalDays: 28}},
+		{"Day after a shortened anniversary", date(2023, 1, 31), date(2023, 3, 1), Feb28, Breakdown{Months: 1, Days: 1, TotalDays: 29}},
+		{"Back to the full day", date(2023, 1, 31), date(2023, 3, 31), Feb28, Breakdown{Months: 2, TotalDays: 59}},
+		{"Leap day, feb28 rule", date(2020, 2, 29), date(2021, 3, 28), Feb28, Breakdown{Years: 1, Days: 28, TotalDays: 393}},
+		{"Leap day, feb28 rule, month anniversary", date(2020, 2, 29), date(2021, 3, 29), Feb28, Breakdown{Years: 1, Months: 1, TotalDays: 394}},
+		{"Leap day, mar1 rule", date(2020, 2, 29), date(2021, 3, 28), Mar1, Breakdown{Years: 1, Days: 27, TotalDays: 393}},
+		{"Leap day, mar1 rule, day before the birthday", date(2020, 2, 29), date(2021, 2, 28), Mar1, Breakdown{Months: 11, Days: 30, TotalDays: 365}},
+		{"Leap day, day before the next leap birthday", date(2020, 2, 29), date(2024, 2, 28), Feb28, Breakdown{Years: 3, Months: 11, Days: 30, TotalDays: 1460}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Detail(tt.dob, tt.on, tt.rule); got != tt.want {
+				t.Errorf("Detail() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNextBirthday(t *testing.T) {
+	tests := []struct {
+		name string
+		dob  time.Time
+		on   time.Time
+		rule LeapDayRule
+		want time.Time
+	}{
+		{"Later this year", date(1990, 5, 10), date(2024, 3, 1), Feb28, date(2024, 5, 10)},
+		{"Today", date(1990, 5, 10), date(2024, 5, 10), Feb28, date(2024, 5, 10)},
+		{"Next year", date(1990, 5, 10), date(2024, 5, 11), Feb28, date(2025, 5, 10)},
+		{"Across New Year", date(1990, 1, 1), date(2024, 12, 31), Feb28, date(2025, 1, 1)},
+		{"Before birth", date(2024, 5, 10), date(2024, 1, 1), Feb28, date(2025, 5, 10)},
+		{"Leap day in a leap year", date(2000, 2, 29), date(2024, 1, 1), Mar1, date(2024, 2, 29)},
+		{"Leap day, feb28 rule", date(2000, 2, 29), date(2025, 1, 1), Feb28, date(2025, 2, 28)},
+		{"Leap day, mar1 rule", date(2000, 2, 29), date(2025, 2, 28), Mar1, date(2025, 3, 1)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NextBirthday(tt.dob, tt.on, tt.rule); !got.Equal(tt.want) {
+				t.Errorf("NextBirthday() = %s, want %s", got.Format("2006-01-02"), tt.want.Format("2006-01-02"))
+			}
+		})
+	}
+}
+
+func TestCalculatorNextBirthdayAt(t *testing.T) {
+	// 2024-05-10 03:00 UTC is still May 9 in New York.
+	at := time.Date(2024, 5, 10, 3, 0, 0, 0, time.UTC)
+	c := NewCalculator(FixedClock(at), Feb28, time.UTC)
+	dob := date(1990, 5, 10)
+
+	if next, days := c.NextBirthdayAt(dob, at, "America/New_York"); !next.Equal(date(2024, 5, 10)) || days != 1 {
+		t.Errorf("NextBirthdayAt(New York) = %s, %d, want 2024-05-10, 1", next.Format("2006-01-02"), days)
+	}
+	if next, days := c.NextBirthdayAt(dob, at, "Asia/Tokyo"); !next.Equal(date(2024, 5, 10)) || days != 0 {
+		t.Errorf("NextBirthdayAt(Tokyo) = %s, %d, want 2024-05-10, 0", next.Format("2006-01-02"), days)
+	}
+}
@@ -0,0 +1,112 @@
+// Package apperr defines the errors that are reported to API clients. Each
+// error has a kind, which decides the HTTP status it is answered with, and
+// a stable code clients can match on. Only the detail of the error itself
+// is shown to clients: layers wrap errors with %w to add context for logs,
+// and use Wrapf or WithDetail to explain them to clients. errors.Is matches
+// both the error and its kind.
+package apperr
+
+import "fmt"
+
+// The kinds of errors. Errors of a kind wrap it.
+var (
+	// ErrValidation is the kind of errors in what the client sent.
+	ErrValidation = &Error{Code: "validation_failed", Detail: "validation failed"}
+	// ErrNotFound is the kind of errors for resources that do not exist.
+	ErrNotFound = &Error{Code: "not_found", Detail: "not found"}
+	// ErrConflict is the kind of errors for requests that conflict with the
+	// current state of a resource.
+	ErrConflict = &Error{Code: "conflict", Detail: "conflict"}
+	// ErrPrecondition is the kind of errors for failed If-Match
+	// preconditions.
+	ErrPrecondition = &Error{Code: "precondition_failed", Detail: "precondition failed"}
+	// ErrUnprocessable is the kind of errors for well-formed requests that
+	// cannot be applied.
+	ErrUnprocessable = &Error{Code: "unprocessable", Detail: "unprocessable request"}
+	// ErrUnavailable is the kind of errors caused by a dependency, such as
+	// the database, being unavailable. The request may be retried.
+	ErrUnavailable = &Error{Code: "unavailable", Detail: "service unavailable"}
+)
+
+// Error is an error reported to clients.
+type Error struct {
+	// Kind is one of the kinds above, or nil for the kinds themselves.
+	Kind *Error
+	// Code identifies the error, e.g. user_not_found. Codes never change.
+	Code string
+	// Detail describes the error to humans.
+	Detail string
+	// Fields lists the invalid fields of a validation error.
+	Fields []FieldError
+}
+
+// FieldError describes why a field of a request is invalid.
+type FieldError struct {
+	// Field is the name of the field as sent, e.g. email.
+	Field string `json:"field"`
+	// Code is the rule the field broke, e.g. required or email.
+	Code string `json:"code"`
+	// Param is the parameter of the rule, if any, e.g. 100 for max=100.
+	Param string `json:"param,omitempty"`
+	// Message describes the problem to humans.
+	Message string `json:"message"`
+}
+
+// New returns an error of the given kind.
+func New(kind *Error, code, detail string) *Error {
+	return &Error{Kind: kind, Code: code, Detail: detail}
+}
+
+func (e *Error) Error() string {
+	return e.Detail
+}
+
+// Unwrap returns the kind of e, so that errors.Is matches it.
+func (e *Error) Unwrap() error {
+	if e.Kind == nil {
+		return nil
+	}
+	return e.Kind
+}
+
+// Is reports whether target is an error with the same code, so that copies
+// made by WithDetail and WithFields match the error they were made from.
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	return ok && t.Code == e.Code
+}
+
+// WithDetail returns a copy of e with another detail.
+func (e *Error) WithDetail(detail string) *Error {
+	c := e.copy()
+	c.Detail = detail
+	return c
+}
+
+// Wrapf returns a copy of e whose detail is followed by an explanation,
+// e.g. "invalid filter: dob_from must be YYYY-MM-DD".
+func (e *Error) Wrapf(format string, args ...interface{}) *Error {
+	return e.WithDetail(e.Detail + ": " + fmt.Sprintf(format, args...))
+}
+
+// WithFields returns a copy of e listing the given invalid fields.
+func (e *Error) WithFields(fields ...FieldError) *Error {
+	c := e.copy()
+	c.Fields = fields
+	return c
+}
+
+// copy returns a copy of e. Copies of a kind are of that kind.
+func (e *Error) copy() *Error {
+	c := *e
+	c.Kind = e.KindOf()
+	return &c
+}
+
+// KindOf returns the kind of e: e itself when it is a kind.
+func (e *Error) KindOf() *Error {
+	if e.Kind == nil {
+		return e
+	}
+	return e.Kind
+}
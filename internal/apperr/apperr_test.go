@@ -0,0 +1,44 @@
+package apperr
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestErrorIs(t *testing.T) {
+	errMissing := New(ErrNotFound, "thing_not_found", "thing not found")
+	wrapped := fmt.Errorf("%w: id 5", errMissing)
+
+	tests := []struct {
+		name   string
+		err    error
+		target error
+		want   bool
+	}{
+		{"Wrapped error", wrapped, errMissing, true},
+		{"Kind of a wrapped error", wrapped, ErrNotFound, true},
+		{"Other kind", wrapped, ErrConflict, false},
+		{"Copy with another detail", errMissing.WithDetail("thing 5 not found"), errMissing, true},
+		{"Copy with an explanation", errMissing.Wrapf("id %d", 5), errMissing, true},
+		{"Copy with fields", ErrValidation.WithFields(FieldError{Field: "name", Code: "required"}), ErrValidation, true},
+		{"Other error of the kind", New(ErrNotFound, "other_not_found", "other not found"), errMissing, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := errors.Is(tt.err, tt.target); got != tt.want {
+				t.Errorf("errors.Is() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestKindOf(t *testing.T) {
+	if got := New(ErrConflict, "taken", "taken").KindOf(); got != ErrConflict {
+		t.Errorf("KindOf() = %v, want ErrConflict", got)
+	}
+	if got := ErrConflict.WithDetail("busy").KindOf(); got != ErrConflict {
+		t.Errorf("KindOf() of a copied kind = %v, want ErrConflict", got)
+	}
+}
@@ -0,0 +1,60 @@
+// Package audit carries who made a change, and under which request, from the
+// HTTP layer down to the repository so it can be recorded with the change.
+package audit
+
+import (
+	"context"
+	"unicode"
+	"unicode/utf8"
+)
+
+type contextKey int
+
+const (
+	requestIDKey contextKey = iota
+	actorKey
+)
+
+// AnonymousActor is recorded when a request does not identify its actor.
+const AnonymousActor = "anonymous"
+
+// MaxActorLength is the longest actor, in characters, that can be recorded.
+const MaxActorLength = 255
+
+// WithRequestID returns a copy of ctx carrying the request ID.
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDKey, requestID)
+}
+
+// RequestID returns the request ID stored in ctx, or "" if there is none.
+func RequestID(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey).(string)
+	return id
+}
+
+// WithActor returns a copy of ctx carrying the actor making the change.
+func WithActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, actorKey, actor)
+}
+
+// Actor returns the actor stored in ctx, or AnonymousActor if there is none.
+func Actor(ctx context.Context) string {
+	if actor, ok := ctx.Value(actorKey).(string); ok && actor != "" {
+		return actor
+	}
+	return AnonymousActor
+}
+
+// ValidActor reports whether actor can be recorded: it must be at most
+// MaxActorLength characters of valid UTF-8 without control characters.
+func ValidActor(actor string) bool {
+	if !utf8.ValidString(actor) || utf8.RuneCountInString(actor) > MaxActorLength {
+		return false
+	}
+	for _, r := range actor {
+		if unicode.IsControl(r) {
+			return false
+		}
+	}
+	return true
+}
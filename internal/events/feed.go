@@ -0,0 +1,236 @@
+package events
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/Pallavi566/Go-Backend/internal/apperr"
+	"github.com/Pallavi566/Go-Backend/internal/models"
+	"github.com/Pallavi566/Go-Backend/internal/repository"
+	"go.uber.org/zap"
+)
+
+var (
+	// ErrFeedClosed is returned by Subscribe once the feed has shut down.
+	ErrFeedClosed = apperr.New(apperr.ErrUnavailable, "feed_closed", "event feed closed")
+	// ErrFeedStarting is returned by Subscribe until the feed has read
+	// where the outbox stands.
+	ErrFeedStarting = apperr.New(apperr.ErrUnavailable, "feed_starting", "event feed starting")
+)
+
+const (
+	// pageSize is the number of events read from the outbox per query.
+	pageSize = 100
+	// bufferSize is the number of events a subscriber may lag behind before
+	// it is dropped.
+	bufferSize = 256
+)
+
+// Feed broadcasts new user events to change feed subscribers. A single
+// poller reads the outbox, so the load on the database does not grow with
+// the number of connected clients.
+//
+// Outbox IDs are assigned when an event is inserted, not when its
+// transaction commits, so an event may become visible after events with
+// higher IDs. Events are published in ID order all the same, so that a
+// client's last event ID says which events it has seen: an event after a
+// missing ID is held back until the missing one commits, or until gapTimeout
+// has passed since the event was first read, after which the missing ID is
+// taken to belong to a transaction that rolled back.
+type Feed struct {
+	repo       *repository.EventRepository
+	interval   time.Duration
+	gapTimeout time.Duration
+	logger     *zap.Logger
+
+	// last is the ID of the last event published, or -1 until the feed
+	// has started.
+	last atomic.Int64
+	// held records when events held back behind a missing ID were first
+	// read. It is only used by the poller.
+	held map[int64]time.Time
+
+	mu     sync.Mutex
+	subs   map[*Subscription]struct{}
+	closed bool
+}
+
+// Subscription receives the events published after it was created.
+type Subscription struct {
+	feed   *Feed
+	events chan models.Event
+}
+
+// NewFeed returns a feed polling repo every interval. gapTimeout is how long
+// events are held back waiting for an event with a lower ID to commit; it
+// should exceed the time transactions take to commit.
+func NewFeed(repo *repository.EventRepository, interval, gapTimeout time.Duration, logger *zap.Logger) *Feed {
+	f := &Feed{
+		repo:       repo,
+		interval:   interval,
+		gapTimeout: gapTimeout,
+		logger:     logger,
+		held:       make(map[int64]time.Time),
+		subs:       make(map[*Subscription]struct{}),
+	}
+	f.last.Store(-1)
+	return f
+}
+
+// Run polls the outbox every interval and publishes new events to the
+// subscribers. When ctx is cancelled every subscription is closed, which
+// ends the open streams, and Run returns.
+func (f *Feed) Run(ctx context.Context) {
+	ticker := time.NewTicker(f.interval)
+	defer ticker.Stop()
+	defer f.close()
+
+	for {
+		if f.last.Load() < 0 {
+			// Only events committed from now on are published; clients
+			// catch up on older ones through Backlog.
+			id, err := f.repo.LatestID(ctx)
+			if err != nil {
+				f.logger.Error("Failed to read latest event ID", zap.Error(err))
+			} else {
+				f.last.Store(id)
+			}
+		} else if err := f.poll(ctx); err != nil && ctx.Err() == nil {
+			f.logger.Error("Failed to poll user events", zap.Error(err))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// poll publishes the events after the last one published, in order, up to
+// the first missing ID still waited for.
+func (f *Feed) poll(ctx context.Context) error {
+	for {
+		events, err := f.repo.ListAfter(ctx, f.last.Load(), pageSize)
+		if err != nil {
+			return err
+		}
+		if !f.advance(events, time.Now()) || len(events) < pageSize {
+			return nil
+		}
+	}
+}
+
+// advance publishes events, read in ID order after the last one published,
+// until one follows a missing ID that is still waited for. It reports
+// whether every event was published.
+func (f *Feed) advance(events []models.Event, now time.Time) bool {
+	// The IDs missing before an event were assigned before those of every
+	// event after it, so they are waited for from when the first of those
+	// was read.
+	since := make([]time.Time, len(events))
+	earliest := now
+	for i := len(events) - 1; i >= 0; i-- {
+		at, ok := f.held[events[i].ID]
+		if !ok {
+			at = now
+		}
+		if at.Before(earliest) {
+			earliest = at
+		}
+		since[i] = earliest
+	}
+
+	for i, e := range events {
+		if e.ID > f.last.Load()+1 && now.Sub(since[i]) < f.gapTimeout {
+			for _, held := range events[i:] {
+				if _, ok := f.held[held.ID]; !ok {
+					f.held[held.ID] = now
+				}
+			}
+			return false
+		}
+		f.publish(e)
+		delete(f.held, e.ID)
+		f.last.Store(e.ID)
+	}
+	return true
+}
+
+// Backlog returns up to limit events after afterID, oldest first, for
+// clients resuming with Last-Event-ID. Only events already published are
+// returned; the others are still to come through the subscription, in
+// order.
+func (f *Feed) Backlog(ctx context.Context, afterID int64, limit int) ([]models.Event, error) {
+	last := f.last.Load()
+	events, err := f.repo.ListAfter(ctx, afterID, limit)
+	if err != nil {
+		return nil, err
+	}
+	for i, e := range events {
+		if e.ID > last {
+			return events[:i], nil
+		}
+	}
+	return events, nil
+}
+
+// Subscribe registers a subscriber for new events. The subscription must be
+// closed when no longer needed.
+func (f *Feed) Subscribe() (*Subscription, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.closed {
+		return nil, ErrFeedClosed
+	}
+	if f.last.Load() < 0 {
+		return nil, ErrFeedStarting
+	}
+	s := &Subscription{feed: f, events: make(chan models.Event, bufferSize)}
+	f.subs[s] = struct{}{}
+	return s, nil
+}
+
+// publish hands e to every subscriber without blocking. A subscriber whose
+// buffer is full is dropped; its stream ends and the client resumes from
+// its last event ID.
+func (f *Feed) publish(e models.Event) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for s := range f.subs {
+		select {
+		case s.events <- e:
+		default:
+			delete(f.subs, s)
+			close(s.events)
+		}
+	}
+}
+
+func (f *Feed) close() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.closed = true
+	for s := range f.subs {
+		delete(f.subs, s)
+		close(s.events)
+	}
+}
+
+// Events returns the channel events are delivered on. It is closed when
+// the subscriber falls too far behind or the feed shuts down.
+func (s *Subscription) Events() <-chan models.Event {
+	return s.events
+}
+
+// Close unregisters the subscription. It is safe to call more than once.
+func (s *Subscription) Close() {
+	s.feed.mu.Lock()
+	defer s.feed.mu.Unlock()
+	if _, ok := s.feed.subs[s]; ok {
+		delete(s.feed.subs, s)
+		close(s.events)
+	}
+}
@@ -0,0 +1,160 @@
+package events
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/Pallavi566/Go-Backend/internal/models"
+	"go.uber.org/zap"
+)
+
+// newStartedFeed returns a feed that has published every event up to ID 0.
+func newStartedFeed() *Feed {
+	f := NewFeed(nil, time.Second, time.Minute, zap.NewNop())
+	f.last.Store(0)
+	return f
+}
+
+// published returns the IDs of the events received by s so far.
+func published(s *Subscription) []int64 {
+	var ids []int64
+	for {
+		select {
+		case e := <-s.Events():
+			ids = append(ids, e.ID)
+		default:
+			return ids
+		}
+	}
+}
+
+func events(ids ...int64) []models.Event {
+	list := make([]models.Event, len(ids))
+	for i, id := range ids {
+		list[i] = models.Event{ID: id}
+	}
+	return list
+}
+
+func TestAdvanceWaitsForEventsCommittedOutOfOrder(t *testing.T) {
+	f := newStartedFeed()
+	s, _ := f.Subscribe()
+	defer s.Close()
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	// 2 is inserted before 3 but commits after it.
+	if f.advance(events(1, 3), now) {
+		t.Error("advance() = true with 2 missing")
+	}
+	if got := published(s); !reflect.DeepEqual(got, []int64{1}) {
+		t.Fatalf("published %v, want [1]", got)
+	}
+	if last := f.last.Load(); last != 1 {
+		t.Errorf("last = %d, want 1", last)
+	}
+
+	if !f.advance(events(2, 3), now.Add(time.Second)) {
+		t.Error("advance() = false with no ID missing")
+	}
+	if got := published(s); !reflect.DeepEqual(got, []int64{2, 3}) {
+		t.Errorf("published %v, want [2 3]", got)
+	}
+	if len(f.held) != 0 {
+		t.Errorf("held = %v, want none", f.held)
+	}
+}
+
+func TestAdvanceSkipsMissingIDsAfterGapTimeout(t *testing.T) {
+	f := newStartedFeed()
+	s, _ := f.Subscribe()
+	defer s.Close()
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	// 1 and 4 rolled back.
+	f.advance(events(2, 3), now)
+	f.advance(events(2, 3, 5), now.Add(30*time.Second))
+	if got := published(s); got != nil {
+		t.Fatalf("published %v before the gap timed out", got)
+	}
+
+	// The gap before 2 times out first; 4 is waited for from when 5 was
+	// read.
+	f.advance(events(2, 3, 5), now.Add(time.Minute))
+	if got := published(s); !reflect.DeepEqual(got, []int64{2, 3}) {
+		t.Fatalf("published %v, want [2 3]", got)
+	}
+	f.advance(events(5), now.Add(90*time.Second))
+	if got := published(s); !reflect.DeepEqual(got, []int64{5}) {
+		t.Errorf("published %v, want [5]", got)
+	}
+}
+
+func TestSubscribeBeforeStart(t *testing.T) {
+	f := NewFeed(nil, time.Second, time.Minute, zap.NewNop())
+	if _, err := f.Subscribe(); err != ErrFeedStarting {
+		t.Errorf("Subscribe() error = %v, want ErrFeedStarting", err)
+	}
+}
+
+func TestPublishDropsSlowSubscribers(t *testing.T) {
+	f := newStartedFeed()
+	fast, err := f.Subscribe()
+	if err != nil {
+		t.Fatalf("Subscribe() error = %v", err)
+	}
+	slow, _ := f.Subscribe()
+
+	for i := 1; i <= bufferSize; i++ {
+		f.publish(models.Event{ID: int64(i)})
+		<-fast.Events()
+	}
+	f.publish(models.Event{ID: bufferSize + 1})
+
+	if e := <-fast.Events(); e.ID != bufferSize+1 {
+		t.Errorf("fast subscriber got event %d, want %d", e.ID, bufferSize+1)
+	}
+	for range slow.Events() {
+	}
+	if _, ok := f.subs[slow]; ok {
+		t.Error("slow subscriber was not dropped")
+	}
+	slow.Close()
+	fast.Close()
+}
+
+func TestCloseEndsSubscriptions(t *testing.T) {
+	f := newStartedFeed()
+	s, _ := f.Subscribe()
+	f.close()
+
+	if _, ok := <-s.Events(); ok {
+		t.Error("subscription still open after feed closed")
+	}
+	if _, err := f.Subscribe(); err != ErrFeedClosed {
+		t.Errorf("Subscribe() error = %v, want ErrFeedClosed", err)
+	}
+	s.Close()
+}
+
+func TestWriteEvent(t *testing.T) {
+	var buf bytes.Buffer
+	e := models.Event{
+		ID:         12,
+		Type:       models.EventUserUpdated,
+		OccurredAt: time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC),
+		Data:       json.RawMessage(`{"user":null,"actor":"admin"}`),
+	}
+	if err := WriteEvent(&buf, e); err != nil {
+		t.Fatalf("WriteEvent() error = %v", err)
+	}
+
+	want := "id: 12\nevent: user.updated\n" +
+		`data: {"id":12,"type":"user.updated","occurred_at":"2024-03-01T12:00:00Z","data":{"user":null,"actor":"admin"}}` +
+		"\n\n"
+	if got := buf.String(); got != want {
+		t.Errorf("WriteEvent() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,34 @@
+package events
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/Pallavi566/Go-Backend/internal/models"
+)
+
+// WriteEvent writes e as a Server-Sent Event. The SSE id is the outbox
+// sequence number, which clients send back in Last-Event-ID to resume.
+func WriteEvent(w io.Writer, e models.Event) error {
+	data, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", e.ID, e.Type, data)
+	return err
+}
+
+// WriteRetry tells the client how long to wait, in milliseconds, before
+// reconnecting after the stream ends.
+func WriteRetry(w io.Writer, ms int) error {
+	_, err := fmt.Fprintf(w, "retry: %d\n\n", ms)
+	return err
+}
+
+// WriteHeartbeat writes an SSE comment. It keeps idle connections open
+// through proxies and detects clients that went away.
+func WriteHeartbeat(w io.Writer) error {
+	_, err := io.WriteString(w, ": heartbeat\n\n")
+	return err
+}
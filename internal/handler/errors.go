@@ -0,0 +1,27 @@
+package handler
+
+import (
+	"github.com/go-playground/validator/v10"
+	"github.com/Pallavi566/Go-Backend/internal/apperr"
+	"github.com/Pallavi566/Go-Backend/internal/service"
+)
+
+var (
+	errInvalidBody  = invalid("invalid_body", "Invalid request body")
+	errInvalidQuery = invalid("invalid_query", "Invalid query parameters")
+	// IDs in paths share a code.
+	errInvalidUserID         = invalid("invalid_id", "Invalid user ID")
+	errInvalidExportID       = invalid("invalid_id", "Invalid export ID")
+	errInvalidSubscriptionID = invalid("invalid_id", "Invalid subscription ID")
+)
+
+// invalid returns a validation error for a malformed request.
+func invalid(code, detail string) *apperr.Error {
+	return apperr.New(apperr.ErrValidation, code, detail)
+}
+
+// newValidator returns the validator of request structs, which reports
+// fields by the names clients send.
+func newValidator() *validator.Validate {
+	return service.Validator()
+}
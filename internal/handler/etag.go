@@ -0,0 +1,77 @@
+package handler
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+	"strings"
+)
+
+// representationETag returns the strong entity tag for one representation
+// of a user version. The body of a read depends on more than the version:
+// the age is computed on the day of the request and include and as_of
+// change the fields returned. The tag is the version followed by a digest
+// of the body, so that If-None-Match only matches a body that is byte for
+// byte the same, while If-Match still compares the version.
+func representationETag(version int, body []byte) string {
+	sum := sha256.Sum256(body)
+	return `"` + strconv.Itoa(version) + "-" + hex.EncodeToString(sum[:8]) + `"`
+}
+
+// parseETags parses an If-Match header into the user versions it lists. It
+// returns nil versions for an absent header and any=true for "*". Tags are
+// read as a version optionally followed by the digest representationETag
+// adds; others are ignored, so a header listing only foreign tags yields an
+// empty, non-nil slice that matches nothing. Weak tags are only honoured when allowWeak is set, since
+// If-Match requires strong comparison.
+func parseETags(header string, allowWeak bool) (versions []int, any bool) {
+	header = strings.TrimSpace(header)
+	if header == "" {
+		return nil, false
+	}
+	if header == "*" {
+		return nil, true
+	}
+
+	versions = []int{}
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimSpace(tag)
+		if strings.HasPrefix(tag, "W/") {
+			if !allowWeak {
+				continue
+			}
+			tag = tag[2:]
+		}
+		if len(tag) < 2 || tag[0] != '"' || tag[len(tag)-1] != '"' {
+			continue
+		}
+		version, _, _ := strings.Cut(tag[1:len(tag)-1], "-")
+		if v, err := strconv.Atoi(version); err == nil {
+			versions = append(versions, v)
+		}
+	}
+	return versions, false
+}
+
+// ifMatchVersions returns the versions accepted by an If-Match header, or
+// nil when any version is acceptable.
+func ifMatchVersions(header string) []int {
+	versions, _ := parseETags(header, false)
+	return versions
+}
+
+// noneMatch reports whether an If-None-Match header matches tag, in which
+// case a GET should be answered with 304 Not Modified. If-None-Match uses
+// weak comparison, so a W/ prefix is ignored.
+func noneMatch(header, tag string) bool {
+	header = strings.TrimSpace(header)
+	if header == "*" {
+		return true
+	}
+	for _, t := range strings.Split(header, ",") {
+		if strings.TrimPrefix(strings.TrimSpace(t), "W/") == tag {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,65 @@
+package handler
+
+import "testing"
+
+func TestParseETags(t *testing.T) {
+	tests := []struct {
+		name      string
+		header    string
+		allowWeak bool
+		want      []int
+		wantAny   bool
+	}{
+		{"Absent", "", false, nil, false},
+		{"Wildcard", "*", false, nil, true},
+		{"Single", `"3"`, false, []int{3}, false},
+		{"List", `"3", "5"`, false, []int{3, 5}, false},
+		{"Weak ignored for If-Match", `W/"3"`, false, []int{}, false},
+		{"Weak allowed for If-None-Match", `W/"3"`, true, []int{3}, false},
+		{"Representation", `"3-0123456789abcdef"`, false, []int{3}, false},
+		{"Foreign tag", `"abc"`, false, []int{}, false},
+		{"Unquoted", `3`, false, []int{}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, any := parseETags(tt.header, tt.allowWeak)
+			if any != tt.wantAny {
+				t.Errorf("any = %v, want %v", any, tt.wantAny)
+			}
+			if (got == nil) != (tt.want == nil) || len(got) != len(tt.want) {
+				t.Fatalf("versions = %#v, want %#v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("versions[%d] = %d, want %d", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestNoneMatch(t *testing.T) {
+	tag := representationETag(2, []byte(`{"id":1}`))
+	tests := []struct {
+		name   string
+		header string
+		want   bool
+	}{
+		{"Same", tag, true},
+		{"Weak", "W/" + tag, true},
+		{"List", `"1", ` + tag, true},
+		{"Wildcard", "*", true},
+		{"Absent", "", false},
+		{"Version only", `"2"`, false},
+		{"Other body", representationETag(2, []byte(`{"id":1,"age":31}`)), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := noneMatch(tt.header, tag); got != tt.want {
+				t.Errorf("noneMatch(%q) = %v, want %v", tt.header, got, tt.want)
+			}
+		})
+	}
+}
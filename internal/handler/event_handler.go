@@ -0,0 +1,138 @@
+package handler
+
+import (
+	"bufio"
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/Pallavi566/Go-Backend/internal/audit"
+	"github.com/Pallavi566/Go-Backend/internal/events"
+	"go.uber.org/zap"
+)
+
+const (
+	// heartbeatInterval is how often an idle stream is pinged.
+	heartbeatInterval = 15 * time.Second
+	// reconnectDelay is the reconnection delay suggested to clients, in
+	// milliseconds.
+	reconnectDelay = 3000
+	// backlogTimeout bounds each query for events missed while a client was
+	// disconnected.
+	backlogTimeout = 10 * time.Second
+)
+
+type EventHandler struct {
+	feed   *events.Feed
+	logger *zap.Logger
+}
+
+func NewEventHandler(feed *events.Feed, logger *zap.Logger) *EventHandler {
+	return &EventHandler{
+		feed:   feed,
+		logger: logger,
+	}
+}
+
+// StreamUserEvents streams user events as Server-Sent Events. A client
+// resuming after a disconnect sends the last event ID it received in the
+// Last-Event-ID header (or the last_event_id query parameter, for clients
+// that cannot set headers) and first receives every event it missed.
+// Without one, only events from now on are streamed.
+func (h *EventHandler) StreamUserEvents(c *fiber.Ctx) error {
+	resume := false
+	var lastID int64
+	lastEventID := c.Get("Last-Event-ID", c.Query("last_event_id"))
+	if lastEventID != "" {
+		id, err := strconv.ParseInt(lastEventID, 10, 64)
+		if err != nil || id < 0 {
+			return invalid("invalid_last_event_id", "Invalid Last-Event-ID")
+		}
+		resume, lastID = true, id
+	}
+
+	// Subscribe before reading the backlog so that no event falls between
+	// the two; events seen in both are skipped by ID below.
+	sub, err := h.feed.Subscribe()
+	if err != nil {
+		return err
+	}
+
+	c.Set(fiber.HeaderContentType, "text/event-stream")
+	c.Set(fiber.HeaderCacheControl, "no-cache")
+	c.Set(fiber.HeaderConnection, "keep-alive")
+	c.Set("X-Accel-Buffering", "no")
+
+	logger := h.logger.With(zap.String("request_id", audit.RequestID(c.UserContext())))
+
+	// The request context ends when the handler returns, before the stream
+	// is written, so the stream is bounded by the subscription instead: it
+	// ends when the client goes away or the feed shuts down.
+	c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		defer sub.Close()
+
+		if err := events.WriteRetry(w, reconnectDelay); err != nil {
+			return
+		}
+		if resume {
+			if lastID, err = h.writeBacklog(w, lastID); err != nil {
+				logger.Warn("Failed to replay user events", zap.Error(err))
+				return
+			}
+		}
+		if err := w.Flush(); err != nil {
+			return
+		}
+
+		heartbeat := time.NewTicker(heartbeatInterval)
+		defer heartbeat.Stop()
+		for {
+			select {
+			case e, ok := <-sub.Events():
+				if !ok {
+					return
+				}
+				if e.ID <= lastID {
+					continue
+				}
+				if err := events.WriteEvent(w, e); err != nil {
+					return
+				}
+				lastID = e.ID
+			case <-heartbeat.C:
+				if err := events.WriteHeartbeat(w); err != nil {
+					return
+				}
+			}
+			if err := w.Flush(); err != nil {
+				logger.Debug("User event stream closed", zap.Int64("last_event_id", lastID))
+				return
+			}
+		}
+	})
+	return nil
+}
+
+// writeBacklog writes every event after lastID and returns the ID of the
+// last one written.
+func (h *EventHandler) writeBacklog(w *bufio.Writer, lastID int64) (int64, error) {
+	const pageSize = 100
+	for {
+		ctx, cancel := context.WithTimeout(context.Background(), backlogTimeout)
+		backlog, err := h.feed.Backlog(ctx, lastID, pageSize)
+		cancel()
+		if err != nil {
+			return lastID, err
+		}
+		for _, e := range backlog {
+			if err := events.WriteEvent(w, e); err != nil {
+				return lastID, err
+			}
+			lastID = e.ID
+		}
+		if len(backlog) < pageSize {
+			return lastID, nil
+		}
+	}
+}
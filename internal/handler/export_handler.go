@@ -0,0 +1,107 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/Pallavi566/Go-Backend/internal/models"
+	"github.com/Pallavi566/Go-Backend/internal/service"
+	"go.uber.org/zap"
+)
+
+type ExportHandler struct {
+	service  *service.ExportJobService
+	validate *validator.Validate
+	logger   *zap.Logger
+}
+
+func NewExportHandler(service *service.ExportJobService, logger *zap.Logger) *ExportHandler {
+	return &ExportHandler{
+		service:  service,
+		validate: newValidator(),
+		logger:   logger,
+	}
+}
+
+// CreateExport queues an export of the users matching the given filters.
+// The job runs in the background; its status is polled at the URL in the
+// Location header.
+func (h *ExportHandler) CreateExport(c *fiber.Ctx) error {
+	ctx := c.UserContext()
+	var req models.CreateExportRequest
+	if err := c.BodyParser(&req); err != nil {
+		return errInvalidBody
+	}
+
+	if err := h.validate.Struct(req); err != nil {
+		return service.ValidationError(ctx, err)
+	}
+
+	job, err := h.service.CreateJob(ctx, req)
+	if err != nil {
+		return err
+	}
+
+	h.logger.Info("Export job queued", zap.Int64("job_id", job.ID), zap.String("format", job.Format))
+	c.Location(fmt.Sprintf("/api/exports/%d", job.ID))
+	return c.Status(fiber.StatusAccepted).JSON(job)
+}
+
+// GetExport reports the status and progress of an export job. Once the job
+// has succeeded, download_url points at the file. Only the actor that
+// requested the job can see it; others get 404.
+func (h *ExportHandler) GetExport(c *fiber.Ctx) error {
+	ctx := c.UserContext()
+	id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+	if err != nil {
+		return errInvalidExportID
+	}
+
+	job, err := h.service.GetJob(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if job.Status == models.ExportSucceeded {
+		job.DownloadURL = fmt.Sprintf("/api/exports/%d/download", job.ID)
+	}
+	return c.JSON(job)
+}
+
+// DownloadExport serves the file of a succeeded export job to the actor that
+// requested it, until the job expires.
+func (h *ExportHandler) DownloadExport(c *fiber.Ctx) error {
+	ctx := c.UserContext()
+	id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+	if err != nil {
+		return errInvalidExportID
+	}
+
+	job, err := h.service.GetDownload(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	f, err := os.Open(job.FilePath)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return fiber.NewError(fiber.StatusGone, "Export file no longer exists")
+		}
+		return fmt.Errorf("open export file of job %d: %w", id, err)
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return fmt.Errorf("stat export file of job %d: %w", id, err)
+	}
+
+	c.Attachment(fmt.Sprintf("users-export-%d%s", job.ID, filepath.Ext(job.FilePath)))
+	c.Set(fiber.HeaderContentType, service.ExportFormat(job.Format).ContentType())
+	// fasthttp closes the file once it has been sent.
+	return c.SendStream(f, int(info.Size()))
+}
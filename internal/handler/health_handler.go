@@ -0,0 +1,35 @@
+package handler
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/Pallavi566/Go-Backend/internal/health"
+	"github.com/Pallavi566/Go-Backend/internal/models"
+)
+
+type HealthHandler struct {
+	checks *health.Registry
+}
+
+// NewHealthHandler returns a handler serving the reports of checks. Failed
+// checks are logged by the registry.
+func NewHealthHandler(checks *health.Registry) *HealthHandler {
+	return &HealthHandler{checks: checks}
+}
+
+// Live answers 200 as long as the server can answer at all. It checks no
+// dependency, so that a database outage does not get the server restarted.
+func (h *HealthHandler) Live(c *fiber.Ctx) error {
+	return c.JSON(fiber.Map{"status": models.HealthOK})
+}
+
+// Ready runs the dependency checks and answers 200 when all pass, or 503
+// when one fails or the server is draining, with the status and latency of
+// each check.
+func (h *HealthHandler) Ready(c *fiber.Ctx) error {
+	c.Set(fiber.HeaderCacheControl, "no-store")
+	report := h.checks.Run(c.UserContext())
+	if report.Status == models.HealthOK {
+		return c.JSON(report)
+	}
+	return c.Status(fiber.StatusServiceUnavailable).JSON(report)
+}
@@ -0,0 +1,42 @@
+package handler
+
+import (
+	"fmt"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/Pallavi566/Go-Backend/internal/service"
+	"go.uber.org/zap"
+)
+
+type StatsHandler struct {
+	service *service.StatsService
+	logger  *zap.Logger
+}
+
+func NewStatsHandler(service *service.StatsService, logger *zap.Logger) *StatsHandler {
+	return &StatsHandler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+// GetUserStats returns statistics of the active users: their number, age
+// histogram, mean and median age, birth decades and signups per period.
+// buckets lists the lower bounds of the age buckets, interval is day, week
+// or month, and since is the YYYY-MM-DD date signups are counted from.
+func (h *StatsHandler) GetUserStats(c *fiber.Ctx) error {
+	opts, err := h.service.ParseOptions(c.Query("buckets"), c.Query("interval"), c.Query("since"))
+	if err != nil {
+		return err
+	}
+
+	stats, err := h.service.GetUserStats(c.UserContext(), opts)
+	if err != nil {
+		return err
+	}
+
+	if ttl := h.service.TTL(); ttl > 0 {
+		c.Set(fiber.HeaderCacheControl, fmt.Sprintf("private, max-age=%d", int(ttl.Seconds())))
+	}
+	return c.JSON(stats)
+}
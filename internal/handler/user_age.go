@@ -0,0 +1,86 @@
+package handler
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/Pallavi566/Go-Backend/internal/audit"
+	"github.com/Pallavi566/Go-Backend/internal/models"
+	"github.com/Pallavi566/Go-Backend/internal/service"
+	"go.uber.org/zap"
+)
+
+// GetUserAge returns the age of a user on the YYYY-MM-DD date given in
+// as_of, today in the user's time zone by default. With min_age it also
+// reports whether the user had reached it, like CheckUserAge.
+func (h *UserHandler) GetUserAge(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return errInvalidUserID
+	}
+
+	var minAge *int
+	if v := c.Query("min_age"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || h.validate.Var(n, "min=0,max=200") != nil {
+			return invalid("invalid_min_age", "Invalid min_age. Expected a whole number from 0 to 200")
+		}
+		minAge = &n
+	}
+
+	return h.checkAge(c, id, c.Query("as_of"), minAge)
+}
+
+// CheckUserAge answers whether a user had reached min_age on the as_of
+// date, today in the user's time zone by default.
+func (h *UserHandler) CheckUserAge(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return errInvalidUserID
+	}
+
+	var req models.AgeCheckRequest
+	if err := c.BodyParser(&req); err != nil {
+		return errInvalidBody
+	}
+	if err := h.validate.Struct(req); err != nil {
+		return service.ValidationError(c.UserContext(), err)
+	}
+
+	return h.checkAge(c, id, req.AsOf, req.MinAge)
+}
+
+// checkAge computes and answers an age lookup or check. Every check is also
+// logged, with who asked, for compliance.
+func (h *UserHandler) checkAge(c *fiber.Ctx, id int, asOf string, minAge *int) error {
+	ctx := c.UserContext()
+
+	var on *time.Time
+	if asOf != "" {
+		t, err := time.Parse("2006-01-02", asOf)
+		if err != nil {
+			return invalid("invalid_as_of", "Invalid as_of. Expected YYYY-MM-DD")
+		}
+		on = &t
+	}
+
+	check, err := h.service.CheckAge(ctx, id, on, minAge)
+	if err != nil {
+		return err
+	}
+
+	fields := []zap.Field{
+		zap.Int("user_id", id),
+		zap.String("as_of", check.AsOf),
+		zap.Int("age", check.Age),
+		zap.String("actor", audit.Actor(ctx)),
+		zap.String("request_id", audit.RequestID(ctx)),
+	}
+	if check.MinAge != nil {
+		fields = append(fields, zap.Int("min_age", *check.MinAge), zap.Bool("meets_min_age", *check.MeetsMinAge))
+	}
+	h.logger.Info("User age checked", fields...)
+
+	return c.JSON(check)
+}
@@ -0,0 +1,145 @@
+package handler
+
+import (
+	"context"
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/Pallavi566/Go-Backend/internal/apperr"
+	"github.com/Pallavi566/Go-Backend/internal/middleware"
+	"github.com/Pallavi566/Go-Backend/internal/models"
+	"github.com/Pallavi566/Go-Backend/internal/service"
+	"go.uber.org/zap"
+)
+
+// batchAbortedCode is the code of operations not applied because another
+// operation of an atomic batch failed.
+const batchAbortedCode = "batch_aborted"
+
+// BatchUsers applies a list of create, update and delete operations and
+// reports a result per operation. An atomic batch answers 200 when every
+// operation was applied and 422 when none was; a best-effort batch answers
+// 200 when every operation succeeded and 207 otherwise.
+func (h *UserHandler) BatchUsers(c *fiber.Ctx) error {
+	ctx := c.UserContext()
+	var req models.BatchRequest
+	if err := c.BodyParser(&req); err != nil {
+		return errInvalidBody
+	}
+
+	if err := h.validate.Struct(req); err != nil {
+		return service.ValidationError(ctx, err)
+	}
+	if req.Mode == "" {
+		req.Mode = models.BatchAtomic
+	}
+	atomic := req.Mode == models.BatchAtomic
+
+	resp := models.BatchResponse{
+		Mode:    req.Mode,
+		Results: make([]models.BatchResult, len(req.Operations)),
+	}
+
+	// Validate every operation up front, so that an atomic batch with an
+	// invalid operation is rejected without touching the database.
+	var valid []int
+	for i, op := range req.Operations {
+		resp.Results[i] = models.BatchResult{Index: i, Op: op.Op, ID: op.ID}
+		if err := h.validateBatchOperation(ctx, op); err != nil {
+			resp.Results[i].Status = fiber.StatusBadRequest
+			resp.Results[i].Code = apperr.ErrValidation.Code
+			resp.Results[i].Error = err.Error()
+			var appErr *apperr.Error
+			if errors.As(err, &appErr) {
+				resp.Results[i].Errors = appErr.Fields
+			}
+			continue
+		}
+		valid = append(valid, i)
+	}
+
+	if atomic && len(valid) < len(req.Operations) {
+		for _, i := range valid {
+			resp.Results[i].Status = fiber.StatusFailedDependency
+			resp.Results[i].Code = batchAbortedCode
+			resp.Results[i].Error = service.ErrBatchAborted.Error()
+		}
+		resp.Failed = len(req.Operations)
+		return c.Status(fiber.StatusUnprocessableEntity).JSON(resp)
+	}
+
+	ops := make([]models.BatchOperation, len(valid))
+	for j, i := range valid {
+		ops[j] = req.Operations[i]
+	}
+	outcomes, committed := h.service.BatchUsers(ctx, ops, atomic)
+	resp.Committed = committed
+
+	for j, outcome := range outcomes {
+		result := &resp.Results[valid[j]]
+		if outcome.Err != nil {
+			result.Status, result.Code, result.Error = h.batchError(outcome.Err, result)
+			continue
+		}
+		result.ID = outcome.ID
+		result.Version = outcome.Version
+		switch result.Op {
+		case models.BatchCreate:
+			result.Status = fiber.StatusCreated
+		case models.BatchUpdate:
+			result.Status = fiber.StatusOK
+		case models.BatchDelete:
+			result.Status = fiber.StatusNoContent
+		}
+	}
+
+	for _, result := range resp.Results {
+		if result.Error == "" {
+			resp.Succeeded++
+		} else {
+			resp.Failed++
+		}
+	}
+
+	h.logger.Info("User batch processed",
+		zap.String("mode", resp.Mode),
+		zap.Int("succeeded", resp.Succeeded),
+		zap.Int("failed", resp.Failed),
+	)
+
+	status := fiber.StatusOK
+	switch {
+	case resp.Failed == 0:
+	case atomic:
+		status = fiber.StatusUnprocessableEntity
+	default:
+		status = fiber.StatusMultiStatus
+	}
+	return c.Status(status).JSON(resp)
+}
+
+// validateBatchOperation returns the validation error to report for an
+// invalid operation, or nil when it is valid. User data is validated exactly
+// like a single create request.
+func (h *UserHandler) validateBatchOperation(ctx context.Context, op models.BatchOperation) error {
+	if err := h.validate.Struct(op); err != nil {
+		return service.ValidationError(ctx, err)
+	}
+	if op.Data != nil && op.Op != models.BatchDelete {
+		return service.CheckCreateUser(ctx, *op.Data)
+	}
+	return nil
+}
+
+// batchError maps the error of a failed operation to the status, code and
+// message a single request would have received.
+func (h *UserHandler) batchError(err error, result *models.BatchResult) (int, string, string) {
+	if errors.Is(err, service.ErrBatchAborted) {
+		return fiber.StatusFailedDependency, batchAbortedCode, err.Error()
+	}
+	p := middleware.NewProblem(err)
+	if p.Status >= fiber.StatusInternalServerError {
+		h.logger.Error("Batch operation failed", zap.Int("index", result.Index), zap.String("op", result.Op), zap.Error(err))
+	}
+	return p.Status, p.Code, p.Detail
+}
@@ -0,0 +1,40 @@
+package handler
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Pallavi566/Go-Backend/internal/models"
+	"go.uber.org/zap"
+)
+
+func TestValidateBatchOperation(t *testing.T) {
+	h := NewUserHandler(nil, zap.NewNop())
+	alice := &models.CreateUserRequest{Name: "Alice", DOB: "1990-05-10"}
+
+	tests := []struct {
+		name    string
+		op      models.BatchOperation
+		wantErr bool
+	}{
+		{"Create", models.BatchOperation{Op: "create", Data: alice}, false},
+		{"Create without data", models.BatchOperation{Op: "create"}, true},
+		{"Create with bad date", models.BatchOperation{Op: "create", Data: &models.CreateUserRequest{Name: "Alice", DOB: "10/05/1990"}}, true},
+		{"Create without name", models.BatchOperation{Op: "create", Data: &models.CreateUserRequest{DOB: "1990-05-10"}}, true},
+		{"Update", models.BatchOperation{Op: "update", ID: 3, Version: 2, Data: alice}, false},
+		{"Update without id", models.BatchOperation{Op: "update", Data: alice}, true},
+		{"Update without data", models.BatchOperation{Op: "update", ID: 3}, true},
+		{"Delete", models.BatchOperation{Op: "delete", ID: 3}, false},
+		{"Delete without id", models.BatchOperation{Op: "delete"}, true},
+		{"Unknown op", models.BatchOperation{Op: "upsert", ID: 3, Data: alice}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := h.validateBatchOperation(context.Background(), tt.op)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("validateBatchOperation() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,39 @@
+package handler
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/Pallavi566/Go-Backend/internal/service"
+)
+
+// birthdaysQuery holds the query parameters of GetBirthdays.
+type birthdaysQuery struct {
+	From  string `query:"from"`
+	To    string `query:"to"`
+	Page  int    `query:"page" validate:"min=1"`
+	Limit int    `query:"limit" validate:"min=1,max=100"`
+}
+
+// GetBirthdays lists the users whose birthday falls between the from and to
+// dates (YYYY-MM-DD, inclusive), in order of their birthday. The window may
+// span New Year; it defaults to the coming week.
+func (h *UserHandler) GetBirthdays(c *fiber.Ctx) error {
+	ctx, err := includeContext(c)
+	if err != nil {
+		return err
+	}
+
+	q := birthdaysQuery{Page: 1, Limit: 100}
+	if err := c.QueryParser(&q); err != nil {
+		return errInvalidQuery
+	}
+	if err := h.validate.Struct(q); err != nil {
+		return service.ValidationError(c.UserContext(), err)
+	}
+
+	result, err := h.service.GetBirthdays(ctx, q.From, q.To, q.Page, q.Limit)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(result)
+}
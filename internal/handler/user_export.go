@@ -0,0 +1,67 @@
+package handler
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/Pallavi566/Go-Backend/internal/models"
+	"github.com/Pallavi566/Go-Backend/internal/service"
+	"go.uber.org/zap"
+)
+
+// exportTimeout bounds how long a single export may stream.
+const exportTimeout = time.Hour
+
+// ExportUsers streams every user matching the listing filters as CSV, NDJSON
+// or XLSX, chosen with the format query parameter (default csv). Rows are
+// written to the response as they are read, so memory use does not depend
+// on the number of users. XLSX exports of more users than a sheet holds are
+// rejected with 422 before anything is sent.
+func (h *UserHandler) ExportUsers(c *fiber.Ctx) error {
+	format, err := service.ParseExportFormat(c.Query("format", string(service.ExportCSV)))
+	if err != nil {
+		return err
+	}
+
+	var filter models.UserFilter
+	if err := c.QueryParser(&filter); err != nil {
+		return errInvalidQuery
+	}
+
+	if err := h.validate.Struct(filter); err != nil {
+		return service.ValidationError(c.UserContext(), err)
+	}
+
+	export, err := h.service.NewExport(c.UserContext(), filter, format)
+	if err != nil {
+		return err
+	}
+
+	filename := fmt.Sprintf("users-%s.%s", time.Now().UTC().Format("20060102-150405"), format)
+	c.Set(fiber.HeaderContentType, format.ContentType())
+	c.Set(fiber.HeaderContentDisposition, fmt.Sprintf(`attachment; filename="%s"`, filename))
+
+	// The request context is cancelled as soon as the handler returns, before
+	// the body is streamed, so the export runs on a detached context that
+	// keeps the request's values. A client that goes away makes the next
+	// write fail, which stops the query.
+	ctx := context.WithoutCancel(c.UserContext())
+	c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		ctx, cancel := context.WithTimeout(ctx, exportTimeout)
+		defer cancel()
+
+		start := time.Now()
+		if err := export.WriteTo(ctx, w); err != nil {
+			h.logger.Error("User export failed", zap.String("format", string(format)), zap.Error(err))
+			return
+		}
+		if err := w.Flush(); err != nil {
+			return
+		}
+		h.logger.Info("Users exported", zap.String("format", string(format)), zap.Duration("duration", time.Since(start)))
+	})
+	return nil
+}
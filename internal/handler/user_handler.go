@@ -1,7 +1,12 @@
 package handler
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/url"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/go-playground/validator/v10"
@@ -20,42 +25,28 @@ type UserHandler struct {
 func NewUserHandler(service *service.UserService, logger *zap.Logger) *UserHandler {
 	return &UserHandler{
 		service:  service,
-		validate: validator.New(),
+		validate: newValidator(),
 		logger:   logger,
 	}
 }
 
 func (h *UserHandler) CreateUser(c *fiber.Ctx) error {
-	ctx := c.UserContext()
+	ctx, err := includeContext(c)
+	if err != nil {
+		return err
+	}
 	var req models.CreateUserRequest
 	if err := c.BodyParser(&req); err != nil {
-		h.logger.Error("Failed to parse request body", zap.Error(err))
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "Invalid request body",
-		})
+		return errInvalidBody
 	}
 
-	if err := h.validate.Struct(req); err != nil {
-		h.logger.Error("Validation failed", zap.Error(err))
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": err.Error(),
-		})
-	}
-
-	// Validate date format
-	if _, err := time.Parse("2006-01-02", req.DOB); err != nil {
-		h.logger.Error("Invalid date format", zap.String("dob", req.DOB), zap.Error(err))
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "Invalid date format. Expected YYYY-MM-DD",
-		})
+	if err := service.CheckCreateUser(c.UserContext(), req); err != nil {
+		return err
 	}
 
 	user, err := h.service.CreateUser(ctx, req)
 	if err != nil {
-		h.logger.Error("Failed to create user", zap.Error(err))
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"error": "Failed to create user",
-		})
+		return err
 	}
 
 	h.logger.Info("User created", zap.Int("user_id", user.ID))
@@ -63,131 +54,268 @@ func (h *UserHandler) CreateUser(c *fiber.Ctx) error {
 }
 
 func (h *UserHandler) GetUserByID(c *fiber.Ctx) error {
-	ctx := c.UserContext()
+	ctx, err := includeContext(c)
+	if err != nil {
+		return err
+	}
 	id, err := strconv.Atoi(c.Params("id"))
 	if err != nil {
-		h.logger.Error("Invalid user ID", zap.Error(err))
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "Invalid user ID",
-		})
+		return errInvalidUserID
+	}
+
+	var user *models.UserResponse
+	if asOf := c.Query("as_of"); asOf != "" {
+		at, err := time.Parse(time.RFC3339, asOf)
+		if err != nil {
+			return invalid("invalid_as_of", "Invalid as_of. Expected an RFC 3339 timestamp")
+		}
+		if at.After(time.Now()) {
+			return invalid("invalid_as_of", "as_of must not be in the future")
+		}
+		user, err = h.service.GetUserByIDAsOf(ctx, id, at)
+	} else {
+		user, err = h.service.GetUserByID(ctx, id)
+	}
+	if err != nil {
+		return err
+	}
+
+	return sendUser(c, user)
+}
+
+// GetUserByEmail looks up an active user by email address.
+func (h *UserHandler) GetUserByEmail(c *fiber.Ctx) error {
+	ctx, err := includeContext(c)
+	if err != nil {
+		return err
+	}
+	email, err := url.PathUnescape(c.Params("email"))
+	if err != nil || h.validate.Var(email, "required,email") != nil {
+		return invalid("invalid_email", "Invalid email")
+	}
+
+	user, err := h.service.GetUserByEmail(ctx, email)
+	if err != nil {
+		return err
 	}
 
-	user, err := h.service.GetUserByID(ctx, id)
+	return sendUser(c, user)
+}
+
+// sendUser answers with user, tagged with the entity tag of its
+// representation, so that reads and writes of the same representation are
+// tagged alike. A read is answered with 304 Not Modified when the client
+// already has it.
+func sendUser(c *fiber.Ctx, user *models.UserResponse) error {
+	body, err := json.Marshal(user)
 	if err != nil {
-		h.logger.Error("User not found", zap.Int("user_id", id), zap.Error(err))
-		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
-			"error": "User not found",
-		})
+		return err
+	}
+	tag := representationETag(user.Version, body)
+	c.Set(fiber.HeaderETag, tag)
+	read := c.Method() == fiber.MethodGet || c.Method() == fiber.MethodHead
+	if read && noneMatch(c.Get(fiber.HeaderIfNoneMatch), tag) {
+		return c.SendStatus(fiber.StatusNotModified)
 	}
 
-	return c.JSON(user)
+	c.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+	return c.Send(body)
 }
 
 func (h *UserHandler) GetAllUsers(c *fiber.Ctx) error {
-	ctx := c.UserContext()
+	ctx, err := includeContext(c)
+	if err != nil {
+		return err
+	}
 	users, err := h.service.GetAllUsers(ctx)
 	if err != nil {
-		h.logger.Error("Failed to get users", zap.Error(err))
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"error": "Failed to get users",
-		})
+		return err
 	}
 
 	return c.JSON(users)
 }
 
 func (h *UserHandler) UpdateUser(c *fiber.Ctx) error {
-	ctx := c.UserContext()
+	ctx, err := includeContext(c)
+	if err != nil {
+		return err
+	}
 	id, err := strconv.Atoi(c.Params("id"))
 	if err != nil {
-		h.logger.Error("Invalid user ID", zap.Error(err))
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "Invalid user ID",
-		})
+		return errInvalidUserID
 	}
 
 	var req models.UpdateUserRequest
 	if err := c.BodyParser(&req); err != nil {
-		h.logger.Error("Failed to parse request body", zap.Error(err))
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "Invalid request body",
-		})
-	}
-
-	if err := h.validate.Struct(req); err != nil {
-		h.logger.Error("Validation failed", zap.Error(err))
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": err.Error(),
-		})
-	}
-
-	// Validate date format if DOB is provided
-	if req.DOB != "" {
-		if _, err := time.Parse("2006-01-02", req.DOB); err != nil {
-			h.logger.Error("Invalid date format", zap.String("dob", req.DOB), zap.Error(err))
-			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-				"error": "Invalid date format. Expected YYYY-MM-DD",
-			})
-		}
+		return errInvalidBody
 	}
 
-	user, err := h.service.UpdateUser(ctx, id, req)
+	if err := service.CheckUpdateUser(c.UserContext(), req); err != nil {
+		return err
+	}
+
+	user, err := h.service.UpdateUser(ctx, id, req, ifMatchVersions(c.Get(fiber.HeaderIfMatch)))
 	if err != nil {
-		h.logger.Error("Failed to update user", zap.Int("user_id", id), zap.Error(err))
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"error": "Failed to update user",
-		})
+		h.logVersionError(id, err)
+		return err
 	}
 
 	h.logger.Info("User updated", zap.Int("user_id", id))
-	return c.JSON(user)
+	return sendUser(c, user)
+}
+
+// PatchUser applies a partial update. The body is interpreted according to
+// its Content-Type as either a JSON Merge Patch or a JSON Patch.
+func (h *UserHandler) PatchUser(c *fiber.Ctx) error {
+	ctx, err := includeContext(c)
+	if err != nil {
+		return err
+	}
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return errInvalidUserID
+	}
+
+	var format service.PatchFormat
+	switch mediaType(c.Get(fiber.HeaderContentType)) {
+	case "application/merge-patch+json":
+		format = service.MergePatch
+	case "application/json-patch+json":
+		format = service.JSONPatch
+	default:
+		return fiber.NewError(fiber.StatusUnsupportedMediaType, "Content-Type must be application/merge-patch+json or application/json-patch+json")
+	}
+
+	user, err := h.service.PatchUser(ctx, id, format, c.Body(), ifMatchVersions(c.Get(fiber.HeaderIfMatch)))
+	if err != nil {
+		h.logVersionError(id, err)
+		return err
+	}
+
+	h.logger.Info("User patched", zap.Int("user_id", id))
+	return sendUser(c, user)
 }
 
 func (h *UserHandler) DeleteUser(c *fiber.Ctx) error {
 	ctx := c.UserContext()
 	id, err := strconv.Atoi(c.Params("id"))
 	if err != nil {
-		h.logger.Error("Invalid user ID", zap.Error(err))
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "Invalid user ID",
-		})
+		return errInvalidUserID
 	}
 
-	if err := h.service.DeleteUser(ctx, id); err != nil {
-		h.logger.Error("Failed to delete user", zap.Int("user_id", id), zap.Error(err))
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"error": "Failed to delete user",
-		})
+	if err := h.service.DeleteUser(ctx, id, ifMatchVersions(c.Get(fiber.HeaderIfMatch))); err != nil {
+		h.logVersionError(id, err)
+		return err
 	}
 
 	h.logger.Info("User deleted", zap.Int("user_id", id))
 	return c.SendStatus(fiber.StatusNoContent)
 }
 
-func (h *UserHandler) GetUsersPaginated(c *fiber.Ctx) error {
+// GetUserHistory lists the recorded changes of a user.
+func (h *UserHandler) GetUserHistory(c *fiber.Ctx) error {
 	ctx := c.UserContext()
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return errInvalidUserID
+	}
+
+	entries, err := h.service.GetUserHistory(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(entries)
+}
+
+// RestoreUser undoes a soft delete.
+func (h *UserHandler) RestoreUser(c *fiber.Ctx) error {
+	ctx, err := includeContext(c)
+	if err != nil {
+		return err
+	}
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return errInvalidUserID
+	}
+
+	user, err := h.service.RestoreUser(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	h.logger.Info("User restored", zap.Int("user_id", id))
+	return sendUser(c, user)
+}
+
+func (h *UserHandler) GetUsersPaginated(c *fiber.Ctx) error {
+	ctx, err := includeContext(c)
+	if err != nil {
+		return err
+	}
 	var params models.PaginationParams
 	if err := c.QueryParser(&params); err != nil {
-		h.logger.Error("Failed to parse query parameters", zap.Error(err))
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "Invalid query parameters",
-		})
+		return errInvalidQuery
 	}
 
 	if err := h.validate.Struct(params); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": err.Error(),
-		})
+		return service.ValidationError(c.UserContext(), err)
 	}
 
-	result, err := h.service.GetUsersPaginated(ctx, params.Page, params.Limit)
+	var filter models.UserFilter
+	if err := c.QueryParser(&filter); err != nil {
+		return errInvalidQuery
+	}
+
+	if err := h.validate.Struct(filter); err != nil {
+		return service.ValidationError(c.UserContext(), err)
+	}
+
+	var result *models.PaginatedResponse
+	if c.Context().QueryArgs().Has("cursor") {
+		if params.Page != 0 {
+			return invalid("invalid_query", "page cannot be combined with cursor")
+		}
+		result, err = h.service.GetUsersByCursor(ctx, params.Cursor, params.Limit, filter)
+	} else {
+		if params.Page == 0 {
+			params.Page = 1
+		}
+		result, err = h.service.GetUsersPaginated(ctx, params.Page, params.Limit, filter)
+	}
 	if err != nil {
-		h.logger.Error("Failed to get paginated users", zap.Error(err))
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"error": "Failed to fetch users",
-		})
+		return err
 	}
 
 	return c.JSON(result)
 }
 
+// logVersionError logs optimistic concurrency failures, which are answered
+// like any other error.
+func (h *UserHandler) logVersionError(id int, err error) {
+	switch {
+	case errors.Is(err, service.ErrPreconditionFailed):
+		h.logger.Info("User version precondition failed", zap.Int("user_id", id))
+	case errors.Is(err, service.ErrConcurrentUpdate):
+		h.logger.Info("Concurrent user update", zap.Int("user_id", id))
+	}
+}
+
+// includeContext returns the request context asking for the optional user
+// response fields listed in the include query parameter.
+func includeContext(c *fiber.Ctx) (context.Context, error) {
+	include, err := service.ParseInclude(c.Query("include"))
+	if err != nil {
+		return nil, err
+	}
+	return service.WithInclude(c.UserContext(), include), nil
+}
+
+// mediaType returns the lower-cased media type of a Content-Type header
+// without its parameters.
+func mediaType(contentType string) string {
+	if i := strings.IndexByte(contentType, ';'); i >= 0 {
+		contentType = contentType[:i]
+	}
+	return strings.ToLower(strings.TrimSpace(contentType))
+}
@@ -0,0 +1,74 @@
+package handler
+
+import (
+	"bytes"
+	"io"
+	"path/filepath"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/Pallavi566/Go-Backend/internal/service"
+	"go.uber.org/zap"
+)
+
+// ImportUsers creates users from a CSV or NDJSON file of name,dob rows and
+// returns a report listing every rejected line. The file is either the raw
+// request body or the "file" field of a multipart form. The format is taken
+// from the format query parameter, else the uploaded file's extension, else
+// the Content-Type. With ?dry_run=true rows are only validated and their
+// emails checked against existing users.
+func (h *UserHandler) ImportUsers(c *fiber.Ctx) error {
+	ctx := c.UserContext()
+
+	var body io.Reader
+	formatName := c.Query("format")
+	switch mediaType(c.Get(fiber.HeaderContentType)) {
+	case fiber.MIMEMultipartForm:
+		fh, err := c.FormFile("file")
+		if err != nil {
+			return invalid("missing_file", "Missing file field in multipart form")
+		}
+		f, err := fh.Open()
+		if err != nil {
+			h.logger.Error("Failed to open uploaded file", zap.Error(err))
+			return invalid("invalid_file", "Invalid uploaded file")
+		}
+		defer f.Close()
+		body = f
+		if formatName == "" {
+			formatName = filepath.Ext(fh.Filename)
+		}
+	case "text/csv":
+		body = bytes.NewReader(c.Body())
+		if formatName == "" {
+			formatName = "csv"
+		}
+	case "application/x-ndjson", "application/jsonl":
+		body = bytes.NewReader(c.Body())
+		if formatName == "" {
+			formatName = "ndjson"
+		}
+	default:
+		body = bytes.NewReader(c.Body())
+	}
+
+	format, err := service.ParseImportFormat(formatName)
+	if err != nil {
+		return fiber.NewError(fiber.StatusUnsupportedMediaType, "Unsupported import format. Use CSV (text/csv) or NDJSON (application/x-ndjson)")
+	}
+
+	report, err := h.service.ImportUsers(ctx, body, format, service.ImportOptions{
+		DryRun: c.QueryBool("dry_run"),
+	})
+	if err != nil {
+		return err
+	}
+
+	h.logger.Info("Users imported",
+		zap.String("format", report.Format),
+		zap.Bool("dry_run", report.DryRun),
+		zap.Int("rows", report.Rows),
+		zap.Int("imported", report.Imported),
+		zap.Int("failed", report.Failed),
+	)
+	return c.JSON(report)
+}
@@ -0,0 +1,143 @@
+package handler
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/Pallavi566/Go-Backend/internal/models"
+	"github.com/Pallavi566/Go-Backend/internal/service"
+	"go.uber.org/zap"
+)
+
+type WebhookHandler struct {
+	service  *service.WebhookService
+	validate *validator.Validate
+	logger   *zap.Logger
+}
+
+func NewWebhookHandler(service *service.WebhookService, logger *zap.Logger) *WebhookHandler {
+	return &WebhookHandler{
+		service:  service,
+		validate: newValidator(),
+		logger:   logger,
+	}
+}
+
+func (h *WebhookHandler) CreateSubscription(c *fiber.Ctx) error {
+	ctx := c.UserContext()
+	var req models.CreateWebhookRequest
+	if err := c.BodyParser(&req); err != nil {
+		return errInvalidBody
+	}
+
+	if err := h.validate.Struct(req); err != nil {
+		return service.ValidationError(ctx, err)
+	}
+
+	sub, err := h.service.CreateSubscription(ctx, req)
+	if err != nil {
+		return err
+	}
+
+	h.logger.Info("Webhook subscription created", zap.Int("subscription_id", sub.ID), zap.String("url", sub.URL))
+	return c.Status(fiber.StatusCreated).JSON(sub)
+}
+
+func (h *WebhookHandler) ListSubscriptions(c *fiber.Ctx) error {
+	ctx := c.UserContext()
+	subs, err := h.service.ListSubscriptions(ctx)
+	if err != nil {
+		return err
+	}
+	return c.JSON(subs)
+}
+
+func (h *WebhookHandler) GetSubscription(c *fiber.Ctx) error {
+	ctx := c.UserContext()
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return errInvalidSubscriptionID
+	}
+
+	sub, err := h.service.GetSubscription(ctx, id)
+	if err != nil {
+		return err
+	}
+	return c.JSON(sub)
+}
+
+func (h *WebhookHandler) DisableSubscription(c *fiber.Ctx) error {
+	return h.setActive(c, false)
+}
+
+func (h *WebhookHandler) EnableSubscription(c *fiber.Ctx) error {
+	return h.setActive(c, true)
+}
+
+func (h *WebhookHandler) setActive(c *fiber.Ctx, active bool) error {
+	ctx := c.UserContext()
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return errInvalidSubscriptionID
+	}
+
+	sub, err := h.service.SetSubscriptionActive(ctx, id, active)
+	if err != nil {
+		return err
+	}
+
+	h.logger.Info("Webhook subscription updated", zap.Int("subscription_id", id), zap.Bool("active", active))
+	return c.JSON(sub)
+}
+
+// ListDeliveries returns the most recent deliveries of a subscription. The
+// limit query parameter defaults to 50 and is capped at 100.
+func (h *WebhookHandler) ListDeliveries(c *fiber.Ctx) error {
+	ctx := c.UserContext()
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return errInvalidSubscriptionID
+	}
+
+	limit := c.QueryInt("limit", 50)
+	if limit < 1 || limit > 100 {
+		return invalid("invalid_limit", "limit must be between 1 and 100")
+	}
+
+	deliveries, err := h.service.ListDeliveries(ctx, id, limit)
+	if err != nil {
+		return err
+	}
+	return c.JSON(deliveries)
+}
+
+// ReplayDeliveries re-sends dead-lettered deliveries of a subscription, or,
+// with the since query parameter (RFC 3339), every delivery created since.
+func (h *WebhookHandler) ReplayDeliveries(c *fiber.Ctx) error {
+	ctx := c.UserContext()
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return errInvalidSubscriptionID
+	}
+
+	var since *time.Time
+	if s := c.Query("since"); s != "" {
+		t, err := time.Parse(time.RFC3339, s)
+		if err != nil {
+			return invalid("invalid_since", "Invalid since. Expected an RFC 3339 timestamp")
+		}
+		since = &t
+	}
+
+	replayed, err := h.service.ReplayDeliveries(ctx, id, since)
+	if err != nil {
+		return err
+	}
+
+	h.logger.Info("Webhook deliveries replayed", zap.Int("subscription_id", id), zap.Int64("count", replayed))
+	return c.Status(fiber.StatusAccepted).JSON(fiber.Map{
+		"replayed": replayed,
+	})
+}
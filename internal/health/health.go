@@ -0,0 +1,135 @@
+// Package health runs the checks that decide whether the server is ready to
+// take traffic: one per dependency, such as the database.
+package health
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/Pallavi566/Go-Backend/internal/models"
+	"go.uber.org/zap"
+)
+
+// Check reports whether a dependency is usable. It must give up when ctx is
+// done.
+type Check func(ctx context.Context) error
+
+// Registry holds the checks of the server's dependencies. Checks can be
+// registered at any time; it is safe for concurrent use.
+type Registry struct {
+	timeout  time.Duration
+	logger   *zap.Logger
+	draining atomic.Bool
+
+	mu     sync.RWMutex
+	checks map[string]Check
+}
+
+// NewRegistry returns a registry that gives each check up to timeout. The
+// errors of failed checks are logged to logger; reports only say whether a
+// check failed or timed out, as they are served to anyone.
+func NewRegistry(timeout time.Duration, logger *zap.Logger) *Registry {
+	return &Registry{timeout: timeout, logger: logger, checks: make(map[string]Check)}
+}
+
+// Register adds a check under name, replacing any check of that name.
+func (r *Registry) Register(name string, check Check) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.checks[name] = check
+}
+
+// Drain marks the server as shutting down. From then on it is reported as
+// not ready, so that traffic is routed elsewhere before it stops.
+func (r *Registry) Drain() {
+	r.draining.Store(true)
+}
+
+// Draining reports whether Drain was called.
+func (r *Registry) Draining() bool {
+	return r.draining.Load()
+}
+
+// Run runs every check concurrently and reports the status and latency of
+// each. The server is ready when all of them pass and it is not draining.
+func (r *Registry) Run(ctx context.Context) models.HealthReport {
+	report := models.HealthReport{Status: models.HealthOK, Checks: map[string]models.CheckResult{}}
+	if r.Draining() {
+		report.Status = models.HealthDraining
+		return report
+	}
+
+	r.mu.RLock()
+	names := make([]string, 0, len(r.checks))
+	for name := range r.checks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	checks := make([]Check, len(names))
+	for i, name := range names {
+		checks[i] = r.checks[name]
+	}
+	r.mu.RUnlock()
+
+	results := make([]models.CheckResult, len(checks))
+	var wg sync.WaitGroup
+	for i, check := range checks {
+		wg.Add(1)
+		go func(i int, check Check) {
+			defer wg.Done()
+			results[i] = r.run(ctx, names[i], check)
+		}(i, check)
+	}
+	wg.Wait()
+
+	for i, name := range names {
+		report.Checks[name] = results[i]
+		if results[i].Status != models.HealthOK {
+			report.Status = models.HealthUnavailable
+		}
+	}
+	return report
+}
+
+// run runs one check within the registry's timeout. A check that overruns
+// it fails even if it does not return.
+func (r *Registry) run(ctx context.Context, name string, check Check) models.CheckResult {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	start := time.Now()
+	done := make(chan error, 1)
+	go func() {
+		defer func() {
+			if p := recover(); p != nil {
+				done <- fmt.Errorf("check panicked: %v", p)
+			}
+		}()
+		done <- check(ctx)
+	}()
+
+	var err error
+	select {
+	case err = <-done:
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+
+	result := models.CheckResult{
+		Status:    models.HealthOK,
+		LatencyMS: float64(time.Since(start).Microseconds()) / 1000,
+	}
+	if err != nil {
+		result.Status = models.HealthUnavailable
+		result.Error = "check failed"
+		if ctx.Err() != nil {
+			result.Error = fmt.Sprintf("timed out after %s", r.timeout)
+		}
+		r.logger.Warn("Health check failed", zap.String("check", name), zap.Error(err))
+	}
+	return result
+}
@@ -0,0 +1,86 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Pallavi566/Go-Backend/internal/models"
+	"go.uber.org/zap"
+)
+
+func TestRegistryRun(t *testing.T) {
+	pass := func(context.Context) error { return nil }
+	fail := func(context.Context) error { return errors.New("connection refused") }
+	hang := func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	}
+	stuck := func(context.Context) error {
+		time.Sleep(time.Second)
+		return nil
+	}
+
+	tests := []struct {
+		name       string
+		checks     map[string]Check
+		want       string
+		wantFailed []string
+		wantError  string
+	}{
+		{"No checks", nil, models.HealthOK, nil, ""},
+		{"All pass", map[string]Check{"database": pass, "migrations": pass}, models.HealthOK, nil, ""},
+		{"One fails", map[string]Check{"database": fail, "migrations": pass}, models.HealthUnavailable, []string{"database"}, "check failed"},
+		{"Times out", map[string]Check{"database": hang}, models.HealthUnavailable, []string{"database"}, "timed out after 20ms"},
+		{"Ignores the timeout", map[string]Check{"database": stuck}, models.HealthUnavailable, []string{"database"}, "timed out after 20ms"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := NewRegistry(20*time.Millisecond, zap.NewNop())
+			for name, check := range tt.checks {
+				r.Register(name, check)
+			}
+
+			start := time.Now()
+			report := r.Run(context.Background())
+			if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+				t.Errorf("Run() took %s, want it bounded by the timeout", elapsed)
+			}
+			if report.Status != tt.want {
+				t.Errorf("status = %q, want %q", report.Status, tt.want)
+			}
+			if len(report.Checks) != len(tt.checks) {
+				t.Errorf("got %d check results, want %d", len(report.Checks), len(tt.checks))
+			}
+			failed := map[string]bool{}
+			for _, name := range tt.wantFailed {
+				failed[name] = true
+			}
+			for name, result := range report.Checks {
+				if (result.Status != models.HealthOK) != failed[name] {
+					t.Errorf("check %s = %+v, want failed %v", name, result, failed[name])
+				}
+				// The error of the check itself is only logged.
+				if failed[name] && result.Error != tt.wantError {
+					t.Errorf("check %s error = %q, want %q", name, result.Error, tt.wantError)
+				}
+			}
+		})
+	}
+}
+
+func TestRegistryDrain(t *testing.T) {
+	r := NewRegistry(time.Second, zap.NewNop())
+	r.Register("database", func(context.Context) error { return nil })
+	r.Drain()
+
+	report := r.Run(context.Background())
+	if report.Status != models.HealthDraining {
+		t.Errorf("status = %q, want %q", report.Status, models.HealthDraining)
+	}
+	if len(report.Checks) != 0 {
+		t.Errorf("checks = %v, want none while draining", report.Checks)
+	}
+}
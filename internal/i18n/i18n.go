@@ -0,0 +1,128 @@
+// Package i18n carries the language a client asked for, from the HTTP layer
+// down to where messages are written, and translates validation messages
+// into it.
+package i18n
+
+import (
+	"context"
+	"strings"
+
+	"github.com/go-playground/locales/en"
+	"github.com/go-playground/locales/es"
+	"github.com/go-playground/locales/fr"
+	"github.com/go-playground/locales/pt"
+	"github.com/go-playground/locales/pt_BR"
+	ut "github.com/go-playground/universal-translator"
+	"github.com/go-playground/validator/v10"
+	en_translations "github.com/go-playground/validator/v10/translations/en"
+	es_translations "github.com/go-playground/validator/v10/translations/es"
+	fr_translations "github.com/go-playground/validator/v10/translations/fr"
+	pt_translations "github.com/go-playground/validator/v10/translations/pt"
+	pt_BR_translations "github.com/go-playground/validator/v10/translations/pt_BR"
+)
+
+// DefaultLanguage is used when a client asks for no language, or for none
+// of the supported ones.
+const DefaultLanguage = "en"
+
+// Languages are the supported languages as BCP 47 tags, the default first.
+// More specific tags come before the tags they extend so that they are
+// preferred when matching Accept-Language.
+var Languages = []string{"en", "es", "fr", "pt-BR", "pt"}
+
+// registrations add the validator's own translations for each language.
+var registrations = map[string]func(*validator.Validate, ut.Translator) error{
+	"en":    en_translations.RegisterDefaultTranslations,
+	"es":    es_translations.RegisterDefaultTranslations,
+	"fr":    fr_translations.RegisterDefaultTranslations,
+	"pt-BR": pt_BR_translations.RegisterDefaultTranslations,
+	"pt":    pt_translations.RegisterDefaultTranslations,
+}
+
+// messages translates the messages of checks the validator does not make.
+// {0} is the name of the field.
+var messages = map[string]map[string]string{
+	"date": {
+		"en":    "{0} must be a date in the YYYY-MM-DD format",
+		"es":    "{0} debe ser una fecha con el formato AAAA-MM-DD",
+		"fr":    "{0} doit être une date au format AAAA-MM-JJ",
+		"pt-BR": "{0} deve ser uma data no formato AAAA-MM-DD",
+		"pt":    "{0} deve ser uma data no formato AAAA-MM-DD",
+	},
+}
+
+var uni = ut.New(en.New(), en.New(), es.New(), fr.New(), pt_BR.New(), pt.New())
+
+type contextKey int
+
+const languageKey contextKey = iota
+
+// WithLanguage returns a copy of ctx carrying the language of the response.
+func WithLanguage(ctx context.Context, lang string) context.Context {
+	return context.WithValue(ctx, languageKey, lang)
+}
+
+// Language returns the language stored in ctx, or DefaultLanguage if there
+// is none.
+func Language(ctx context.Context) string {
+	if lang, ok := ctx.Value(languageKey).(string); ok && lang != "" {
+		return lang
+	}
+	return DefaultLanguage
+}
+
+// RegisterTranslations adds the translations of every supported language to
+// v. Translations are registered once per translator, so it must be called
+// for a single validator.
+func RegisterTranslations(v *validator.Validate) error {
+	for _, lang := range Languages {
+		trans := translator(lang)
+		if err := registrations[lang](v, trans); err != nil {
+			return err
+		}
+		for key, texts := range messages {
+			if err := trans.Add(key, texts[lang], false); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// TranslateField returns the message for a failed check in the language
+// stored in ctx, falling back to English. It reports false when there is no
+// translation for the check in either.
+func TranslateField(ctx context.Context, fe validator.FieldError) (string, bool) {
+	for _, lang := range fallbacks(Language(ctx)) {
+		// Without a translation, the validator's own message is returned.
+		if msg := fe.Translate(translator(lang)); msg != fe.Error() {
+			return msg, true
+		}
+	}
+	return "", false
+}
+
+// Message returns the message with the given key in the language stored in
+// ctx, falling back to English, with {0}, {1}... replaced by params.
+func Message(ctx context.Context, key string, params ...string) string {
+	for _, lang := range fallbacks(Language(ctx)) {
+		if msg, err := translator(lang).T(key, params...); err == nil {
+			return msg
+		}
+	}
+	return key
+}
+
+// fallbacks lists the languages to try for lang, in order.
+func fallbacks(lang string) []string {
+	if lang == DefaultLanguage {
+		return []string{lang}
+	}
+	return []string{lang, DefaultLanguage}
+}
+
+// translator returns the translator of a supported language.
+func translator(lang string) ut.Translator {
+	trans, _ := uni.GetTranslator(strings.ReplaceAll(lang, "-", "_"))
+	return trans
+}
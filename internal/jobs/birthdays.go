@@ -0,0 +1,35 @@
+package jobs
+
+import (
+	"context"
+	"time"
+
+	"github.com/Pallavi566/Go-Backend/internal/service"
+	"go.uber.org/zap"
+)
+
+// RunBirthdayDigest publishes the digest of today's birthdays as a
+// user.birthday_digest event, checking at start-up and then every interval
+// whether today's has gone out yet. Each day's digest is published once,
+// however many servers run the job. It blocks until ctx is cancelled.
+func RunBirthdayDigest(ctx context.Context, userService *service.UserService, interval time.Duration, logger *zap.Logger) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		runCtx, cancel := context.WithTimeout(ctx, time.Minute)
+		count, published, err := userService.PublishBirthdayDigest(runCtx)
+		cancel()
+		if err != nil {
+			logger.Error("Failed to publish birthday digest", zap.Error(err))
+		} else if published {
+			logger.Info("Published birthday digest", zap.Int("count", count))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
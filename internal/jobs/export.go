@@ -0,0 +1,100 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/Pallavi566/Go-Backend/internal/service"
+	"go.uber.org/zap"
+)
+
+// RunExportWorkers runs queued export jobs on a pool of workers goroutines,
+// polling for new jobs every interval. Jobs orphaned by a crashed instance
+// are requeued as they go stale, or failed once out of attempts, and jobs
+// still running when ctx is cancelled are put back in the queue. It blocks
+// until ctx is cancelled and every worker has stopped.
+func RunExportWorkers(ctx context.Context, exports *service.ExportJobService, workers int, interval time.Duration, logger *zap.Logger) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	slots := make(chan struct{}, workers)
+	var wg sync.WaitGroup
+	defer wg.Wait()
+
+	for {
+		if requeued, failed, err := exports.RequeueStaleJobs(ctx); err != nil {
+			logger.Error("Failed to requeue stale export jobs", zap.Error(err))
+		} else {
+			if requeued > 0 {
+				logger.Warn("Requeued stale export jobs", zap.Int64("count", requeued))
+			}
+			if failed > 0 {
+				logger.Error("Failed stale export jobs out of attempts", zap.Int64("count", failed))
+			}
+		}
+
+		if free := workers - len(slots); free > 0 {
+			ids, err := exports.QueuedJobs(ctx, free)
+			if err != nil && ctx.Err() == nil {
+				logger.Error("Failed to list queued export jobs", zap.Error(err))
+			}
+			for _, id := range ids {
+				slots <- struct{}{}
+				wg.Add(1)
+				go func(id int64) {
+					defer wg.Done()
+					defer func() { <-slots }()
+					runExportJob(ctx, exports, id, logger)
+				}(id)
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func runExportJob(ctx context.Context, exports *service.ExportJobService, id int64, logger *zap.Logger) {
+	start := time.Now()
+	err := exports.RunJob(ctx, id)
+	switch {
+	case ctx.Err() != nil:
+		logger.Info("Export job interrupted, requeued", zap.Int64("job_id", id))
+	case errors.Is(err, service.ErrExportJobSuperseded):
+		logger.Warn("Export job taken over by another attempt", zap.Int64("job_id", id))
+	case err != nil:
+		logger.Error("Export job failed", zap.Int64("job_id", id), zap.Error(err))
+	default:
+		logger.Info("Export job finished", zap.Int64("job_id", id), zap.Duration("duration", time.Since(start)))
+	}
+}
+
+// RunExportPurge deletes export jobs that finished longer than the export
+// retention ago, and their files, once at start-up and then every interval.
+// It blocks until ctx is cancelled.
+func RunExportPurge(ctx context.Context, exports *service.ExportJobService, interval time.Duration, logger *zap.Logger) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		runCtx, cancel := context.WithTimeout(ctx, time.Minute)
+		purged, err := exports.PurgeExpiredJobs(runCtx)
+		cancel()
+		if err != nil {
+			logger.Error("Failed to purge expired export jobs", zap.Error(err))
+		} else if purged > 0 {
+			logger.Info("Purged expired export jobs", zap.Int64("count", purged))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,33 @@
+package jobs
+
+import (
+	"context"
+	"time"
+
+	"github.com/Pallavi566/Go-Backend/internal/repository"
+	"go.uber.org/zap"
+)
+
+// RunIdempotencyPurge deletes expired idempotency keys, once at start-up and
+// then every interval. It blocks until ctx is cancelled.
+func RunIdempotencyPurge(ctx context.Context, repo *repository.IdempotencyRepository, interval time.Duration, logger *zap.Logger) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		runCtx, cancel := context.WithTimeout(ctx, time.Minute)
+		purged, err := repo.PurgeExpired(runCtx, time.Now())
+		cancel()
+		if err != nil {
+			logger.Error("Failed to purge expired idempotency keys", zap.Error(err))
+		} else if purged > 0 {
+			logger.Info("Purged expired idempotency keys", zap.Int64("count", purged))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,34 @@
+package jobs
+
+import (
+	"context"
+	"time"
+
+	"github.com/Pallavi566/Go-Backend/internal/repository"
+	"go.uber.org/zap"
+)
+
+// RunOutboxPurge deletes outbox events, and their webhook deliveries, that
+// were dispatched longer than retention ago, once at start-up and then
+// every interval. It blocks until ctx is cancelled.
+func RunOutboxPurge(ctx context.Context, repo *repository.WebhookRepository, retention, interval time.Duration, logger *zap.Logger) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		runCtx, cancel := context.WithTimeout(ctx, time.Minute)
+		purged, err := repo.PurgeDispatched(runCtx, time.Now().Add(-retention))
+		cancel()
+		if err != nil {
+			logger.Error("Failed to purge dispatched outbox events", zap.Error(err))
+		} else if purged > 0 {
+			logger.Info("Purged dispatched outbox events", zap.Int64("count", purged), zap.Duration("retention", retention))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,34 @@
+package jobs
+
+import (
+	"context"
+	"time"
+
+	"github.com/Pallavi566/Go-Backend/internal/service"
+	"go.uber.org/zap"
+)
+
+// RunPurge hard-deletes users that have been soft-deleted for longer than
+// retention, once at start-up and then every interval. It blocks until ctx
+// is cancelled.
+func RunPurge(ctx context.Context, userService *service.UserService, retention, interval time.Duration, logger *zap.Logger) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		runCtx, cancel := context.WithTimeout(ctx, time.Minute)
+		purged, err := userService.PurgeDeletedUsers(runCtx, retention)
+		cancel()
+		if err != nil {
+			logger.Error("Failed to purge deleted users", zap.Error(err))
+		} else if purged > 0 {
+			logger.Info("Purged deleted users", zap.Int64("count", purged), zap.Duration("retention", retention))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,96 @@
+// Package metrics defines the Prometheus metrics of the service: HTTP
+// requests, database queries and connections, and changes to users. They
+// are served, in the Prometheus text format, on the admin port.
+package metrics
+
+import (
+	"database/sql"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Registry holds the metrics of the service, along with the Go runtime and
+// process metrics.
+var Registry = prometheus.NewRegistry()
+
+var factory = promauto.With(Registry)
+
+var (
+	httpRequests = factory.NewCounterVec(prometheus.CounterOpts{
+		Name: "http_requests_total",
+		Help: "HTTP requests answered, by method, route template and status.",
+	}, []string{"method", "route", "status"})
+
+	httpRequestDuration = factory.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "http_request_duration_seconds",
+		Help:    "Time taken to answer HTTP requests, by method, route template and status.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"method", "route", "status"})
+
+	httpRequestsInFlight = factory.NewGauge(prometheus.GaugeOpts{
+		Name: "http_requests_in_flight",
+		Help: "HTTP requests being answered.",
+	})
+
+	dbQueryDuration = factory.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "db_query_duration_seconds",
+		Help:    "Time taken by database queries, by query name and whether they failed.",
+		Buckets: []float64{.001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5},
+	}, []string{"query", "error"})
+
+	userChanges = factory.NewCounterVec(prometheus.CounterOpts{
+		Name: "user_changes_total",
+		Help: "Committed changes to users, by action: created, updated, deleted or restored.",
+	}, []string{"action"})
+)
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+}
+
+// Handler serves the metrics in the Prometheus text format.
+func Handler() http.Handler {
+	return promhttp.HandlerFor(Registry, promhttp.HandlerOpts{Registry: Registry})
+}
+
+// RegisterDB adds the connection pool statistics of db, as reported by
+// db.Stats, labelled with name.
+func RegisterDB(db *sql.DB, name string) error {
+	return Registry.Register(collectors.NewDBStatsCollector(db, name))
+}
+
+// RequestStarted counts a request as in flight until the returned function
+// is called.
+func RequestStarted() func() {
+	httpRequestsInFlight.Inc()
+	return httpRequestsInFlight.Dec
+}
+
+// ObserveRequest records an answered request. route is the template the
+// request matched, e.g. /api/users/:id, so that the number of series stays
+// bounded.
+func ObserveRequest(method, route string, status int, d time.Duration) {
+	code := strconv.Itoa(status)
+	httpRequests.WithLabelValues(method, route, code).Inc()
+	httpRequestDuration.WithLabelValues(method, route, code).Observe(d.Seconds())
+}
+
+// ObserveQuery records a database query.
+func ObserveQuery(query string, d time.Duration, err error) {
+	dbQueryDuration.WithLabelValues(query, strconv.FormatBool(err != nil)).Observe(d.Seconds())
+}
+
+// UserChanged counts a committed change to a user. action is one of the
+// history actions.
+func UserChanged(action string) {
+	userChanges.WithLabelValues(action).Inc()
+}
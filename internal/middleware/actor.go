@@ -0,0 +1,29 @@
+package middleware
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/Pallavi566/Go-Backend/internal/apperr"
+	"github.com/Pallavi566/Go-Backend/internal/audit"
+)
+
+// ActorHeader identifies who is making the request. It is expected to be set
+// by the gateway after authenticating the caller.
+const ActorHeader = "X-Actor"
+
+// ErrInvalidActor is returned for an X-Actor header that is too long or
+// holds control characters.
+var ErrInvalidActor = apperr.New(apperr.ErrValidation, "invalid_actor", "Invalid X-Actor header")
+
+// ActorMiddleware records the request's actor so changes can be attributed
+// to it in the user history.
+func ActorMiddleware() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if actor := c.Get(ActorHeader); actor != "" {
+			if !audit.ValidActor(actor) {
+				return ErrInvalidActor
+			}
+			c.SetUserContext(audit.WithActor(c.UserContext(), actor))
+		}
+		return c.Next()
+	}
+}
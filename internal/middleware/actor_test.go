@@ -0,0 +1,55 @@
+package middleware
+
+import (
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/Pallavi566/Go-Backend/internal/audit"
+	"go.uber.org/zap"
+)
+
+func TestActorMiddleware(t *testing.T) {
+	app := fiber.New(fiber.Config{ErrorHandler: ErrorHandler(zap.NewNop())})
+	app.Use(ActorMiddleware())
+	app.Get("/", func(c *fiber.Ctx) error {
+		return c.SendString(audit.Actor(c.UserContext()))
+	})
+
+	tests := []struct {
+		name   string
+		header string
+		status int
+		want   string
+	}{
+		{"Absent", "", fiber.StatusOK, audit.AnonymousActor},
+		{"Valid", "alice@example.com", fiber.StatusOK, "alice@example.com"},
+		{"Longest", strings.Repeat("é", audit.MaxActorLength), fiber.StatusOK, strings.Repeat("é", audit.MaxActorLength)},
+		{"Too long", strings.Repeat("a", audit.MaxActorLength+1), fiber.StatusBadRequest, ""},
+		{"Control character", "alice\x7f", fiber.StatusBadRequest, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/", nil)
+			if tt.header != "" {
+				req.Header.Set(ActorHeader, tt.header)
+			}
+			resp, err := app.Test(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if resp.StatusCode != tt.status {
+				t.Fatalf("status = %d, want %d", resp.StatusCode, tt.status)
+			}
+			if tt.status != fiber.StatusOK {
+				return
+			}
+			if body, _ := io.ReadAll(resp.Body); string(body) != tt.want {
+				t.Errorf("actor = %q, want %q", body, tt.want)
+			}
+		})
+	}
+}
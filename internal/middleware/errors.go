@@ -0,0 +1,79 @@
+package middleware
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/utils"
+	"github.com/Pallavi566/Go-Backend/internal/apperr"
+	"github.com/Pallavi566/Go-Backend/internal/audit"
+	"github.com/Pallavi566/Go-Backend/internal/models"
+	"github.com/Pallavi566/Go-Backend/internal/tracing"
+	"go.uber.org/zap"
+)
+
+// kindStatus is the HTTP status each kind of error is answered with.
+var kindStatus = map[*apperr.Error]int{
+	apperr.ErrValidation:    fiber.StatusBadRequest,
+	apperr.ErrNotFound:      fiber.StatusNotFound,
+	apperr.ErrConflict:      fiber.StatusConflict,
+	apperr.ErrPrecondition:  fiber.StatusPreconditionFailed,
+	apperr.ErrUnprocessable: fiber.StatusUnprocessableEntity,
+	apperr.ErrUnavailable:   fiber.StatusServiceUnavailable,
+}
+
+// ErrorHandler answers the errors returned by handlers and middleware with
+// an application/problem+json document. apperr errors get the status of
+// their kind and their code; fiber errors their status, with a code derived
+// from it. Anything else is an internal error: it is logged and its details
+// are not disclosed.
+func ErrorHandler(logger *zap.Logger) fiber.ErrorHandler {
+	return func(c *fiber.Ctx, err error) error {
+		p := NewProblem(err)
+		p.Instance = c.Path()
+		p.RequestID = audit.RequestID(c.UserContext())
+
+		if p.Status >= fiber.StatusInternalServerError {
+			logger.With(tracing.LogFields(c.UserContext())...).Error("Request failed",
+				zap.String("method", c.Method()),
+				zap.String("path", c.Path()),
+				zap.Int("status", p.Status),
+				zap.String("request_id", p.RequestID),
+				zap.Error(err),
+			)
+		}
+		return c.Status(p.Status).JSON(p, models.ProblemContentType)
+	}
+}
+
+// NewProblem describes err as a problem document. Only the detail of the
+// apperr error found in err is shown, not the errors wrapping it.
+func NewProblem(err error) models.Problem {
+	var p models.Problem
+	var appErr *apperr.Error
+	var fiberErr *fiber.Error
+	switch {
+	case errors.As(err, &appErr):
+		p.Status = kindStatus[appErr.KindOf()]
+		if p.Status == 0 {
+			p.Status = fiber.StatusInternalServerError
+		}
+		p.Code = appErr.Code
+		// Errors wrapping it carry context for the logs, such as a driver
+		// message, that is not for clients.
+		p.Detail = appErr.Detail
+		p.Errors = appErr.Fields
+	case errors.As(err, &fiberErr):
+		p.Status = fiberErr.Code
+		p.Code = strings.ReplaceAll(strings.ToLower(utils.StatusMessage(fiberErr.Code)), " ", "_")
+		p.Detail = fiberErr.Message
+	default:
+		p.Status = fiber.StatusInternalServerError
+		p.Code = "internal_error"
+		p.Detail = "Internal server error"
+	}
+	p.Type = "about:blank"
+	p.Title = utils.StatusMessage(p.Status)
+	return p
+}
@@ -0,0 +1,97 @@
+package middleware
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/Pallavi566/Go-Backend/internal/apperr"
+	"github.com/Pallavi566/Go-Backend/internal/models"
+	"go.uber.org/zap"
+)
+
+func TestNewProblem(t *testing.T) {
+	errMissing := apperr.New(apperr.ErrNotFound, "user_not_found", "user not found")
+	errDown := apperr.New(apperr.ErrUnavailable, "database_unavailable", "database unavailable")
+	errDuplicate := apperr.New(apperr.ErrConflict, "duplicate_email", "email already in use")
+	errInvalid := apperr.New(apperr.ErrValidation, "invalid_filter", "invalid filter")
+	name := apperr.FieldError{Field: "name", Code: "required", Message: "name failed the required check"}
+
+	tests := []struct {
+		name string
+		err  error
+		want models.Problem
+	}{
+		{
+			name: "Wrapped not found",
+			err:  fmt.Errorf("%w: id 5", errMissing),
+			want: models.Problem{Type: "about:blank", Title: "Not Found", Status: 404, Detail: "user not found", Code: "user_not_found"},
+		},
+		{
+			name: "Conflict hides the driver message",
+			err:  fmt.Errorf("create user: %w", fmt.Errorf("%w: %v", errDuplicate, errors.New("Error 1062 (23000): Duplicate entry 'a@example.com' for key 'uq_users_email'"))),
+			want: models.Problem{Type: "about:blank", Title: "Conflict", Status: 409, Detail: "email already in use", Code: "duplicate_email"},
+		},
+		{
+			name: "Explained error",
+			err:  errInvalid.Wrapf("dob_from must be YYYY-MM-DD"),
+			want: models.Problem{Type: "about:blank", Title: "Bad Request", Status: 400, Detail: "invalid filter: dob_from must be YYYY-MM-DD", Code: "invalid_filter"},
+		},
+		{
+			name: "Validation with fields",
+			err:  apperr.ErrValidation.WithDetail(name.Message).WithFields(name),
+			want: models.Problem{Type: "about:blank", Title: "Bad Request", Status: 400, Detail: name.Message, Code: "validation_failed", Errors: []apperr.FieldError{name}},
+		},
+		{
+			name: "Unavailable hides its cause",
+			err:  fmt.Errorf("%w: %w", errDown, errors.New("dial tcp 10.0.0.1:3306: connection refused")),
+			want: models.Problem{Type: "about:blank", Title: "Service Unavailable", Status: 503, Detail: "database unavailable", Code: "database_unavailable"},
+		},
+		{
+			name: "Fiber error",
+			err:  fiber.NewError(fiber.StatusUnsupportedMediaType, "Use JSON"),
+			want: models.Problem{Type: "about:blank", Title: "Unsupported Media Type", Status: 415, Detail: "Use JSON", Code: "unsupported_media_type"},
+		},
+		{
+			name: "Unknown error",
+			err:  errors.New("error fetching user: connection reset"),
+			want: models.Problem{Type: "about:blank", Title: "Internal Server Error", Status: 500, Detail: "Internal server error", Code: "internal_error"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NewProblem(tt.err); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("NewProblem() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestErrorHandler(t *testing.T) {
+	app := fiber.New(fiber.Config{ErrorHandler: ErrorHandler(zap.NewNop())})
+	app.Get("/users/:id", func(c *fiber.Ctx) error {
+		return apperr.New(apperr.ErrNotFound, "user_not_found", "user not found")
+	})
+
+	resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/users/5", nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+
+	if resp.StatusCode != fiber.StatusNotFound {
+		t.Errorf("status = %d, want %d", resp.StatusCode, fiber.StatusNotFound)
+	}
+	if got := resp.Header.Get(fiber.HeaderContentType); got != models.ProblemContentType {
+		t.Errorf("Content-Type = %q, want %q", got, models.ProblemContentType)
+	}
+	if !strings.Contains(string(body), `"instance":"/users/5"`) || !strings.Contains(string(body), `"code":"user_not_found"`) {
+		t.Errorf("body = %s, want the instance and code", body)
+	}
+}
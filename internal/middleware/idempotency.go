@@ -0,0 +1,191 @@
+package middleware
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/Pallavi566/Go-Backend/internal/apperr"
+	"github.com/Pallavi566/Go-Backend/internal/audit"
+	"github.com/Pallavi566/Go-Backend/internal/models"
+	"go.uber.org/zap"
+)
+
+const (
+	// IdempotencyKeyHeader carries a client-chosen key, such as a UUID, that
+	// makes retries of a request safe.
+	IdempotencyKeyHeader = "Idempotency-Key"
+	// IdempotentReplayedHeader is set on responses replayed for a retry.
+	IdempotentReplayedHeader = "Idempotent-Replayed"
+
+	maxIdempotencyKeyLength = 255
+)
+
+// replayedHeaders are the response headers stored and replayed with the
+// body.
+var replayedHeaders = []string{fiber.HeaderLocation, fiber.HeaderETag}
+
+var (
+	// ErrIdempotencyKeyReused is returned when a key is sent again with a
+	// different request.
+	ErrIdempotencyKeyReused = apperr.New(apperr.ErrUnprocessable, "idempotency_key_reused", "Idempotency-Key was already used with a different request")
+	// ErrIdempotencyKeyInUse is returned when a key is sent again while the
+	// first request with it is still running.
+	ErrIdempotencyKeyInUse = apperr.New(apperr.ErrConflict, "idempotency_key_in_use", "A request with this Idempotency-Key is still being processed")
+)
+
+// IdempotencyStore persists the responses of requests sent with an
+// Idempotency-Key.
+type IdempotencyStore interface {
+	// Reserve claims key within scope for a request with the given hash
+	// until expiresAt. It returns nil once the key is claimed, or the record
+	// of the request that holds it already. A key whose reservation expired
+	// is claimed anew.
+	Reserve(ctx context.Context, scope, key, hash string, expiresAt time.Time) (*models.IdempotencyRecord, error)
+	// Complete stores the response to the request holding key and keeps
+	// it until rec.ExpiresAt.
+	Complete(ctx context.Context, scope, key string, rec models.IdempotencyRecord) error
+	// Release frees key so that the request can be retried.
+	Release(ctx context.Context, scope, key string) error
+}
+
+// Idempotency makes the routes it is applied to safe to retry. The first
+// response to a request carrying an Idempotency-Key is stored and replayed
+// for retries with the same key within ttl, without running the handler
+// again. Reusing a key for a different payload is rejected with 422, and a
+// retry that arrives while the first request is still running gets 409.
+// The key is held for the first request for lease only, which should outlast
+// the request timeout, so that a request that never completes, e.g. because
+// the server died, does not lock the key until ttl. Server errors are not
+// stored, so the request can be retried once they are resolved. Keys are
+// scoped to the actor, method and path.
+func Idempotency(store IdempotencyStore, ttl, lease time.Duration, logger *zap.Logger) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		key := c.Get(IdempotencyKeyHeader)
+		if key == "" {
+			return c.Next()
+		}
+		if len(key) > maxIdempotencyKeyLength {
+			return apperr.New(apperr.ErrValidation, "invalid_idempotency_key", "Idempotency-Key must be at most "+strconv.Itoa(maxIdempotencyKeyLength)+" characters")
+		}
+
+		ctx := c.UserContext()
+		scope := audit.Actor(ctx) + " " + c.Method() + " " + strings.TrimSuffix(c.Path(), "/")
+		hash, err := requestHash(c)
+		if err != nil {
+			return apperr.New(apperr.ErrValidation, "invalid_body", "Invalid request body")
+		}
+
+		rec, err := store.Reserve(ctx, scope, key, hash, time.Now().Add(lease))
+		if err != nil {
+			return fmt.Errorf("reserve idempotency key: %w", err)
+		}
+		if rec != nil {
+			switch {
+			case rec.RequestHash != hash:
+				return ErrIdempotencyKeyReused
+			case rec.StatusCode == 0:
+				c.Set(fiber.HeaderRetryAfter, "1")
+				return ErrIdempotencyKeyInUse
+			}
+			c.Set(IdempotentReplayedHeader, "true")
+			if rec.ContentType != "" {
+				c.Set(fiber.HeaderContentType, rec.ContentType)
+			}
+			for name, value := range rec.Headers {
+				c.Set(name, value)
+			}
+			return c.Status(rec.StatusCode).Send(rec.Body)
+		}
+
+		// Errors are rendered here, so that error responses are stored like
+		// any other.
+		if err = c.Next(); err != nil {
+			err = c.App().ErrorHandler(c, err)
+		}
+		status := c.Response().StatusCode()
+		if err != nil || status >= fiber.StatusInternalServerError {
+			if rerr := store.Release(ctx, scope, key); rerr != nil {
+				logger.Error("Failed to release idempotency key", zap.Error(rerr))
+			}
+			return err
+		}
+
+		headers := make(map[string]string)
+		for _, name := range replayedHeaders {
+			// Copied, as the response buffers are reused.
+			if value := c.Response().Header.Peek(name); len(value) > 0 {
+				headers[name] = string(value)
+			}
+		}
+		err = store.Complete(ctx, scope, key, models.IdempotencyRecord{
+			RequestHash: hash,
+			StatusCode:  status,
+			ContentType: string(c.Response().Header.ContentType()),
+			Headers:     headers,
+			Body:        append([]byte(nil), c.Response().Body()...),
+			ExpiresAt:   time.Now().Add(ttl),
+		})
+		if err != nil {
+			// The request itself went through. Retries get 409 until the
+			// lease runs out, and run it again after that.
+			logger.Error("Failed to store idempotent response", zap.Error(err))
+		}
+		return nil
+	}
+}
+
+// requestHash identifies the payload of a request: its query string and
+// body. Multipart forms are hashed by their contents, as clients pick a new
+// random boundary for every attempt.
+func requestHash(c *fiber.Ctx) (string, error) {
+	h := sha256.New()
+	h.Write(c.Request().URI().QueryString())
+	h.Write([]byte{0})
+
+	if !strings.HasPrefix(c.Get(fiber.HeaderContentType), fiber.MIMEMultipartForm) {
+		h.Write(c.Body())
+		return hex.EncodeToString(h.Sum(nil)), nil
+	}
+
+	form, err := c.MultipartForm()
+	if err != nil {
+		return "", err
+	}
+	for _, name := range sortedKeys(form.Value) {
+		for _, v := range form.Value[name] {
+			io.WriteString(h, name+"\x00"+v+"\x00")
+		}
+	}
+	for _, name := range sortedKeys(form.File) {
+		for _, fh := range form.File[name] {
+			io.WriteString(h, name+"\x00"+fh.Filename+"\x00")
+			f, err := fh.Open()
+			if err != nil {
+				return "", err
+			}
+			_, err = io.Copy(h, f)
+			f.Close()
+			if err != nil {
+				return "", err
+			}
+		}
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,209 @@
+package middleware
+
+import (
+	"context"
+	"io"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/Pallavi566/Go-Backend/internal/models"
+	"go.uber.org/zap"
+)
+
+type memoryIdempotencyStore struct {
+	mu      sync.Mutex
+	records map[string]models.IdempotencyRecord
+}
+
+func (s *memoryIdempotencyStore) Reserve(_ context.Context, scope, key, hash string, expiresAt time.Time) (*models.IdempotencyRecord, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if rec, ok := s.records[scope+"|"+key]; ok && rec.ExpiresAt.After(time.Now()) {
+		return &rec, nil
+	}
+	s.records[scope+"|"+key] = models.IdempotencyRecord{RequestHash: hash, ExpiresAt: expiresAt}
+	return nil, nil
+}
+
+func (s *memoryIdempotencyStore) Complete(_ context.Context, scope, key string, rec models.IdempotencyRecord) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.records[scope+"|"+key] = rec
+	return nil
+}
+
+func (s *memoryIdempotencyStore) Release(_ context.Context, scope, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.records, scope+"|"+key)
+	return nil
+}
+
+func TestIdempotency(t *testing.T) {
+	store := &memoryIdempotencyStore{records: map[string]models.IdempotencyRecord{}}
+	calls := 0
+	app := fiber.New(fiber.Config{ErrorHandler: ErrorHandler(zap.NewNop())})
+	app.Post("/users", Idempotency(store, time.Hour, time.Minute, zap.NewNop()), func(c *fiber.Ctx) error {
+		calls++
+		if string(c.Body()) == "fail" {
+			return c.Status(fiber.StatusInternalServerError).SendString("boom")
+		}
+		c.Location("/users/" + strconv.Itoa(calls))
+		c.Set(fiber.HeaderETag, `"1"`)
+		return c.Status(fiber.StatusCreated).JSON(fiber.Map{"call": calls})
+	})
+
+	steps := []struct {
+		name       string
+		key        string
+		body       string
+		wantStatus int
+		wantBody   string
+		wantCalls  int
+		replayed   bool
+	}{
+		{"first request", "k1", `{"name":"Alice"}`, fiber.StatusCreated, `{"call":1}`, 1, false},
+		{"retry is replayed", "k1", `{"name":"Alice"}`, fiber.StatusCreated, `{"call":1}`, 1, true},
+		{"different payload", "k1", `{"name":"Bob"}`, fiber.StatusUnprocessableEntity, "", 1, false},
+		{"new key", "k2", `{"name":"Alice"}`, fiber.StatusCreated, `{"call":2}`, 2, false},
+		{"no key", "", `{"name":"Alice"}`, fiber.StatusCreated, `{"call":3}`, 3, false},
+		{"server error", "k3", "fail", fiber.StatusInternalServerError, "boom", 4, false},
+		{"server error is not stored", "k3", "fail", fiber.StatusInternalServerError, "boom", 5, false},
+		{"key too long", strings.Repeat("k", 256), "{}", fiber.StatusBadRequest, "", 5, false},
+	}
+
+	for _, step := range steps {
+		req := httptest.NewRequest(fiber.MethodPost, "/users", strings.NewReader(step.body))
+		req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+		if step.key != "" {
+			req.Header.Set(IdempotencyKeyHeader, step.key)
+		}
+		resp, err := app.Test(req)
+		if err != nil {
+			t.Fatalf("%s: %v", step.name, err)
+		}
+		body, _ := io.ReadAll(resp.Body)
+
+		if resp.StatusCode != step.wantStatus {
+			t.Errorf("%s: status = %d, want %d", step.name, resp.StatusCode, step.wantStatus)
+		}
+		if step.wantBody != "" && string(body) != step.wantBody {
+			t.Errorf("%s: body = %s, want %s", step.name, body, step.wantBody)
+		}
+		if calls != step.wantCalls {
+			t.Errorf("%s: handler called %d times, want %d", step.name, calls, step.wantCalls)
+		}
+		if got := resp.Header.Get(IdempotentReplayedHeader) == "true"; got != step.replayed {
+			t.Errorf("%s: replayed = %v, want %v", step.name, got, step.replayed)
+		}
+		if step.wantStatus == fiber.StatusCreated {
+			if got, want := resp.Header.Get(fiber.HeaderLocation), "/users/"+strings.Trim(step.wantBody, `{}"call:`); got != want {
+				t.Errorf("%s: Location = %q, want %q", step.name, got, want)
+			}
+			if got := resp.Header.Get(fiber.HeaderETag); got != `"1"` {
+				t.Errorf("%s: ETag = %q, want %q", step.name, got, `"1"`)
+			}
+		}
+	}
+
+	// A completed response is kept for ttl, not just for the lease.
+	if rec := store.records["anonymous POST /users|k1"]; time.Until(rec.ExpiresAt) < 59*time.Minute {
+		t.Errorf("completed key expires in %v, want about 1h", time.Until(rec.ExpiresAt))
+	}
+}
+
+func TestIdempotencyInFlight(t *testing.T) {
+	store := &memoryIdempotencyStore{records: map[string]models.IdempotencyRecord{}}
+	calls := 0
+	app := fiber.New(fiber.Config{ErrorHandler: ErrorHandler(zap.NewNop())})
+	app.Post("/users", Idempotency(store, time.Hour, time.Minute, zap.NewNop()), func(c *fiber.Ctx) error {
+		calls++
+		return c.SendStatus(fiber.StatusCreated)
+	})
+
+	var hash string
+	app.Post("/hash", func(c *fiber.Ctx) error {
+		var err error
+		hash, err = requestHash(c)
+		return err
+	})
+	if _, err := app.Test(httptest.NewRequest(fiber.MethodPost, "/hash", strings.NewReader("{}"))); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name       string
+		leaseLeft  time.Duration
+		wantStatus int
+		wantCalls  int
+	}{
+		// A first request with the same payload has reserved the key but
+		// not finished yet.
+		{"running", time.Minute, fiber.StatusConflict, 0},
+		// The first request never completed and its lease ran out.
+		{"lease expired", -time.Second, fiber.StatusCreated, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls = 0
+			store.records["anonymous POST /users|k"] = models.IdempotencyRecord{RequestHash: hash, ExpiresAt: time.Now().Add(tt.leaseLeft)}
+
+			req := httptest.NewRequest(fiber.MethodPost, "/users", strings.NewReader("{}"))
+			req.Header.Set(IdempotencyKeyHeader, "k")
+			resp, err := app.Test(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if resp.StatusCode != tt.wantStatus {
+				t.Errorf("status = %d, want %d", resp.StatusCode, tt.wantStatus)
+			}
+			if calls != tt.wantCalls {
+				t.Errorf("handler called %d times, want %d", calls, tt.wantCalls)
+			}
+		})
+	}
+}
+
+func TestRequestHashMultipart(t *testing.T) {
+	form := func(boundary, content string) string {
+		return "--" + boundary + "\r\n" +
+			"Content-Disposition: form-data; name=\"file\"; filename=\"users.csv\"\r\n" +
+			"Content-Type: text/csv\r\n\r\n" +
+			content + "\r\n" +
+			"--" + boundary + "--\r\n"
+	}
+
+	var hashes []string
+	app := fiber.New()
+	app.Post("/", func(c *fiber.Ctx) error {
+		hash, err := requestHash(c)
+		hashes = append(hashes, hash)
+		return err
+	})
+	for _, tt := range []struct{ boundary, content string }{
+		{"aaa", "name,dob\nAlice,1990-05-10"},
+		{"bbb", "name,dob\nAlice,1990-05-10"},
+		{"aaa", "name,dob\nBob,1990-05-10"},
+	} {
+		req := httptest.NewRequest(fiber.MethodPost, "/", strings.NewReader(form(tt.boundary, tt.content)))
+		req.Header.Set(fiber.HeaderContentType, "multipart/form-data; boundary="+tt.boundary)
+		if _, err := app.Test(req); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if len(hashes) != 3 {
+		t.Fatalf("got %d hashes, want 3", len(hashes))
+	}
+	if hashes[0] != hashes[1] {
+		t.Error("same form with another boundary hashed differently")
+	}
+	if hashes[0] == hashes[2] {
+		t.Error("forms with different files hashed the same")
+	}
+}
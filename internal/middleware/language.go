@@ -0,0 +1,20 @@
+package middleware
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/Pallavi566/Go-Backend/internal/i18n"
+)
+
+// LanguageMiddleware picks the language of messages, such as those of
+// validation errors, from the request's Accept-Language header.
+func LanguageMiddleware() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		lang := c.AcceptsLanguages(i18n.Languages...)
+		if lang == "" {
+			lang = i18n.DefaultLanguage
+		}
+		c.Vary(fiber.HeaderAcceptLanguage)
+		c.SetUserContext(i18n.WithLanguage(c.UserContext(), lang))
+		return c.Next()
+	}
+}
@@ -0,0 +1,49 @@
+package middleware
+
+import (
+	"io"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/Pallavi566/Go-Backend/internal/i18n"
+)
+
+func TestLanguageMiddleware(t *testing.T) {
+	app := fiber.New()
+	app.Use(LanguageMiddleware())
+	app.Get("/", func(c *fiber.Ctx) error {
+		return c.SendString(i18n.Language(c.UserContext()))
+	})
+
+	tests := []struct {
+		name   string
+		header string
+		want   string
+	}{
+		{"No header", "", "en"},
+		{"Supported", "es", "es"},
+		{"Region falls back to language", "fr-CA", "fr"},
+		{"Region supported", "pt-BR", "pt-BR"},
+		{"Quality", "de, fr;q=0.5, es;q=0.8", "es"},
+		{"Unsupported", "de", "en"},
+		{"Any", "*", "en"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/", nil)
+			if tt.header != "" {
+				req.Header.Set("Accept-Language", tt.header)
+			}
+			resp, err := app.Test(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			body, _ := io.ReadAll(resp.Body)
+			if string(body) != tt.want {
+				t.Errorf("language = %q, want %q", body, tt.want)
+			}
+		})
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"time"
 
 	"github.com/gofiber/fiber/v2"
+	"github.com/Pallavi566/Go-Backend/internal/tracing"
 	"go.uber.org/zap"
 )
 
@@ -11,8 +12,12 @@ func LoggerMiddleware(logger *zap.Logger) fiber.Handler {
 	return func(c *fiber.Ctx) error {
 		start := time.Now()
 
-		// Process request
+		// Process request, rendering errors so that the status logged is the
+		// one sent
 		err := c.Next()
+		if err != nil {
+			err = c.App().ErrorHandler(c, err)
+		}
 
 		// Calculate duration
 		duration := time.Since(start)
@@ -23,8 +28,8 @@ func LoggerMiddleware(logger *zap.Logger) fiber.Handler {
 			requestID = "unknown"
 		}
 
-		// Log request
-		logger.Info("HTTP Request",
+		// Log request, with the trace it belongs to
+		logger.With(tracing.LogFields(c.UserContext())...).Info("HTTP Request",
 			zap.String("method", c.Method()),
 			zap.String("path", c.Path()),
 			zap.Int("status", c.Response().StatusCode()),
@@ -0,0 +1,47 @@
+package middleware
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/Pallavi566/Go-Backend/internal/metrics"
+)
+
+// unmatchedRoute labels requests that matched no route, so that scans of
+// random paths do not each add a series.
+const unmatchedRoute = "unmatched"
+
+// MetricsMiddleware records the count and latency of requests by the route
+// template they matched and their status, and the number in flight. Errors
+// are rendered here so that the status recorded is the one sent.
+func MetricsMiddleware() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		done := metrics.RequestStarted()
+		defer done()
+		start := time.Now()
+
+		err := c.Next()
+		route := c.Route().Path
+		if unmatched(err) {
+			route = unmatchedRoute
+		}
+		if err != nil {
+			if renderErr := c.App().ErrorHandler(c, err); renderErr != nil {
+				return renderErr
+			}
+		}
+
+		metrics.ObserveRequest(c.Method(), route, c.Response().StatusCode(), time.Since(start))
+		return nil
+	}
+}
+
+// unmatched reports whether err is the one Fiber answers requests that match
+// no route with. Their route is then that of the last middleware they went
+// through.
+func unmatched(err error) bool {
+	var fe *fiber.Error
+	return errors.As(err, &fe) && fe.Code == fiber.StatusNotFound && strings.HasPrefix(fe.Message, "Cannot ")
+}
@@ -0,0 +1,67 @@
+package middleware
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/Pallavi566/Go-Backend/internal/apperr"
+	"github.com/Pallavi566/Go-Backend/internal/metrics"
+	"go.uber.org/zap"
+)
+
+func TestMetricsMiddleware(t *testing.T) {
+	app := fiber.New(fiber.Config{ErrorHandler: ErrorHandler(zap.NewNop())})
+	app.Use(MetricsMiddleware())
+	app.Get("/metrics-test/users/:id", func(c *fiber.Ctx) error {
+		if c.Params("id") == "0" {
+			return apperr.New(apperr.ErrNotFound, "user_not_found", "user not found")
+		}
+		return c.SendString("ok")
+	})
+
+	for _, path := range []string{"/metrics-test/users/1", "/metrics-test/users/2", "/metrics-test/users/0", "/metrics-test/nowhere"} {
+		if _, err := app.Test(httptest.NewRequest("GET", path, nil)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		route  string
+		status string
+		want   float64
+	}{
+		{"/metrics-test/users/:id", "200", 2},
+		{"/metrics-test/users/:id", "404", 1},
+		{"unmatched", "404", 1},
+	}
+	for _, tt := range tests {
+		if got := requestCount(t, "GET", tt.route, tt.status); got != tt.want {
+			t.Errorf("requests to %s with status %s = %v, want %v", tt.route, tt.status, got, tt.want)
+		}
+	}
+}
+
+// requestCount returns the value of http_requests_total for the labels.
+func requestCount(t *testing.T, method, route, status string) float64 {
+	families, err := metrics.Registry.Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{"method": method, "route": route, "status": status}
+	for _, family := range families {
+		if family.GetName() != "http_requests_total" {
+			continue
+		}
+	metrics:
+		for _, m := range family.GetMetric() {
+			for _, label := range m.GetLabel() {
+				if want[label.GetName()] != label.GetValue() {
+					continue metrics
+				}
+			}
+			return m.GetCounter().GetValue()
+		}
+	}
+	return 0
+}
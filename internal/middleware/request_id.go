@@ -1,20 +1,26 @@
 package middleware
 
 import (
+	"regexp"
+
 	"github.com/gofiber/fiber/v2"
 	"github.com/google/uuid"
+	"github.com/Pallavi566/Go-Backend/internal/audit"
 )
 
+// validRequestID matches the request IDs accepted from clients. They are
+// recorded with changes and echoed back, so anything else is replaced.
+var validRequestID = regexp.MustCompile(`^[A-Za-z0-9._-]{1,64}$`)
+
 func RequestIDMiddleware() fiber.Handler {
 	return func(c *fiber.Ctx) error {
 		requestID := c.Get("X-Request-ID")
-		if requestID == "" {
+		if !validRequestID.MatchString(requestID) {
 			requestID = uuid.New().String()
 		}
 		c.Set("X-Request-ID", requestID)
 		c.Locals("requestID", requestID)
+		c.SetUserContext(audit.WithRequestID(c.UserContext(), requestID))
 		return c.Next()
 	}
 }
-
-
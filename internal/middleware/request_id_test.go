@@ -0,0 +1,58 @@
+package middleware
+
+import (
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/Pallavi566/Go-Backend/internal/audit"
+)
+
+func TestRequestIDMiddleware(t *testing.T) {
+	app := fiber.New()
+	app.Use(RequestIDMiddleware())
+	app.Get("/", func(c *fiber.Ctx) error {
+		return c.SendString(audit.RequestID(c.UserContext()))
+	})
+
+	tests := []struct {
+		name   string
+		header string
+		kept   bool
+	}{
+		{"Absent", "", false},
+		{"Valid", "req-1.a_B", true},
+		{"Longest", strings.Repeat("a", 64), true},
+		{"Too long", strings.Repeat("a", 65), false},
+		{"Invalid characters", "req 1; drop", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/", nil)
+			if tt.header != "" {
+				req.Header.Set("X-Request-ID", tt.header)
+			}
+			resp, err := app.Test(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			body, _ := io.ReadAll(resp.Body)
+			got := string(body)
+			if resp.Header.Get("X-Request-ID") != got {
+				t.Errorf("X-Request-ID = %q, want %q", resp.Header.Get("X-Request-ID"), got)
+			}
+			if tt.kept && got != tt.header {
+				t.Errorf("request ID = %q, want %q", got, tt.header)
+			}
+			if !tt.kept && got == tt.header {
+				t.Errorf("request ID %q was not replaced", got)
+			}
+			if !validRequestID.MatchString(got) {
+				t.Errorf("request ID %q is invalid", got)
+			}
+		})
+	}
+}
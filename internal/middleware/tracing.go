@@ -0,0 +1,66 @@
+package middleware
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/Pallavi566/Go-Backend/internal/tracing"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// TracingMiddleware starts a server span for each request, continuing the
+// trace of the caller when the request carries a W3C traceparent header.
+// The span is named after the route template the request matched.
+func TracingMiddleware() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		ctx := otel.GetTextMapPropagator().Extract(c.UserContext(), headerCarrier{c})
+		ctx, span := tracing.Tracer().Start(ctx, c.Method(),
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(
+				semconv.HTTPRequestMethodKey.String(c.Method()),
+				semconv.URLPath(c.Path()),
+			),
+		)
+		defer span.End()
+		c.SetUserContext(ctx)
+
+		err := c.Next()
+
+		route := c.Route().Path
+		if unmatched(err) {
+			route = unmatchedRoute
+		}
+		status := c.Response().StatusCode()
+		if err != nil {
+			status = NewProblem(err).Status
+		}
+		span.SetName(c.Method() + " " + route)
+		span.SetAttributes(semconv.HTTPRoute(route), semconv.HTTPResponseStatusCode(status))
+		if status >= fiber.StatusInternalServerError {
+			span.SetStatus(codes.Error, "")
+		}
+		return err
+	}
+}
+
+// headerCarrier reads and writes trace context in the request headers.
+type headerCarrier struct {
+	c *fiber.Ctx
+}
+
+func (h headerCarrier) Get(key string) string {
+	return h.c.Get(key)
+}
+
+func (h headerCarrier) Set(key, value string) {
+	h.c.Request().Header.Set(key, value)
+}
+
+func (h headerCarrier) Keys() []string {
+	var keys []string
+	h.c.Request().Header.VisitAll(func(key, _ []byte) {
+		keys = append(keys, string(key))
+	})
+	return keys
+}
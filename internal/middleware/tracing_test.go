@@ -0,0 +1,98 @@
+package middleware
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/Pallavi566/Go-Backend/internal/apperr"
+	"github.com/Pallavi566/Go-Backend/internal/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+)
+
+func TestTracingMiddleware(t *testing.T) {
+	exporter := tracing.SetupInMemory()
+
+	var traceID string
+	app := fiber.New(fiber.Config{ErrorHandler: ErrorHandler(zap.NewNop())})
+	app.Use(TracingMiddleware())
+	app.Get("/users/:id", func(c *fiber.Ctx) error {
+		traceID = trace.SpanContextFromContext(c.UserContext()).TraceID().String()
+		switch c.Params("id") {
+		case "0":
+			return apperr.New(apperr.ErrNotFound, "user_not_found", "user not found")
+		case "500":
+			return fiber.ErrInternalServerError
+		}
+		return c.SendString("ok")
+	})
+
+	const (
+		parentTraceID = "4bf92f3577b34da6a3ce929d0e0e4736"
+		parentSpanID  = "00f067aa0ba902b7"
+	)
+
+	tests := []struct {
+		name        string
+		path        string
+		traceparent string
+		wantStatus  int64
+		wantError   bool
+	}{
+		{"Continues the caller's trace", "/users/1", "00-" + parentTraceID + "-" + parentSpanID + "-01", 200, false},
+		{"Starts a trace", "/users/2", "", 200, false},
+		{"Client error", "/users/0", "", 404, false},
+		{"Server error", "/users/500", "", 500, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			exporter.Reset()
+			req := httptest.NewRequest("GET", tt.path, nil)
+			if tt.traceparent != "" {
+				req.Header.Set("traceparent", tt.traceparent)
+			}
+			if _, err := app.Test(req); err != nil {
+				t.Fatal(err)
+			}
+
+			spans := exporter.GetSpans()
+			if len(spans) != 1 {
+				t.Fatalf("got %d spans, want 1", len(spans))
+			}
+			span := spans[0]
+			if span.Name != "GET /users/:id" {
+				t.Errorf("name = %q, want %q", span.Name, "GET /users/:id")
+			}
+			if span.SpanContext.TraceID().String() != traceID {
+				t.Errorf("handler saw trace %s, span is in %s", traceID, span.SpanContext.TraceID())
+			}
+			if tt.traceparent != "" {
+				if got := span.SpanContext.TraceID().String(); got != parentTraceID {
+					t.Errorf("trace ID = %s, want %s", got, parentTraceID)
+				}
+				if got := span.Parent.SpanID().String(); got != parentSpanID {
+					t.Errorf("parent span ID = %s, want %s", got, parentSpanID)
+				}
+			} else if span.Parent.IsValid() {
+				t.Errorf("span has parent %s, want none", span.Parent.SpanID())
+			}
+
+			var status int64
+			for _, attr := range span.Attributes {
+				if attr.Key == attribute.Key("http.response.status_code") {
+					status = attr.Value.AsInt64()
+				}
+			}
+			if status != tt.wantStatus {
+				t.Errorf("status attribute = %d, want %d", status, tt.wantStatus)
+			}
+			if (span.Status.Code == codes.Error) != tt.wantError {
+				t.Errorf("span status = %v, want error %v", span.Status.Code, tt.wantError)
+			}
+		})
+	}
+}
@@ -0,0 +1,18 @@
+package models
+
+// AgeCheckRequest is the body of an age-gating check. AsOf is a YYYY-MM-DD
+// date and defaults to today in the user's time zone.
+type AgeCheckRequest struct {
+	MinAge *int   `json:"min_age" validate:"required,min=0,max=200"`
+	AsOf   string `json:"as_of,omitempty"`
+}
+
+// AgeCheck is the age of a user on a date and, for age-gating checks,
+// whether they had reached the minimum age.
+type AgeCheck struct {
+	UserID      int    `json:"user_id"`
+	AsOf        string `json:"as_of"`
+	Age         int    `json:"age"`
+	MinAge      *int   `json:"min_age,omitempty"`
+	MeetsMinAge *bool  `json:"meets_min_age,omitempty"`
+}
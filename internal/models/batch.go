@@ -0,0 +1,77 @@
+package models
+
+import "github.com/Pallavi566/Go-Backend/internal/apperr"
+
+// Batch operation kinds.
+const (
+	BatchCreate = "create"
+	BatchUpdate = "update"
+	BatchDelete = "delete"
+)
+
+// Batch modes. In atomic mode either every operation is applied or none is;
+// in best-effort mode each operation is applied on its own.
+const (
+	BatchAtomic     = "atomic"
+	BatchBestEffort = "best_effort"
+)
+
+// BatchRequest is a list of at most 1000 operations. Mode defaults to
+// atomic.
+type BatchRequest struct {
+	Mode       string           `json:"mode" validate:"omitempty,oneof=atomic best_effort"`
+	Operations []BatchOperation `json:"operations" validate:"required,min=1,max=1000"`
+}
+
+// BatchOperation is a single create, update or delete in a batch. Data is
+// required for create and update, ID for update and delete. A non-zero
+// Version makes an update or delete conditional on the user's current
+// version, like If-Match does for single requests.
+type BatchOperation struct {
+	Op      string             `json:"op" validate:"required,oneof=create update delete"`
+	ID      int                `json:"id" validate:"required_unless=Op create,omitempty,min=1"`
+	Version int                `json:"version" validate:"omitempty,min=1"`
+	Data    *CreateUserRequest `json:"data" validate:"required_unless=Op delete"`
+}
+
+// BatchResult is the outcome of one operation. Status is the HTTP status the
+// operation would have had as a single request, and Code the error code of
+// its problem document.
+type BatchResult struct {
+	Index   int    `json:"index"`
+	Op      string `json:"op"`
+	Status  int    `json:"status"`
+	ID      int    `json:"id,omitempty"`
+	Version int    `json:"version,omitempty"`
+	Code    string `json:"code,omitempty"`
+	Error   string `json:"error,omitempty"`
+	// Errors lists the invalid fields of an invalid operation.
+	Errors []apperr.FieldError `json:"errors,omitempty"`
+}
+
+type BatchResponse struct {
+	Mode      string        `json:"mode"`
+	Committed bool          `json:"committed"`
+	Succeeded int           `json:"succeeded"`
+	Failed    int           `json:"failed"`
+	Results   []BatchResult `json:"results"`
+}
+
+// ImportRowError reports why a line of an import file was not imported.
+type ImportRowError struct {
+	Line  int    `json:"line"`
+	Error string `json:"error"`
+}
+
+// ImportReport summarises an import. Rows counts the data rows read, Valid
+// those that passed validation and Imported those written; on a dry run
+// nothing is written and Imported stays zero.
+type ImportReport struct {
+	Format   string           `json:"format"`
+	DryRun   bool             `json:"dry_run"`
+	Rows     int              `json:"rows"`
+	Valid    int              `json:"valid"`
+	Imported int              `json:"imported"`
+	Failed   int              `json:"failed"`
+	Errors   []ImportRowError `json:"errors"`
+}
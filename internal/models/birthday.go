@@ -0,0 +1,22 @@
+package models
+
+// UpcomingBirthday is a user with their birthday in a requested window.
+type UpcomingBirthday struct {
+	UserResponse
+	// Birthday is the date of the birthday in the window, YYYY-MM-DD.
+	Birthday string `json:"birthday"`
+	// Turning is the age the user turns on it.
+	Turning int `json:"turning"`
+}
+
+// BirthdaysResponse is a page of the users with a birthday between From and
+// To, in order of their birthday.
+type BirthdaysResponse struct {
+	From       string             `json:"from"`
+	To         string             `json:"to"`
+	Data       []UpcomingBirthday `json:"data"`
+	Page       int                `json:"page"`
+	Limit      int                `json:"limit"`
+	Total      int64              `json:"total"`
+	TotalPages int                `json:"total_pages"`
+}
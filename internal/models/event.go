@@ -0,0 +1,57 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Event types written to the outbox when a user changes.
+const (
+	EventUserCreated  = "user.created"
+	EventUserUpdated  = "user.updated"
+	EventUserDeleted  = "user.deleted"
+	EventUserRestored = "user.restored"
+)
+
+// EventBirthdayDigest is published once a day with the users whose
+// birthday it is.
+const EventBirthdayDigest = "user.birthday_digest"
+
+// EventTypes lists every event type that can be subscribed to.
+var EventTypes = []string{EventUserCreated, EventUserUpdated, EventUserDeleted, EventUserRestored, EventBirthdayDigest}
+
+// UserEventData is the payload of a user event. Previous is the state before
+// the change and is omitted for user.created.
+type UserEventData struct {
+	User      *UserSnapshot `json:"user"`
+	Previous  *UserSnapshot `json:"previous,omitempty"`
+	Actor     string        `json:"actor"`
+	RequestID string        `json:"request_id,omitempty"`
+}
+
+// BirthdayDigestData is the payload of a user.birthday_digest event.
+type BirthdayDigestData struct {
+	// Date is the day of the digest, YYYY-MM-DD.
+	Date  string           `json:"date"`
+	Users []BirthdayPerson `json:"users"`
+}
+
+// BirthdayPerson is a user listed in a birthday digest.
+type BirthdayPerson struct {
+	ID       int    `json:"id"`
+	Name     string `json:"name"`
+	Email    string `json:"email,omitempty"`
+	Timezone string `json:"timezone,omitempty"`
+	Locale   string `json:"locale,omitempty"`
+	Turning  int    `json:"turning"`
+}
+
+// Event is an event as delivered to subscribers. Data is UserEventData for
+// the user.* change events and BirthdayDigestData for
+// user.birthday_digest.
+type Event struct {
+	ID         int64           `json:"id"`
+	Type       string          `json:"type"`
+	OccurredAt time.Time       `json:"occurred_at"`
+	Data       json.RawMessage `json:"data"`
+}
@@ -0,0 +1,43 @@
+package models
+
+import "time"
+
+// Export job statuses.
+const (
+	ExportQueued    = "queued"
+	ExportRunning   = "running"
+	ExportSucceeded = "succeeded"
+	ExportFailed    = "failed"
+)
+
+// ExportJob is an asynchronous user export. TotalRows is known once the job
+// has started; DownloadURL is set once it has succeeded. Finished jobs and
+// their files are deleted at ExpiresAt.
+type ExportJob struct {
+	ID            int64      `json:"id"`
+	Status        string     `json:"status"`
+	Format        string     `json:"format"`
+	Filters       UserFilter `json:"filters"`
+	TotalRows     *int64     `json:"total_rows,omitempty"`
+	ProcessedRows int64      `json:"processed_rows"`
+	// Attempts is the number of times the job has been started.
+	Attempts int `json:"attempts"`
+	// Progress is the percentage of rows written, from 0 to 100.
+	Progress    float64    `json:"progress"`
+	FileSize    *int64     `json:"file_size,omitempty"`
+	Error       string     `json:"error,omitempty"`
+	RequestedBy string     `json:"requested_by"`
+	CreatedAt   time.Time  `json:"created_at"`
+	StartedAt   *time.Time `json:"started_at,omitempty"`
+	FinishedAt  *time.Time `json:"finished_at,omitempty"`
+	ExpiresAt   *time.Time `json:"expires_at,omitempty"`
+	DownloadURL string     `json:"download_url,omitempty"`
+
+	// FilePath is where the finished file is stored; it is not exposed.
+	FilePath string `json:"-"`
+}
+
+type CreateExportRequest struct {
+	Format  string     `json:"format" validate:"required,oneof=csv ndjson xlsx"`
+	Filters UserFilter `json:"filters"`
+}
@@ -0,0 +1,24 @@
+package models
+
+// Health statuses.
+const (
+	HealthOK          = "ok"
+	HealthUnavailable = "unavailable"
+	// HealthDraining is reported while the server shuts down.
+	HealthDraining = "draining"
+)
+
+// HealthReport is the answer to a readiness check.
+type HealthReport struct {
+	Status string `json:"status"`
+	// Checks has the result of each dependency check, by name. It is empty
+	// while draining.
+	Checks map[string]CheckResult `json:"checks"`
+}
+
+// CheckResult is the outcome of checking one dependency.
+type CheckResult struct {
+	Status    string  `json:"status"`
+	LatencyMS float64 `json:"latency_ms"`
+	Error     string  `json:"error,omitempty"`
+}
@@ -0,0 +1,18 @@
+package models
+
+import "time"
+
+// IdempotencyRecord is the stored outcome of a request sent with an
+// Idempotency-Key header.
+type IdempotencyRecord struct {
+	// RequestHash identifies the payload the key was first used with.
+	RequestHash string
+	// StatusCode is zero while the first request is still in flight.
+	StatusCode  int
+	ContentType string
+	// Headers holds the response headers replayed with the body, such as
+	// Location.
+	Headers   map[string]string
+	Body      []byte
+	ExpiresAt time.Time
+}
@@ -0,0 +1,22 @@
+package models
+
+import "github.com/Pallavi566/Go-Backend/internal/apperr"
+
+// ProblemContentType is the media type of error responses.
+const ProblemContentType = "application/problem+json"
+
+// Problem is an error response, a problem details document (RFC 7807).
+type Problem struct {
+	Type   string `json:"type"`
+	Title  string `json:"title"`
+	Status int    `json:"status"`
+	Detail string `json:"detail,omitempty"`
+	// Instance is the path of the request that failed.
+	Instance string `json:"instance,omitempty"`
+	// Code identifies the error, e.g. user_not_found. Codes never change,
+	// so clients can match on them.
+	Code      string `json:"code"`
+	RequestID string `json:"request_id,omitempty"`
+	// Errors lists the invalid fields of a validation error.
+	Errors []apperr.FieldError `json:"errors,omitempty"`
+}
@@ -0,0 +1,53 @@
+package models
+
+import "time"
+
+// UserStats summarizes the active users. Signups also count users deleted
+// since.
+type UserStats struct {
+	Total        int64         `json:"total"`
+	Age          AgeStats      `json:"age"`
+	Signups      SignupStats   `json:"signups"`
+	BirthDecades []CohortCount `json:"birth_decades"`
+	// GeneratedAt is when the numbers were computed; they are cached for a
+	// short while.
+	GeneratedAt time.Time `json:"generated_at"`
+}
+
+// AgeStats describes the age distribution. Mean and Median are omitted when
+// there are no users.
+type AgeStats struct {
+	Mean      *float64    `json:"mean,omitempty"`
+	Median    *float64    `json:"median,omitempty"`
+	Histogram []AgeBucket `json:"histogram"`
+}
+
+// AgeBucket counts the users aged Min to Max years, inclusive. The last
+// bucket has no Max.
+type AgeBucket struct {
+	Min   int   `json:"min"`
+	Max   *int  `json:"max,omitempty"`
+	Count int64 `json:"count"`
+}
+
+// SignupStats counts the users created in each day, week (starting on
+// Monday) or month since Since. Periods without signups are included.
+type SignupStats struct {
+	Interval string        `json:"interval"`
+	Since    string        `json:"since"`
+	Periods  []PeriodCount `json:"periods"`
+}
+
+// PeriodCount is the number of users created in the period starting on
+// Start, YYYY-MM-DD.
+type PeriodCount struct {
+	Start string `json:"start"`
+	Count int64  `json:"count"`
+}
+
+// CohortCount is the number of users born in the decade starting with the
+// year Decade.
+type CohortCount struct {
+	Decade int   `json:"decade"`
+	Count  int64 `json:"count"`
+}
@@ -5,38 +5,139 @@ import (
 )
 
 type User struct {
-	ID   int       `json:"id"`
-	Name string    `json:"name"`
-	DOB  time.Time `json:"dob"`
+	ID        int        `json:"id"`
+	Name      string     `json:"name"`
+	DOB       time.Time  `json:"dob"`
+	Email     string     `json:"email,omitempty"`
+	Phone     string     `json:"phone,omitempty"`
+	Timezone  string     `json:"timezone,omitempty"`
+	Locale    string     `json:"locale,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+	Version   int        `json:"version"`
+	DeletedAt *time.Time `json:"deleted_at,omitempty"`
 }
 
 type UserResponse struct {
-	ID   int    `json:"id"`
-	Name string `json:"name"`
-	DOB  string `json:"dob"`
-	Age  *int   `json:"age,omitempty"`
+	ID        int     `json:"id"`
+	Name      string  `json:"name"`
+	DOB       string  `json:"dob"`
+	Email     string  `json:"email,omitempty"`
+	Phone     string  `json:"phone,omitempty"`
+	Timezone  string  `json:"timezone,omitempty"`
+	Locale    string  `json:"locale,omitempty"`
+	Age       *int    `json:"age,omitempty"`
+	Version   int     `json:"version"`
+	DeletedAt *string `json:"deleted_at,omitempty"`
+
+	// The fields below are only set when requested with ?include=.
+	AgeDetail         *AgeDetail `json:"age_detail,omitempty"`
+	NextBirthday      *string    `json:"next_birthday,omitempty"`
+	DaysUntilBirthday *int       `json:"days_until_birthday,omitempty"`
+}
+
+// AgeDetail breaks an age down into completed years, months and days, as in
+// "3 years, 4 months", along with the total number of days lived.
+type AgeDetail struct {
+	Years     int `json:"years"`
+	Months    int `json:"months"`
+	Days      int `json:"days"`
+	TotalDays int `json:"total_days"`
 }
 
+// CreateUserRequest is the body of a user creation. Email, phone (E.164,
+// e.g. +14155550123), timezone (IANA, e.g. Europe/Paris) and locale (BCP 47,
+// e.g. en-GB) are optional.
 type CreateUserRequest struct {
-	Name string `json:"name" validate:"required,min=1,max=255"`
-	DOB  string `json:"dob" validate:"required"`
+	Name     string `json:"name" validate:"required,min=1,max=255"`
+	DOB      string `json:"dob" validate:"required"`
+	Email    string `json:"email,omitempty" validate:"omitempty,email,max=254"`
+	Phone    string `json:"phone,omitempty" validate:"omitempty,e164"`
+	Timezone string `json:"timezone,omitempty" validate:"omitempty,timezone"`
+	Locale   string `json:"locale,omitempty" validate:"omitempty,bcp47_language_tag,max=35"`
 }
 
+// UpdateUserRequest is the body of a full update. Optional fields left out
+// keep their current value.
 type UpdateUserRequest struct {
-	Name string `json:"name" validate:"required,min=1,max=255"`
-	DOB  string `json:"dob" validate:"required"`
+	Name     string `json:"name" validate:"required,min=1,max=255"`
+	DOB      string `json:"dob" validate:"required"`
+	Email    string `json:"email,omitempty" validate:"omitempty,email,max=254"`
+	Phone    string `json:"phone,omitempty" validate:"omitempty,e164"`
+	Timezone string `json:"timezone,omitempty" validate:"omitempty,timezone"`
+	Locale   string `json:"locale,omitempty" validate:"omitempty,bcp47_language_tag,max=35"`
 }
 
+// PaginationParams selects a page of a listing. Page/limit paging is used by
+// default; when a cursor is supplied (empty for the first page) the listing
+// is paged by keyset instead and Page must be omitted.
 type PaginationParams struct {
-	Page  int `query:"page" validate:"min=1"`
-	Limit int `query:"limit" validate:"min=1,max=100"`
+	Page   int    `query:"page" validate:"omitempty,min=1"`
+	Limit  int    `query:"limit" validate:"min=1,max=100"`
+	Cursor string `query:"cursor"`
+}
+
+// UserFilter holds the optional filters and sort order accepted by the
+// users listing and exports. Dates use the YYYY-MM-DD format and sort is a
+// comma separated list of fields, each optionally prefixed with "-" for
+// descending order (e.g. "-dob,name").
+type UserFilter struct {
+	NameContains string `query:"name_contains" json:"name_contains,omitempty" validate:"max=255"`
+	DOBFrom      string `query:"dob_from" json:"dob_from,omitempty"`
+	DOBTo        string `query:"dob_to" json:"dob_to,omitempty"`
+	MinAge       *int   `query:"min_age" json:"min_age,omitempty" validate:"omitempty,min=0,max=200"`
+	MaxAge       *int   `query:"max_age" json:"max_age,omitempty" validate:"omitempty,min=0,max=200"`
+	Sort         string `query:"sort" json:"sort,omitempty"`
+
+	// IncludeDeleted is an admin view that also lists soft-deleted users.
+	IncludeDeleted bool `query:"include_deleted" json:"include_deleted,omitempty"`
 }
 
+// PaginatedResponse is returned by both paging modes. Page, Total and
+// TotalPages are only set for page/limit paging; NextCursor is only set for
+// cursor paging and is omitted on the last page.
 type PaginatedResponse struct {
 	Data       []UserResponse `json:"data"`
-	Page       int            `json:"page"`
+	Page       int            `json:"page,omitempty"`
 	Limit      int            `json:"limit"`
-	Total      int64          `json:"total"`
-	TotalPages int            `json:"total_pages"`
+	Total      *int64         `json:"total,omitempty"`
+	TotalPages *int           `json:"total_pages,omitempty"`
+	NextCursor *string        `json:"next_cursor,omitempty"`
 }
 
+// History actions recorded in UserHistoryEntry.Action.
+const (
+	HistoryCreated  = "created"
+	HistoryUpdated  = "updated"
+	HistoryDeleted  = "deleted"
+	HistoryRestored = "restored"
+	// HistoryBackfilled marks the state of a user that existed before its
+	// history was recorded.
+	HistoryBackfilled = "backfilled"
+)
+
+// UserSnapshot is the state of a user as recorded in its history.
+type UserSnapshot struct {
+	ID       int    `json:"id"`
+	Name     string `json:"name"`
+	DOB      string `json:"dob"`
+	Email    string `json:"email,omitempty"`
+	Phone    string `json:"phone,omitempty"`
+	Timezone string `json:"timezone,omitempty"`
+	Locale   string `json:"locale,omitempty"`
+	Version  int    `json:"version"`
+	Deleted  bool   `json:"deleted"`
+}
+
+// UserHistoryEntry is a single recorded change to a user.
+type UserHistoryEntry struct {
+	ID        int64         `json:"id"`
+	UserID    int           `json:"user_id"`
+	Action    string        `json:"action"`
+	Version   int           `json:"version"`
+	Before    *UserSnapshot `json:"before"`
+	After     *UserSnapshot `json:"after"`
+	Actor     string        `json:"actor"`
+	RequestID string        `json:"request_id,omitempty"`
+	ChangedAt time.Time     `json:"changed_at"`
+}
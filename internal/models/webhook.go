@@ -0,0 +1,45 @@
+package models
+
+import "time"
+
+// Webhook delivery statuses.
+const (
+	DeliveryPending   = "pending"
+	DeliveryDelivered = "delivered"
+	// DeliveryDead marks a delivery that exhausted its retries. It is only
+	// attempted again when replayed.
+	DeliveryDead = "dead"
+)
+
+// WebhookSubscription is a URL that receives user events. An empty
+// EventTypes subscribes to every event type.
+type WebhookSubscription struct {
+	ID         int       `json:"id"`
+	URL        string    `json:"url"`
+	EventTypes []string  `json:"event_types"`
+	Active     bool      `json:"active"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+	// Secret signs deliveries. It is only returned when the subscription is
+	// created.
+	Secret string `json:"secret,omitempty"`
+}
+
+// WebhookDelivery is the delivery of one event to one subscription.
+type WebhookDelivery struct {
+	ID             int64      `json:"id"`
+	EventID        int64      `json:"event_id"`
+	SubscriptionID int        `json:"subscription_id"`
+	Status         string     `json:"status"`
+	Attempts       int        `json:"attempts"`
+	NextAttemptAt  time.Time  `json:"next_attempt_at"`
+	LastStatusCode *int       `json:"last_status_code,omitempty"`
+	LastError      *string    `json:"last_error,omitempty"`
+	DeliveredAt    *time.Time `json:"delivered_at,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+}
+
+type CreateWebhookRequest struct {
+	URL        string   `json:"url" validate:"required,url"`
+	EventTypes []string `json:"event_types" validate:"dive,oneof=user.created user.updated user.deleted user.restored user.birthday_digest"`
+}
@@ -0,0 +1,33 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/Pallavi566/Go-Backend/db/sqlc"
+	"github.com/Pallavi566/Go-Backend/internal/age"
+	"github.com/Pallavi566/Go-Backend/internal/audit"
+	"github.com/Pallavi566/Go-Backend/internal/models"
+)
+
+// RecordAgeCheck logs an age lookup or check for compliance, along with the
+// date of birth and leap day rule it was computed with and who asked.
+func (r *UserRepository) RecordAgeCheck(ctx context.Context, u *models.User, check models.AgeCheck, asOf time.Time, rule age.LeapDayRule) error {
+	params := sqlc.CreateAgeCheckParams{
+		UserID:      int32(u.ID),
+		Dob:         u.DOB,
+		AsOf:        asOf,
+		Age:         int32(check.Age),
+		LeapDayRule: rule.String(),
+		Actor:       audit.Actor(ctx),
+		RequestID:   audit.RequestID(ctx),
+	}
+	if check.MinAge != nil {
+		params.MinAge = sql.NullInt32{Int32: int32(*check.MinAge), Valid: true}
+	}
+	if check.MeetsMinAge != nil {
+		params.Passed = sql.NullBool{Bool: *check.MeetsMinAge, Valid: true}
+	}
+	return dbError(r.queries.CreateAgeCheck(ctx, params))
+}
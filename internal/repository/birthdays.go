@@ -0,0 +1,130 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/Pallavi566/Go-Backend/db/sqlc"
+	"github.com/Pallavi566/Go-Backend/internal/models"
+)
+
+// leapDay is February 29 as a birthday column value.
+const leapDay = 229
+
+// BirthdayRange is a range of birthdays within one calendar year, as MMDD
+// numbers like the birthday column (e.g. 1231 for December 31).
+type BirthdayRange struct {
+	From, To int
+}
+
+// BirthdayWindow selects the active users whose birthday falls in a window
+// of dates. A window spanning New Year has two ranges, in window order.
+type BirthdayWindow struct {
+	Ranges []BirthdayRange
+	// LeapDay is the MMDD on which people born on February 29 have their
+	// birthday within the window: 229 in a leap year, 228 or 301 in a common
+	// year depending on the leap day rule, or zero when it is not in the
+	// window.
+	LeapDay int
+}
+
+// whereClause builds the WHERE clause selecting the window. Each range is
+// served by the birthday index. February 29 birthdays are matched
+// separately, as they fall on another date in common years.
+func (w BirthdayWindow) whereClause() (string, []interface{}) {
+	var ranges []string
+	var args []interface{}
+	for _, r := range w.Ranges {
+		ranges = append(ranges, "birthday BETWEEN ? AND ?")
+		args = append(args, r.From, r.To)
+	}
+	if w.LeapDay != 0 {
+		ranges = append(ranges, "birthday = ?")
+		args = append(args, leapDay)
+	}
+	if len(ranges) == 0 {
+		// An empty window matches nobody.
+		ranges = append(ranges, "FALSE")
+	}
+
+	return " WHERE deleted_at IS NULL AND (" + strings.Join(ranges, " OR ") + ")", args
+}
+
+// orderByClause sorts users by the date of their birthday in the window:
+// the ranges are in window order and each one after the first is earlier in
+// the year than the one before.
+func (w BirthdayWindow) orderByClause() (string, []interface{}) {
+	if len(w.Ranges) == 0 {
+		return " ORDER BY id", nil
+	}
+	const day = "IF(birthday = ?, ?, birthday)"
+	return " ORDER BY " + day + " < ?, " + day + ", id",
+		[]interface{}{leapDay, w.LeapDay, w.Ranges[0].From, leapDay, w.LeapDay}
+}
+
+// GetByBirthday returns a page of the users whose birthday is in the
+// window, in order of their birthday.
+func (r *UserRepository) GetByBirthday(ctx context.Context, w BirthdayWindow, limit, offset int) ([]*models.User, error) {
+	where, args := w.whereClause()
+	orderBy, orderArgs := w.orderByClause()
+	query := "SELECT " + userColumns + " FROM users" + where + orderBy + " LIMIT ? OFFSET ?"
+	args = append(append(args, orderArgs...), limit, offset)
+	return r.queryUsers(ctx, named("ListUsersByBirthday", query), args...)
+}
+
+// CountByBirthday returns the number of users whose birthday is in the
+// window.
+func (r *UserRepository) CountByBirthday(ctx context.Context, w BirthdayWindow) (int64, error) {
+	where, args := w.whereClause()
+	var count int64
+	if err := r.instrumented.QueryRowContext(ctx, named("CountUsersByBirthday", "SELECT COUNT(*) FROM users"+where), args...).Scan(&count); err != nil {
+		return 0, dbError(err)
+	}
+	return count, nil
+}
+
+// StreamByBirthday calls fn for every user whose birthday is in the window,
+// in order of their birthday.
+func (r *UserRepository) StreamByBirthday(ctx context.Context, w BirthdayWindow, fn func(u *models.User) error) error {
+	where, args := w.whereClause()
+	orderBy, orderArgs := w.orderByClause()
+	return r.eachUser(ctx, named("StreamUsersByBirthday", "SELECT "+userColumns+" FROM users"+where+orderBy), append(args, orderArgs...), fn)
+}
+
+// BirthdayDigestSent reports whether the digest of the given day has been
+// published.
+func (r *UserRepository) BirthdayDigestSent(ctx context.Context, day time.Time) (bool, error) {
+	return r.queries.BirthdayDigestExists(ctx, day)
+}
+
+// PublishBirthdayDigest writes the digest of a day to the outbox, from where
+// it is delivered to webhooks and the event stream. It reports false, and
+// publishes nothing, when the day's digest was already published.
+func (r *UserRepository) PublishBirthdayDigest(ctx context.Context, day time.Time, digest models.BirthdayDigestData) (bool, error) {
+	payload, err := json.Marshal(digest)
+	if err != nil {
+		return false, err
+	}
+
+	published := false
+	err = r.withTx(ctx, func(q *sqlc.Queries) error {
+		n, err := q.ClaimBirthdayDigest(ctx, sqlc.ClaimBirthdayDigestParams{
+			DigestDate: day,
+			UserCount:  int32(len(digest.Users)),
+		})
+		if err != nil || n == 0 {
+			return err
+		}
+		// The digest is about no single user.
+		_, err = q.CreateOutboxEvent(ctx, sqlc.CreateOutboxEventParams{
+			EventType:   models.EventBirthdayDigest,
+			AggregateID: 0,
+			Payload:     payload,
+		})
+		published = err == nil
+		return err
+	})
+	return published, err
+}
@@ -0,0 +1,60 @@
+package repository
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestBirthdayWindowClauses(t *testing.T) {
+	tests := []struct {
+		name      string
+		window    BirthdayWindow
+		wantWhere string
+		wantArgs  []interface{}
+	}{
+		{
+			name:      "Single range",
+			window:    BirthdayWindow{Ranges: []BirthdayRange{{From: 510, To: 517}}},
+			wantWhere: " WHERE deleted_at IS NULL AND (birthday BETWEEN ? AND ?)",
+			wantArgs:  []interface{}{510, 517},
+		},
+		{
+			name:      "Across New Year",
+			window:    BirthdayWindow{Ranges: []BirthdayRange{{From: 1220, To: 1231}, {From: 101, To: 110}}},
+			wantWhere: " WHERE deleted_at IS NULL AND (birthday BETWEEN ? AND ? OR birthday BETWEEN ? AND ?)",
+			wantArgs:  []interface{}{1220, 1231, 101, 110},
+		},
+		{
+			name:      "Leap day birthdays on Feb 28",
+			window:    BirthdayWindow{Ranges: []BirthdayRange{{From: 220, To: 228}}, LeapDay: 228},
+			wantWhere: " WHERE deleted_at IS NULL AND (birthday BETWEEN ? AND ? OR birthday = ?)",
+			wantArgs:  []interface{}{220, 228, 229},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			where, args := tt.window.whereClause()
+			if where != tt.wantWhere {
+				t.Errorf("whereClause() = %q, want %q", where, tt.wantWhere)
+			}
+			if !reflect.DeepEqual(args, tt.wantArgs) {
+				t.Errorf("whereClause() args = %v, want %v", args, tt.wantArgs)
+			}
+		})
+	}
+}
+
+func TestBirthdayWindowOrderBy(t *testing.T) {
+	w := BirthdayWindow{Ranges: []BirthdayRange{{From: 1220, To: 1231}, {From: 101, To: 305}}, LeapDay: 301}
+	orderBy, args := w.orderByClause()
+
+	wantOrderBy := " ORDER BY IF(birthday = ?, ?, birthday) < ?, IF(birthday = ?, ?, birthday), id"
+	if orderBy != wantOrderBy {
+		t.Errorf("orderByClause() = %q, want %q", orderBy, wantOrderBy)
+	}
+	wantArgs := []interface{}{229, 301, 1220, 229, 301}
+	if !reflect.DeepEqual(args, wantArgs) {
+		t.Errorf("orderByClause() args = %v, want %v", args, wantArgs)
+	}
+}
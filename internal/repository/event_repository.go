@@ -0,0 +1,52 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/Pallavi566/Go-Backend/db/sqlc"
+	"github.com/Pallavi566/Go-Backend/internal/models"
+)
+
+// EventRepository reads user events back from the outbox. Outbox IDs are
+// increasing, so they double as the sequence number of the change feed.
+type EventRepository struct {
+	queries *sqlc.Queries
+}
+
+func NewEventRepository(db *sql.DB) *EventRepository {
+	return &EventRepository{queries: newQueries(db)}
+}
+
+// ListAfter returns up to limit events with an ID greater than afterID,
+// oldest first.
+func (r *EventRepository) ListAfter(ctx context.Context, afterID int64, limit int) ([]models.Event, error) {
+	rows, err := r.queries.ListOutboxEventsAfter(ctx, sqlc.ListOutboxEventsAfterParams{
+		ID:    afterID,
+		Limit: int32(limit),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	events := make([]models.Event, 0, len(rows))
+	for _, row := range rows {
+		events = append(events, models.Event{
+			ID:         row.ID,
+			Type:       row.EventType,
+			OccurredAt: row.CreatedAt.UTC(),
+			Data:       row.Payload,
+		})
+	}
+	return events, nil
+}
+
+// LatestID returns the ID of the most recent event, or zero when there are
+// none.
+func (r *EventRepository) LatestID(ctx context.Context) (int64, error) {
+	id, err := r.queries.GetLatestOutboxEventID(ctx)
+	if err == sql.ErrNoRows {
+		return 0, nil
+	}
+	return id, err
+}
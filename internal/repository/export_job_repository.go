@@ -0,0 +1,225 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Pallavi566/Go-Backend/db/sqlc"
+	"github.com/Pallavi566/Go-Backend/internal/apperr"
+	"github.com/Pallavi566/Go-Backend/internal/models"
+)
+
+var (
+	// ErrExportJobNotFound is returned when an export job does not exist.
+	ErrExportJobNotFound = apperr.New(apperr.ErrNotFound, "export_not_found", "export job not found")
+	// ErrExportJobSuperseded is returned when updating an attempt at a job
+	// that is no longer running it, e.g. because the job was requeued as
+	// stale and claimed again by another worker.
+	ErrExportJobSuperseded = errors.New("export job superseded by another attempt")
+)
+
+type ExportJobRepository struct {
+	queries *sqlc.Queries
+}
+
+func NewExportJobRepository(db *sql.DB) *ExportJobRepository {
+	return &ExportJobRepository{queries: newQueries(db)}
+}
+
+// Create queues an export job and returns its ID.
+func (r *ExportJobRepository) Create(ctx context.Context, format string, filters models.UserFilter, requestedBy, requestID string) (int64, error) {
+	data, err := json.Marshal(filters)
+	if err != nil {
+		return 0, err
+	}
+	result, err := r.queries.CreateExportJob(ctx, sqlc.CreateExportJobParams{
+		Format:      format,
+		Filters:     data,
+		RequestedBy: requestedBy,
+		RequestID:   requestID,
+	})
+	if err != nil {
+		return 0, err
+	}
+	return result.LastInsertId()
+}
+
+func (r *ExportJobRepository) Get(ctx context.Context, id int64) (*models.ExportJob, error) {
+	row, err := r.queries.GetExportJob(ctx, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("%w: id %d", ErrExportJobNotFound, id)
+		}
+		return nil, dbError(err)
+	}
+
+	job := &models.ExportJob{
+		ID:            row.ID,
+		Status:        row.Status,
+		Format:        row.Format,
+		ProcessedRows: row.ProcessedRows,
+		Attempts:      int(row.Attempts),
+		Error:         row.Error.String,
+		RequestedBy:   row.RequestedBy,
+		CreatedAt:     row.CreatedAt,
+		FilePath:      row.FilePath.String,
+	}
+	if err := json.Unmarshal(row.Filters, &job.Filters); err != nil {
+		return nil, fmt.Errorf("export job %d: %w", id, err)
+	}
+	if row.TotalRows.Valid {
+		job.TotalRows = &row.TotalRows.Int64
+	}
+	if row.FileSize.Valid {
+		job.FileSize = &row.FileSize.Int64
+	}
+	if row.StartedAt.Valid {
+		job.StartedAt = &row.StartedAt.Time
+	}
+	if row.FinishedAt.Valid {
+		job.FinishedAt = &row.FinishedAt.Time
+	}
+	return job, nil
+}
+
+// Queued returns the IDs of up to limit queued jobs, oldest first.
+func (r *ExportJobRepository) Queued(ctx context.Context, limit int) ([]int64, error) {
+	return r.queries.ListQueuedExportJobIDs(ctx, int32(limit))
+}
+
+// Claim marks a queued job as running. It reports false when the job is no
+// longer queued, e.g. because another worker claimed it first.
+func (r *ExportJobRepository) Claim(ctx context.Context, id int64, now time.Time) (bool, error) {
+	n, err := r.queries.ClaimExportJob(ctx, sqlc.ClaimExportJobParams{
+		StartedAt:   sql.NullTime{Time: now, Valid: true},
+		HeartbeatAt: sql.NullTime{Time: now, Valid: true},
+		ID:          id,
+	})
+	if err != nil {
+		return false, err
+	}
+	return n == 1, nil
+}
+
+// UpdateProgress records the number of rows attempt at a running job is to
+// write and has written.
+func (r *ExportJobRepository) UpdateProgress(ctx context.Context, id int64, attempt int, total, processed int64, now time.Time) error {
+	n, err := r.queries.UpdateExportJobProgress(ctx, sqlc.UpdateExportJobProgressParams{
+		TotalRows:     sql.NullInt64{Int64: total, Valid: true},
+		ProcessedRows: processed,
+		HeartbeatAt:   sql.NullTime{Time: now, Valid: true},
+		ID:            id,
+		Attempts:      int32(attempt),
+	})
+	return attemptResult(id, attempt, n, err)
+}
+
+// Heartbeat records that attempt at a running job is alive, along with the
+// number of rows it has written.
+func (r *ExportJobRepository) Heartbeat(ctx context.Context, id int64, attempt int, processed int64, now time.Time) error {
+	n, err := r.queries.HeartbeatExportJob(ctx, sqlc.HeartbeatExportJobParams{
+		ProcessedRows: processed,
+		HeartbeatAt:   sql.NullTime{Time: now, Valid: true},
+		ID:            id,
+		Attempts:      int32(attempt),
+	})
+	return attemptResult(id, attempt, n, err)
+}
+
+func (r *ExportJobRepository) Complete(ctx context.Context, id int64, attempt int, processed int64, path string, size int64, now time.Time) error {
+	n, err := r.queries.CompleteExportJob(ctx, sqlc.CompleteExportJobParams{
+		ProcessedRows: processed,
+		FilePath:      sql.NullString{String: path, Valid: true},
+		FileSize:      sql.NullInt64{Int64: size, Valid: true},
+		FinishedAt:    sql.NullTime{Time: now, Valid: true},
+		ID:            id,
+		Attempts:      int32(attempt),
+	})
+	return attemptResult(id, attempt, n, err)
+}
+
+func (r *ExportJobRepository) Fail(ctx context.Context, id int64, attempt int, reason string, now time.Time) error {
+	n, err := r.queries.FailExportJob(ctx, sqlc.FailExportJobParams{
+		Error:      sql.NullString{String: reason, Valid: true},
+		FinishedAt: sql.NullTime{Time: now, Valid: true},
+		ID:         id,
+		Attempts:   int32(attempt),
+	})
+	return attemptResult(id, attempt, n, err)
+}
+
+// Requeue puts a job running attempt back in the queue, e.g. when its
+// worker is shutting down.
+func (r *ExportJobRepository) Requeue(ctx context.Context, id int64, attempt int) error {
+	n, err := r.queries.RequeueExportJob(ctx, sqlc.RequeueExportJobParams{
+		ID:       id,
+		Attempts: int32(attempt),
+	})
+	return attemptResult(id, attempt, n, err)
+}
+
+// attemptResult returns the error of an update of attempt at a running job
+// that changed n rows.
+func attemptResult(id int64, attempt int, n int64, err error) error {
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return fmt.Errorf("%w: id %d, attempt %d", ErrExportJobSuperseded, id, attempt)
+	}
+	return nil
+}
+
+// RequeueStale puts running jobs whose heartbeat is older than cutoff, and
+// that have been attempted fewer than maxAttempts times, back in the queue.
+// Such jobs were orphaned by a worker that died.
+func (r *ExportJobRepository) RequeueStale(ctx context.Context, cutoff time.Time, maxAttempts int) (int64, error) {
+	return r.queries.RequeueStaleExportJobs(ctx, sqlc.RequeueStaleExportJobsParams{
+		HeartbeatAt: sql.NullTime{Time: cutoff, Valid: true},
+		Attempts:    int32(maxAttempts),
+	})
+}
+
+// FailStale marks running jobs whose heartbeat is older than cutoff, and
+// that have been attempted maxAttempts times already, as failed, so that a
+// job that keeps killing its worker is not retried forever.
+func (r *ExportJobRepository) FailStale(ctx context.Context, cutoff time.Time, maxAttempts int, reason string, now time.Time) (int64, error) {
+	return r.queries.FailStaleExportJobs(ctx, sqlc.FailStaleExportJobsParams{
+		Error:       sql.NullString{String: reason, Valid: true},
+		FinishedAt:  sql.NullTime{Time: now, Valid: true},
+		HeartbeatAt: sql.NullTime{Time: cutoff, Valid: true},
+		Attempts:    int32(maxAttempts),
+	})
+}
+
+// ExpiredJob is a finished export job due for deletion, with the path of
+// its file, if any.
+type ExpiredJob struct {
+	ID       int64
+	FilePath string
+}
+
+// Expired returns up to limit jobs that finished before cutoff, oldest
+// first.
+func (r *ExportJobRepository) Expired(ctx context.Context, cutoff time.Time, limit int) ([]ExpiredJob, error) {
+	rows, err := r.queries.ListExpiredExportJobs(ctx, sqlc.ListExpiredExportJobsParams{
+		FinishedAt: sql.NullTime{Time: cutoff, Valid: true},
+		Limit:      int32(limit),
+	})
+	if err != nil {
+		return nil, err
+	}
+	jobs := make([]ExpiredJob, 0, len(rows))
+	for _, row := range rows {
+		jobs = append(jobs, ExpiredJob{ID: row.ID, FilePath: row.FilePath.String})
+	}
+	return jobs, nil
+}
+
+func (r *ExportJobRepository) Delete(ctx context.Context, id int64) error {
+	return r.queries.DeleteExportJob(ctx, id)
+}
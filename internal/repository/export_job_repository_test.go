@@ -0,0 +1,47 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Pallavi566/Go-Backend/db/sqlc"
+)
+
+// rowsDB reports every statement as having changed n rows.
+type rowsDB struct {
+	fakeDB
+	n int64
+}
+
+func (r rowsDB) ExecContext(context.Context, string, ...interface{}) (sql.Result, error) {
+	return driver.RowsAffected(r.n), nil
+}
+
+func TestExportJobAttemptUpdates(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	updates := map[string]func(r *ExportJobRepository) error{
+		"UpdateProgress": func(r *ExportJobRepository) error { return r.UpdateProgress(ctx, 1, 2, 10, 0, now) },
+		"Heartbeat":      func(r *ExportJobRepository) error { return r.Heartbeat(ctx, 1, 2, 5, now) },
+		"Complete":       func(r *ExportJobRepository) error { return r.Complete(ctx, 1, 2, 10, "users.csv", 100, now) },
+		"Fail":           func(r *ExportJobRepository) error { return r.Fail(ctx, 1, 2, "boom", now) },
+		"Requeue":        func(r *ExportJobRepository) error { return r.Requeue(ctx, 1, 2) },
+	}
+
+	for name, update := range updates {
+		t.Run(name, func(t *testing.T) {
+			current := &ExportJobRepository{queries: sqlc.New(rowsDB{n: 1})}
+			if err := update(current); err != nil {
+				t.Errorf("update of the current attempt error = %v", err)
+			}
+			superseded := &ExportJobRepository{queries: sqlc.New(rowsDB{n: 0})}
+			if err := update(superseded); !errors.Is(err, ErrExportJobSuperseded) {
+				t.Errorf("update of a superseded attempt error = %v, want ErrExportJobSuperseded", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,44 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/Pallavi566/Go-Backend/db/sqlc"
+)
+
+// SchemaVersion is the migration the code expects the database to be at:
+// the number of the last file in db/migrations.
+const SchemaVersion = 14
+
+// HealthRepository checks that the database can serve the application.
+type HealthRepository struct {
+	db      *sql.DB
+	queries *sqlc.Queries
+}
+
+func NewHealthRepository(db *sql.DB) *HealthRepository {
+	return &HealthRepository{db: db, queries: newQueries(db)}
+}
+
+// Ping checks that the database can be reached.
+func (r *HealthRepository) Ping(ctx context.Context) error {
+	return dbError(r.db.PingContext(ctx))
+}
+
+// CheckSchema checks that the migrations applied to the database reach
+// SchemaVersion. Later migrations are accepted: they must keep the schema
+// compatible with the code running before them, which is still serving
+// while they roll out.
+func (r *HealthRepository) CheckSchema(ctx context.Context) error {
+	version, err := r.queries.GetSchemaVersion(ctx)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return dbError(err)
+	}
+	if version < SchemaVersion {
+		return fmt.Errorf("schema at version %d, want %d", version, SchemaVersion)
+	}
+	return nil
+}
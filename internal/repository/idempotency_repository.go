@@ -0,0 +1,125 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/Pallavi566/Go-Backend/db/sqlc"
+	"github.com/Pallavi566/Go-Backend/internal/models"
+)
+
+type IdempotencyRepository struct {
+	queries *sqlc.Queries
+}
+
+func NewIdempotencyRepository(db *sql.DB) *IdempotencyRepository {
+	return &IdempotencyRepository{queries: newQueries(db)}
+}
+
+// Reserve claims key within scope for a request with the given hash until
+// expiresAt. It returns nil once the key is claimed, or the record of the
+// request that holds it already. Expired keys, including reservations whose
+// request never completed, are claimed anew.
+func (r *IdempotencyRepository) Reserve(ctx context.Context, scope, key, hash string, expiresAt time.Time) (*models.IdempotencyRecord, error) {
+	// A key released between the insert and the read is claimed on the next
+	// attempt.
+	for attempt := 0; attempt < 3; attempt++ {
+		err := r.queries.DeleteExpiredIdempotencyKey(ctx, sqlc.DeleteExpiredIdempotencyKeyParams{
+			Scope:          scope,
+			IdempotencyKey: key,
+			ExpiresAt:      time.Now(),
+		})
+		if err != nil {
+			return nil, dbError(err)
+		}
+
+		n, err := r.queries.ReserveIdempotencyKey(ctx, sqlc.ReserveIdempotencyKeyParams{
+			Scope:          scope,
+			IdempotencyKey: key,
+			RequestHash:    hash,
+			ExpiresAt:      expiresAt,
+		})
+		if err != nil {
+			return nil, dbError(err)
+		}
+		if n == 1 {
+			return nil, nil
+		}
+
+		row, err := r.queries.GetIdempotencyKey(ctx, sqlc.GetIdempotencyKeyParams{
+			Scope:          scope,
+			IdempotencyKey: key,
+		})
+		if err == sql.ErrNoRows {
+			continue
+		}
+		if err != nil {
+			return nil, dbError(err)
+		}
+		var headers map[string]string
+		if len(row.ResponseHeaders) > 0 {
+			if err := json.Unmarshal(row.ResponseHeaders, &headers); err != nil {
+				return nil, fmt.Errorf("idempotency key %q: response headers: %w", key, err)
+			}
+		}
+		return &models.IdempotencyRecord{
+			RequestHash: row.RequestHash,
+			StatusCode:  int(row.StatusCode.Int32),
+			ContentType: row.ContentType.String,
+			Headers:     headers,
+			Body:        row.ResponseBody,
+			ExpiresAt:   row.ExpiresAt,
+		}, nil
+	}
+	return nil, fmt.Errorf("idempotency key %q: contended, giving up", key)
+}
+
+// Complete stores the response to the request holding key and keeps it
+// until rec.ExpiresAt.
+func (r *IdempotencyRepository) Complete(ctx context.Context, scope, key string, rec models.IdempotencyRecord) error {
+	var headers json.RawMessage
+	if len(rec.Headers) > 0 {
+		var err error
+		if headers, err = json.Marshal(rec.Headers); err != nil {
+			return err
+		}
+	}
+	return dbError(r.queries.CompleteIdempotencyKey(ctx, sqlc.CompleteIdempotencyKeyParams{
+		StatusCode:      sql.NullInt32{Int32: int32(rec.StatusCode), Valid: true},
+		ContentType:     sql.NullString{String: rec.ContentType, Valid: rec.ContentType != ""},
+		ResponseHeaders: headers,
+		ResponseBody:    rec.Body,
+		ExpiresAt:       rec.ExpiresAt,
+		Scope:           scope,
+		IdempotencyKey:  key,
+	}))
+}
+
+// Release frees key so that the request can be retried, e.g. after it
+// failed with a server error.
+func (r *IdempotencyRepository) Release(ctx context.Context, scope, key string) error {
+	return dbError(r.queries.DeleteIdempotencyKey(ctx, sqlc.DeleteIdempotencyKeyParams{
+		Scope:          scope,
+		IdempotencyKey: key,
+	}))
+}
+
+// PurgeExpired deletes keys that expired before now, in batches, and
+// returns how many were deleted.
+func (r *IdempotencyRepository) PurgeExpired(ctx context.Context, now time.Time) (int64, error) {
+	const batchSize = 1000
+	var total int64
+	for {
+		n, err := r.queries.PurgeExpiredIdempotencyKeys(ctx, sqlc.PurgeExpiredIdempotencyKeysParams{
+			ExpiresAt: now,
+			Limit:     batchSize,
+		})
+		total += n
+		if err != nil || n < batchSize {
+			return total, dbError(err)
+		}
+	}
+}
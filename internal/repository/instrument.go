@@ -0,0 +1,99 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"time"
+
+	"github.com/Pallavi566/Go-Backend/db/sqlc"
+	"github.com/Pallavi566/Go-Backend/internal/metrics"
+	"github.com/Pallavi566/Go-Backend/internal/tracing"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// instrumentedDB times and traces the queries run through it by their sqlc
+// name.
+type instrumentedDB struct {
+	db sqlc.DBTX
+}
+
+// newQueries returns sqlc queries bound to db, a database or transaction,
+// whose latency is recorded in the metrics and which each get a span.
+func newQueries(db sqlc.DBTX) *sqlc.Queries {
+	return sqlc.New(instrumentedDB{db: db})
+}
+
+func (i instrumentedDB) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	ctx, done := startQuery(ctx, query)
+	result, err := i.db.ExecContext(ctx, query, args...)
+	done(err)
+	return result, err
+}
+
+func (i instrumentedDB) PrepareContext(ctx context.Context, query string) (*sql.Stmt, error) {
+	ctx, done := startQuery(ctx, query)
+	stmt, err := i.db.PrepareContext(ctx, query)
+	done(err)
+	return stmt, err
+}
+
+func (i instrumentedDB) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	ctx, done := startQuery(ctx, query)
+	rows, err := i.db.QueryContext(ctx, query, args...)
+	done(err)
+	return rows, err
+}
+
+// QueryRowContext records the time taken to run the query; its error, if
+// any, is only known once the row is scanned.
+func (i instrumentedDB) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	ctx, done := startQuery(ctx, query)
+	row := i.db.QueryRowContext(ctx, query, args...)
+	done(nil)
+	return row
+}
+
+// startQuery starts the span of a query. The returned function ends it and
+// records the query's latency.
+func startQuery(ctx context.Context, query string) (context.Context, func(err error)) {
+	name := queryName(query)
+	ctx, span := tracing.Tracer().Start(ctx, name,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			semconv.DBSystemMySQL,
+			semconv.DBOperationName(name),
+			semconv.DBQueryText(query),
+		),
+	)
+	start := time.Now()
+	return ctx, func(err error) {
+		metrics.ObserveQuery(name, time.Since(start), err)
+		if err != nil && err != sql.ErrNoRows {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		}
+		span.End()
+	}
+}
+
+// queryName returns the name of a sqlc query from the comment it starts
+// with, e.g. GetUserByID for "-- name: GetUserByID :one".
+func queryName(query string) string {
+	rest, ok := strings.CutPrefix(query, "-- name: ")
+	if !ok {
+		return "unknown"
+	}
+	if i := strings.IndexAny(rest, " \n"); i >= 0 {
+		rest = rest[:i]
+	}
+	return rest
+}
+
+// named prefixes a query built at run time with a comment naming it like
+// sqlc queries, so that it is timed and traced under that name.
+func named(name, query string) string {
+	return "-- name: " + name + "\n" + query
+}
@@ -0,0 +1,74 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/Pallavi566/Go-Backend/internal/tracing"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestQueryName(t *testing.T) {
+	tests := []struct {
+		query string
+		want  string
+	}{
+		{"-- name: GetUserByID :one\nSELECT id FROM users WHERE id = ?", "GetUserByID"},
+		{"-- name: PurgeDeletedUsers :execrows\nDELETE FROM users", "PurgeDeletedUsers"},
+		{named("CountUsersFiltered", "SELECT COUNT(*) FROM users"), "CountUsersFiltered"},
+		{"SELECT COUNT(*) FROM users", "unknown"},
+	}
+
+	for _, tt := range tests {
+		if got := queryName(tt.query); got != tt.want {
+			t.Errorf("queryName(%q) = %q, want %q", tt.query, got, tt.want)
+		}
+	}
+}
+
+func TestInstrumentedDBSpans(t *testing.T) {
+	exporter := tracing.SetupInMemory()
+	db := instrumentedDB{db: fakeDB{err: errors.New("connection refused")}}
+
+	if _, err := db.ExecContext(context.Background(), "-- name: DeleteUser :exec\nUPDATE users SET deleted_at = NOW() WHERE id = ?", 5); err == nil {
+		t.Fatal("ExecContext() succeeded, want the error of the database")
+	}
+
+	spans := exporter.GetSpans()
+	if len(spans) != 1 {
+		t.Fatalf("got %d spans, want 1", len(spans))
+	}
+	if spans[0].Name != "DeleteUser" {
+		t.Errorf("name = %q, want DeleteUser", spans[0].Name)
+	}
+	if spans[0].SpanKind != trace.SpanKindClient {
+		t.Errorf("kind = %v, want client", spans[0].SpanKind)
+	}
+	if spans[0].Status.Code != codes.Error {
+		t.Errorf("status = %v, want error", spans[0].Status.Code)
+	}
+}
+
+// fakeDB fails every statement with err.
+type fakeDB struct {
+	err error
+}
+
+func (f fakeDB) ExecContext(context.Context, string, ...interface{}) (sql.Result, error) {
+	return nil, f.err
+}
+
+func (f fakeDB) PrepareContext(context.Context, string) (*sql.Stmt, error) {
+	return nil, f.err
+}
+
+func (f fakeDB) QueryContext(context.Context, string, ...interface{}) (*sql.Rows, error) {
+	return nil, f.err
+}
+
+func (f fakeDB) QueryRowContext(context.Context, string, ...interface{}) *sql.Row {
+	return nil
+}
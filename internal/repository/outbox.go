@@ -0,0 +1,57 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/Pallavi566/Go-Backend/db/sqlc"
+	"github.com/Pallavi566/Go-Backend/internal/audit"
+	"github.com/Pallavi566/Go-Backend/internal/models"
+)
+
+// eventTypes maps history actions to the outbox event they publish.
+var eventTypes = map[string]string{
+	models.HistoryCreated:  models.EventUserCreated,
+	models.HistoryUpdated:  models.EventUserUpdated,
+	models.HistoryDeleted:  models.EventUserDeleted,
+	models.HistoryRestored: models.EventUserRestored,
+}
+
+// recordChange records a change from before to after in the user's history
+// and publishes the matching event to the outbox. It must run in the
+// transaction making the change, so that the event is written if and only
+// if the change is committed.
+func recordChange(ctx context.Context, q *sqlc.Queries, action string, before, after *models.User) error {
+	if err := recordHistory(ctx, q, action, before, after); err != nil {
+		return err
+	}
+	return recordEvent(ctx, q, eventTypes[action], before, after)
+}
+
+// recordEvent writes an event for a change from before to after to the
+// outbox, from where the webhook dispatcher picks it up.
+func recordEvent(ctx context.Context, q *sqlc.Queries, eventType string, before, after *models.User) error {
+	data := models.UserEventData{
+		Actor:     audit.Actor(ctx),
+		RequestID: audit.RequestID(ctx),
+	}
+	subject := after
+	if subject == nil {
+		subject = before
+	}
+	data.User = snapshotOf(subject)
+	if before != nil && after != nil {
+		data.Previous = snapshotOf(before)
+	}
+
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	_, err = q.CreateOutboxEvent(ctx, sqlc.CreateOutboxEventParams{
+		EventType:   eventType,
+		AggregateID: int32(subject.ID),
+		Payload:     payload,
+	})
+	return err
+}
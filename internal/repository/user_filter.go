@@ -0,0 +1,229 @@
+package repository
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Pallavi566/Go-Backend/internal/apperr"
+	"github.com/Pallavi566/Go-Backend/internal/models"
+)
+
+// ErrInvalidKeyset is returned when UserFilter.After does not describe a
+// valid position for the filter's sort order.
+var ErrInvalidKeyset = apperr.New(apperr.ErrValidation, "invalid_keyset", "invalid keyset position")
+
+// keyTimeLayout is the layout used to serialize timestamp keyset values.
+const keyTimeLayout = "2006-01-02T15:04:05.999999999Z07:00"
+
+// sortColumns whitelists the fields a listing can be ordered by and maps
+// them to their column names. Only values from this map are ever
+// interpolated into SQL.
+var sortColumns = map[string]string{
+	"id":         "id",
+	"name":       "name",
+	"dob":        "dob",
+	"created_at": "created_at",
+	"updated_at": "updated_at",
+}
+
+// SortField is a single ORDER BY term of a user listing.
+type SortField struct {
+	Column string
+	Desc   bool
+}
+
+// UserFilter narrows down a user listing. Zero values mean "no filter".
+type UserFilter struct {
+	NameContains string
+	DOBFrom      *time.Time
+	DOBTo        *time.Time
+	Sort         []SortField
+
+	// IncludeDeleted also returns soft-deleted users.
+	IncludeDeleted bool
+
+	// After holds the keyset position, as produced by KeyOf, of the last
+	// row already seen. It is only used by GetAfter.
+	After []string
+}
+
+// ParseSort parses a sort expression such as "-dob,name" into sort fields.
+// Unknown or repeated fields are rejected.
+func ParseSort(expr string) ([]SortField, error) {
+	if strings.TrimSpace(expr) == "" {
+		return nil, nil
+	}
+
+	var fields []SortField
+	seen := make(map[string]bool)
+	for _, part := range strings.Split(expr, ",") {
+		part = strings.TrimSpace(part)
+		desc := false
+		if strings.HasPrefix(part, "-") {
+			desc = true
+			part = part[1:]
+		} else if strings.HasPrefix(part, "+") {
+			part = part[1:]
+		}
+
+		column, ok := sortColumns[part]
+		if !ok {
+			return nil, fmt.Errorf("unknown sort field %q", part)
+		}
+		if seen[column] {
+			return nil, fmt.Errorf("duplicate sort field %q", part)
+		}
+		seen[column] = true
+		fields = append(fields, SortField{Column: column, Desc: desc})
+	}
+	return fields, nil
+}
+
+// FormatSort is the inverse of ParseSort.
+func FormatSort(fields []SortField) string {
+	parts := make([]string, len(fields))
+	for i, f := range fields {
+		if f.Desc {
+			parts[i] = "-" + f.Column
+		} else {
+			parts[i] = f.Column
+		}
+	}
+	return strings.Join(parts, ",")
+}
+
+// orderTerms returns the effective sort order: the requested fields followed
+// by id as a tie-breaker unless id is already part of the order.
+func (f UserFilter) orderTerms() []SortField {
+	for _, s := range f.Sort {
+		if s.Column == "id" {
+			return f.Sort
+		}
+	}
+	terms := make([]SortField, len(f.Sort), len(f.Sort)+1)
+	copy(terms, f.Sort)
+	return append(terms, SortField{Column: "id"})
+}
+
+// KeyOf returns the keyset position of u under the filter's sort order.
+func (f UserFilter) KeyOf(u *models.User) []string {
+	terms := f.orderTerms()
+	key := make([]string, len(terms))
+	for i, t := range terms {
+		switch t.Column {
+		case "id":
+			key[i] = strconv.Itoa(u.ID)
+		case "name":
+			key[i] = u.Name
+		case "dob":
+			key[i] = u.DOB.Format("2006-01-02")
+		case "created_at":
+			key[i] = u.CreatedAt.UTC().Format(keyTimeLayout)
+		case "updated_at":
+			key[i] = u.UpdatedAt.UTC().Format(keyTimeLayout)
+		}
+	}
+	return key
+}
+
+// keysetClause builds the condition selecting rows that sort strictly after
+// f.After. For terms (a, b, id) it expands to
+// a > ? OR (a = ? AND b > ?) OR (a = ? AND b = ? AND id > ?),
+// with the comparison flipped for descending terms.
+func (f UserFilter) keysetClause() (string, []interface{}, error) {
+	terms := f.orderTerms()
+	if len(f.After) != len(terms) {
+		return "", nil, fmt.Errorf("%w: got %d values, want %d", ErrInvalidKeyset, len(f.After), len(terms))
+	}
+
+	values := make([]interface{}, len(terms))
+	for i, t := range terms {
+		v, err := parseKeyValue(t.Column, f.After[i])
+		if err != nil {
+			return "", nil, fmt.Errorf("%w: %v", ErrInvalidKeyset, err)
+		}
+		values[i] = v
+	}
+
+	var ors []string
+	var args []interface{}
+	for i, t := range terms {
+		var ands []string
+		for j := 0; j < i; j++ {
+			ands = append(ands, terms[j].Column+" = ?")
+			args = append(args, values[j])
+		}
+		op := ">"
+		if t.Desc {
+			op = "<"
+		}
+		ands = append(ands, t.Column+" "+op+" ?")
+		args = append(args, values[i])
+		ors = append(ors, "("+strings.Join(ands, " AND ")+")")
+	}
+	return "(" + strings.Join(ors, " OR ") + ")", args, nil
+}
+
+// parseKeyValue converts a serialized keyset value back to its column type.
+func parseKeyValue(column, value string) (interface{}, error) {
+	switch column {
+	case "id":
+		return strconv.Atoi(value)
+	case "name":
+		return value, nil
+	case "dob":
+		return time.Parse("2006-01-02", value)
+	case "created_at", "updated_at":
+		return time.Parse(keyTimeLayout, value)
+	}
+	return nil, fmt.Errorf("unknown sort column %q", column)
+}
+
+// whereClause builds the WHERE clause for the filter along with its
+// positional arguments. It returns an empty string when nothing is filtered.
+func (f UserFilter) whereClause() (string, []interface{}) {
+	var conds []string
+	var args []interface{}
+
+	if !f.IncludeDeleted {
+		conds = append(conds, "deleted_at IS NULL")
+	}
+	if f.NameContains != "" {
+		conds = append(conds, "name LIKE ?")
+		args = append(args, "%"+escapeLike(f.NameContains)+"%")
+	}
+	if f.DOBFrom != nil {
+		conds = append(conds, "dob >= ?")
+		args = append(args, *f.DOBFrom)
+	}
+	if f.DOBTo != nil {
+		conds = append(conds, "dob <= ?")
+		args = append(args, *f.DOBTo)
+	}
+
+	if len(conds) == 0 {
+		return "", nil
+	}
+	return " WHERE " + strings.Join(conds, " AND "), args
+}
+
+// orderByClause builds the ORDER BY clause for the filter. The id column is
+// always appended as a tie-breaker so paging is deterministic.
+func (f UserFilter) orderByClause() string {
+	var terms []string
+	for _, s := range f.orderTerms() {
+		term := s.Column
+		if s.Desc {
+			term += " DESC"
+		}
+		terms = append(terms, term)
+	}
+	return " ORDER BY " + strings.Join(terms, ", ")
+}
+
+// escapeLike escapes the LIKE wildcards in s so it is matched literally.
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
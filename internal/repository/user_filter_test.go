@@ -0,0 +1,70 @@
+package repository
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Pallavi566/Go-Backend/internal/models"
+)
+
+func TestKeysetClause(t *testing.T) {
+	filter := UserFilter{Sort: []SortField{{Column: "dob", Desc: true}, {Column: "name"}}}
+	user := &models.User{ID: 7, Name: "Alice", DOB: time.Date(1990, 5, 10, 0, 0, 0, 0, time.UTC)}
+
+	filter.After = filter.KeyOf(user)
+	clause, args, err := filter.keysetClause()
+	if err != nil {
+		t.Fatalf("keysetClause() error = %v", err)
+	}
+
+	wantClause := "((dob < ?) OR (dob = ? AND name > ?) OR (dob = ? AND name = ? AND id > ?))"
+	if clause != wantClause {
+		t.Errorf("keysetClause() = %q, want %q", clause, wantClause)
+	}
+	if len(args) != 6 {
+		t.Fatalf("keysetClause() returned %d args, want 6", len(args))
+	}
+	if got := args[5]; got != 7 {
+		t.Errorf("last arg = %v, want 7", got)
+	}
+	if got, ok := args[0].(time.Time); !ok || !got.Equal(user.DOB) {
+		t.Errorf("first arg = %v, want %v", args[0], user.DOB)
+	}
+}
+
+func TestKeysetClauseInvalid(t *testing.T) {
+	tests := []struct {
+		name   string
+		filter UserFilter
+	}{
+		{"Wrong length", UserFilter{After: []string{"1", "2"}}},
+		{"Bad id", UserFilter{After: []string{"abc"}}},
+		{"Bad date", UserFilter{Sort: []SortField{{Column: "dob"}}, After: []string{"yesterday", "1"}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, _, err := tt.filter.keysetClause(); !errors.Is(err, ErrInvalidKeyset) {
+				t.Errorf("keysetClause() error = %v, want ErrInvalidKeyset", err)
+			}
+		})
+	}
+}
+
+func TestWhereClauseEscapesLike(t *testing.T) {
+	where, args := UserFilter{NameContains: "50%_off", IncludeDeleted: true}.whereClause()
+	if where != " WHERE name LIKE ?" {
+		t.Errorf("whereClause() = %q", where)
+	}
+	if len(args) != 1 || args[0] != `%50\%\_off%` {
+		t.Errorf("whereClause() args = %v", args)
+	}
+}
+
+func TestWhereClauseExcludesDeleted(t *testing.T) {
+	where, args := UserFilter{}.whereClause()
+	if where != " WHERE deleted_at IS NULL" || len(args) != 0 {
+		t.Errorf("whereClause() = %q, %v", where, args)
+	}
+}
@@ -0,0 +1,144 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/Pallavi566/Go-Backend/db/sqlc"
+	"github.com/Pallavi566/Go-Backend/internal/audit"
+	"github.com/Pallavi566/Go-Backend/internal/models"
+)
+
+// GetHistory returns the recorded changes of a user, oldest first. History
+// outlives the user, so entries are returned even after it was purged.
+func (r *UserRepository) GetHistory(ctx context.Context, id int) ([]models.UserHistoryEntry, error) {
+	rows, err := r.queries.ListUserHistory(ctx, int32(id))
+	if err != nil {
+		return nil, dbError(err)
+	}
+
+	entries := make([]models.UserHistoryEntry, 0, len(rows))
+	for _, row := range rows {
+		entry := models.UserHistoryEntry{
+			ID:        row.ID,
+			UserID:    int(row.UserID),
+			Action:    row.Action,
+			Version:   int(row.Version),
+			Actor:     row.Actor,
+			RequestID: row.RequestID,
+			ChangedAt: row.ChangedAt,
+		}
+		if entry.Before, err = decodeSnapshot(row.BeforeData); err != nil {
+			return nil, fmt.Errorf("history entry %d: %w", row.ID, err)
+		}
+		if entry.After, err = decodeSnapshot(row.AfterData); err != nil {
+			return nil, fmt.Errorf("history entry %d: %w", row.ID, err)
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+// GetAsOf returns the user as it was at the given instant, reconstructed from
+// its history. ErrUserNotFound is returned when the user did not exist or
+// was deleted at that instant.
+func (r *UserRepository) GetAsOf(ctx context.Context, id int, at time.Time) (*models.User, error) {
+	row, err := r.queries.GetUserHistoryAsOf(ctx, sqlc.GetUserHistoryAsOfParams{
+		UserID:    int32(id),
+		ChangedAt: at,
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("%w: id %d at %s", ErrUserNotFound, id, at.Format(time.RFC3339))
+		}
+		return nil, dbError(err)
+	}
+
+	snapshot, err := decodeSnapshot(row.AfterData)
+	if err != nil {
+		return nil, fmt.Errorf("history entry %d: %w", row.ID, err)
+	}
+	if snapshot == nil || snapshot.Deleted {
+		return nil, fmt.Errorf("%w: id %d at %s", ErrUserNotFound, id, at.Format(time.RFC3339))
+	}
+
+	dob, err := time.Parse("2006-01-02", snapshot.DOB)
+	if err != nil {
+		return nil, fmt.Errorf("history entry %d: %w", row.ID, err)
+	}
+	return &models.User{
+		ID:        snapshot.ID,
+		Name:      snapshot.Name,
+		DOB:       dob,
+		Email:     snapshot.Email,
+		Phone:     snapshot.Phone,
+		Timezone:  snapshot.Timezone,
+		Locale:    snapshot.Locale,
+		UpdatedAt: row.ChangedAt,
+		Version:   snapshot.Version,
+	}, nil
+}
+
+// recordHistory writes a history entry for a change from before to after,
+// either of which may be nil. It must run in the transaction making the
+// change. The actor and request ID are taken from ctx.
+func recordHistory(ctx context.Context, q *sqlc.Queries, action string, before, after *models.User) error {
+	subject := after
+	if subject == nil {
+		subject = before
+	}
+
+	beforeData, err := encodeSnapshot(before)
+	if err != nil {
+		return err
+	}
+	afterData, err := encodeSnapshot(after)
+	if err != nil {
+		return err
+	}
+
+	return dbError(q.CreateUserHistory(ctx, sqlc.CreateUserHistoryParams{
+		UserID:     int32(subject.ID),
+		Action:     action,
+		Version:    int32(subject.Version),
+		BeforeData: beforeData,
+		AfterData:  afterData,
+		Actor:      audit.Actor(ctx),
+		RequestID:  audit.RequestID(ctx),
+	}))
+}
+
+func snapshotOf(u *models.User) *models.UserSnapshot {
+	return &models.UserSnapshot{
+		ID:       u.ID,
+		Name:     u.Name,
+		DOB:      u.DOB.Format("2006-01-02"),
+		Email:    u.Email,
+		Phone:    u.Phone,
+		Timezone: u.Timezone,
+		Locale:   u.Locale,
+		Version:  u.Version,
+		Deleted:  u.DeletedAt != nil,
+	}
+}
+
+func encodeSnapshot(u *models.User) (json.RawMessage, error) {
+	if u == nil {
+		return nil, nil
+	}
+	return json.Marshal(snapshotOf(u))
+}
+
+func decodeSnapshot(data json.RawMessage) (*models.UserSnapshot, error) {
+	if len(data) == 0 || string(data) == "null" {
+		return nil, nil
+	}
+	var s models.UserSnapshot
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
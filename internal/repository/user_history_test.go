@@ -0,0 +1,82 @@
+package repository
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Pallavi566/Go-Backend/db/sqlc"
+	"github.com/Pallavi566/Go-Backend/internal/age"
+	"github.com/Pallavi566/Go-Backend/internal/models"
+)
+
+func TestSnapshotRoundTrip(t *testing.T) {
+	deletedAt := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	user := &models.User{
+		ID:        4,
+		Name:      "Alice",
+		DOB:       time.Date(1990, 5, 10, 0, 0, 0, 0, time.UTC),
+		Version:   3,
+		DeletedAt: &deletedAt,
+	}
+
+	data, err := encodeSnapshot(user)
+	if err != nil {
+		t.Fatalf("encodeSnapshot() error = %v", err)
+	}
+	got, err := decodeSnapshot(data)
+	if err != nil {
+		t.Fatalf("decodeSnapshot() error = %v", err)
+	}
+
+	want := models.UserSnapshot{ID: 4, Name: "Alice", DOB: "1990-05-10", Version: 3, Deleted: true}
+	if got == nil || *got != want {
+		t.Errorf("decodeSnapshot() = %+v, want %+v", got, want)
+	}
+}
+
+func TestSnapshotNil(t *testing.T) {
+	data, err := encodeSnapshot(nil)
+	if err != nil || data != nil {
+		t.Fatalf("encodeSnapshot(nil) = %s, %v", data, err)
+	}
+	for _, in := range []string{"", "null"} {
+		got, err := decodeSnapshot([]byte(in))
+		if err != nil || got != nil {
+			t.Errorf("decodeSnapshot(%q) = %+v, %v", in, got, err)
+		}
+	}
+}
+
+func TestQueryErrorsOfAnOutage(t *testing.T) {
+	q := sqlc.New(fakeDB{err: driver.ErrBadConn})
+	users := &UserRepository{queries: q}
+	keys := &IdempotencyRepository{queries: q}
+	ctx := context.Background()
+	today := time.Date(2024, 5, 10, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name string
+		run  func() error
+	}{
+		{"GetHistory", func() error { _, err := users.GetHistory(ctx, 1); return err }},
+		{"CountByAge", func() error { _, err := users.CountByAge(ctx, today, age.Feb28); return err }},
+		{"CountByBirthDecade", func() error { _, err := users.CountByBirthDecade(ctx); return err }},
+		{"CountSignups", func() error { _, err := users.CountSignups(ctx, "week", today); return err }},
+		{"RecordAgeCheck", func() error {
+			return users.RecordAgeCheck(ctx, &models.User{ID: 1, DOB: today}, models.AgeCheck{}, today, age.Feb28)
+		}},
+		{"Reserve", func() error { _, err := keys.Reserve(ctx, "scope", "key", "hash", today); return err }},
+		{"Release", func() error { return keys.Release(ctx, "scope", "key") }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.run(); !errors.Is(err, ErrDatabaseUnavailable) {
+				t.Errorf("error = %v, want ErrDatabaseUnavailable", err)
+			}
+		})
+	}
+}
@@ -3,37 +3,140 @@ package repository
 import (
 	"context"
 	"database/sql"
+	"database/sql/driver"
+	"errors"
 	"fmt"
+	"net"
+	"strings"
 	"time"
 
 	"github.com/Pallavi566/Go-Backend/db/sqlc"
+	"github.com/Pallavi566/Go-Backend/internal/apperr"
+	"github.com/Pallavi566/Go-Backend/internal/metrics"
 	"github.com/Pallavi566/Go-Backend/internal/models"
+	"github.com/go-sql-driver/mysql"
 )
 
+// MySQL error numbers.
+const (
+	// mysqlDuplicateEntry is the error of a unique key violation.
+	mysqlDuplicateEntry = 1062
+	// mysqlTooManyConnections and mysqlServerShutdown are the errors of a
+	// server that cannot take queries.
+	mysqlTooManyConnections = 1040
+	mysqlServerShutdown     = 1053
+)
+
+// ErrUserNotFound is returned when no matching user exists.
+var ErrUserNotFound = apperr.New(apperr.ErrNotFound, "user_not_found", "user not found")
+
+// ErrVersionConflict is returned when a conditional write finds the row at a
+// different version than the one it was read at.
+var ErrVersionConflict = apperr.New(apperr.ErrConflict, "version_conflict", "user version conflict")
+
+// ErrDuplicateEmail is returned when a write would give a user an email
+// address that another user already has.
+var ErrDuplicateEmail = apperr.New(apperr.ErrConflict, "duplicate_email", "email already in use")
+
+// ErrDatabaseUnavailable is returned when the database cannot be reached or
+// cannot take queries.
+var ErrDatabaseUnavailable = apperr.New(apperr.ErrUnavailable, "database_unavailable", "database unavailable")
+
+// userColumns are the columns eachUser scans, in order.
+const userColumns = "id, name, dob, email, phone, timezone, locale, created_at, updated_at, version, deleted_at"
+
 type UserRepository struct {
-	db      *sql.DB
-	queries *sqlc.Queries
+	db *sql.DB
+	// instrumented runs the queries built at run time, which must be
+	// named with named.
+	instrumented instrumentedDB
+	queries      *sqlc.Queries
 }
 
 func NewUserRepository(db *sql.DB) *UserRepository {
 	return &UserRepository{
-		db:      db,
-		queries: sqlc.New(db),
+		db:           db,
+		instrumented: instrumentedDB{db: db},
+		queries:      newQueries(db),
+	}
+}
+
+// UserTx applies changes to users within a single transaction. Each change
+// is recorded in the history and the outbox like its standalone counterpart
+// on UserRepository.
+type UserTx struct {
+	q *sqlc.Queries
+	// actions lists the changes made, counted in the metrics once they are
+	// committed.
+	actions []string
+}
+
+// InTx runs fn in a new transaction, committing when fn succeeds and rolling
+// back every change made through tx otherwise.
+func (r *UserRepository) InTx(ctx context.Context, fn func(tx *UserTx) error) error {
+	var tx *UserTx
+	err := r.withTx(ctx, func(q *sqlc.Queries) error {
+		tx = &UserTx{q: q}
+		return fn(tx)
+	})
+	if err != nil {
+		return err
+	}
+	for _, action := range tx.actions {
+		metrics.UserChanged(action)
+	}
+	return nil
+}
+
+// recordChange records a change in the history and the outbox, and notes it
+// to be counted once the transaction is committed.
+func (t *UserTx) recordChange(ctx context.Context, action string, before, after *models.User) error {
+	if err := recordChange(ctx, t.q, action, before, after); err != nil {
+		return err
 	}
+	t.actions = append(t.actions, action)
+	return nil
 }
 
-func (r *UserRepository) Create(ctx context.Context, name string, dob time.Time) (int64, error) {
-	result, err := r.queries.CreateUser(ctx, sqlc.CreateUserParams{
-		Name: name,
-		Dob:  dob,
+// Create inserts a user and records its creation in the history and the
+// outbox within the same transaction. The ID and version of u are ignored.
+func (r *UserRepository) Create(ctx context.Context, u models.User) (int64, error) {
+	var id int64
+	err := r.InTx(ctx, func(tx *UserTx) error {
+		var err error
+		id, err = tx.Create(ctx, u)
+		return err
 	})
 	if err != nil {
 		return 0, err
 	}
+	return id, nil
+}
+
+func (t *UserTx) Create(ctx context.Context, u models.User) (int64, error) {
+	result, err := t.q.CreateUser(ctx, sqlc.CreateUserParams{
+		Name:     u.Name,
+		Dob:      u.DOB,
+		Email:    nullString(u.Email),
+		Phone:    nullString(u.Phone),
+		Timezone: nullString(u.Timezone),
+		Locale:   nullString(u.Locale),
+	})
+	if err != nil {
+		return 0, writeError(err)
+	}
 	id, err := result.LastInsertId()
 	if err != nil {
 		return 0, err
 	}
+
+	created := u
+	created.ID = int(id)
+	created.Version = 1
+	created.DeletedAt = nil
+	if err := t.recordChange(ctx, models.HistoryCreated, nil, &created); err != nil {
+		return 0, err
+	}
 	return id, nil
 }
 
@@ -41,102 +144,433 @@ func (r *UserRepository) GetByID(ctx context.Context, id int) (*models.User, err
 	// First try with the generated query
 	user, err := r.queries.GetUserByID(ctx, int32(id))
 	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("%w: id %d", ErrUserNotFound, id)
+		}
+		if err := dbError(err); errors.Is(err, ErrDatabaseUnavailable) {
+			return nil, err
+		}
 		// If that fails, try with a direct query for better error messages
 		var dbUser struct {
-			ID        int32     `db:"id"`
-			Name      string    `db:"name"`
-			Dob       time.Time `db:"dob"`
-			CreatedAt time.Time `db:"created_at"`
-			UpdatedAt time.Time `db:"updated_at"`
-		}
-		err = r.db.QueryRowContext(ctx, 
-			"SELECT id, name, dob, created_at, updated_at FROM users WHERE id = ?", id).
-			Scan(&dbUser.ID, &dbUser.Name, &dbUser.Dob, &dbUser.CreatedAt, &dbUser.UpdatedAt)
+			ID        int32          `db:"id"`
+			Name      string         `db:"name"`
+			Dob       time.Time      `db:"dob"`
+			Email     sql.NullString `db:"email"`
+			Phone     sql.NullString `db:"phone"`
+			Timezone  sql.NullString `db:"timezone"`
+			Locale    sql.NullString `db:"locale"`
+			CreatedAt time.Time      `db:"created_at"`
+			UpdatedAt time.Time      `db:"updated_at"`
+			Version   int32          `db:"version"`
+		}
+		err = r.instrumented.QueryRowContext(ctx, named("GetUserByIDFallback",
+			"SELECT id, name, dob, email, phone, timezone, locale, created_at, updated_at, version FROM users WHERE id = ? AND deleted_at IS NULL"), id).
+			Scan(&dbUser.ID, &dbUser.Name, &dbUser.Dob, &dbUser.Email, &dbUser.Phone, &dbUser.Timezone, &dbUser.Locale, &dbUser.CreatedAt, &dbUser.UpdatedAt, &dbUser.Version)
 		
 		if err != nil {
 			if err == sql.ErrNoRows {
-				return nil, fmt.Errorf("user with ID %d not found", id)
+				return nil, fmt.Errorf("%w: id %d", ErrUserNotFound, id)
 			}
-			return nil, fmt.Errorf("error fetching user: %v", err)
+			return nil, fmt.Errorf("error fetching user: %w", dbError(err))
 		}
 		
 		return &models.User{
-			ID:   int(dbUser.ID),
-			Name: dbUser.Name,
-			DOB:  dbUser.Dob,
+			ID:        int(dbUser.ID),
+			Name:      dbUser.Name,
+			DOB:       dbUser.Dob,
+			Email:     dbUser.Email.String,
+			Phone:     dbUser.Phone.String,
+			Timezone:  dbUser.Timezone.String,
+			Locale:    dbUser.Locale.String,
+			CreatedAt: dbUser.CreatedAt,
+			UpdatedAt: dbUser.UpdatedAt,
+			Version:   int(dbUser.Version),
 		}, nil
 	}
 	
 	return &models.User{
-		ID:   int(user.ID),
-		Name: user.Name,
-		DOB:  user.Dob,
+		ID:       int(user.ID),
+		Name:     user.Name,
+		DOB:      user.Dob,
+		Email:    user.Email.String,
+		Phone:    user.Phone.String,
+		Timezone: user.Timezone.String,
+		Locale:   user.Locale.String,
+		Version:  int(user.Version),
 	}, nil
 }
 
+// GetByEmail returns the active user with the given email address, which
+// must be normalized the way it is stored.
+func (r *UserRepository) GetByEmail(ctx context.Context, email string) (*models.User, error) {
+	row, err := r.queries.GetUserByEmail(ctx, nullString(email))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("%w: email %q", ErrUserNotFound, email)
+		}
+		return nil, dbError(err)
+	}
+	return &models.User{
+		ID:       int(row.ID),
+		Name:     row.Name,
+		DOB:      row.Dob,
+		Email:    row.Email.String,
+		Phone:    row.Phone.String,
+		Timezone: row.Timezone.String,
+		Locale:   row.Locale.String,
+		Version:  int(row.Version),
+	}, nil
+}
+
+// ExistingEmails returns which of emails, normalized the way they are
+// stored, already belong to a user, soft-deleted ones included.
+func (r *UserRepository) ExistingEmails(ctx context.Context, emails []string) (map[string]bool, error) {
+	existing := make(map[string]bool)
+	if len(emails) == 0 {
+		return existing, nil
+	}
+	args := make([]interface{}, len(emails))
+	for i, email := range emails {
+		args[i] = email
+	}
+	query := "SELECT email FROM users WHERE email IN (?" + strings.Repeat(", ?", len(emails)-1) + ")"
+	rows, err := r.instrumented.QueryContext(ctx, named("ListExistingEmails", query), args...)
+	if err != nil {
+		return nil, dbError(err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var email string
+		if err := rows.Scan(&email); err != nil {
+			return nil, err
+		}
+		existing[email] = true
+	}
+	return existing, dbError(rows.Err())
+}
+
 func (r *UserRepository) GetAll(ctx context.Context) ([]*models.User, error) {
 	users, err := r.queries.GetAllUsers(ctx)
 	if err != nil {
-		return nil, err
+		return nil, dbError(err)
 	}
 
 	result := make([]*models.User, len(users))
 	for i, u := range users {
 		result[i] = &models.User{
-			ID:   int(u.ID),
-			Name: u.Name,
-			DOB:  u.Dob,
+			ID:       int(u.ID),
+			Name:     u.Name,
+			DOB:      u.Dob,
+			Email:    u.Email.String,
+			Phone:    u.Phone.String,
+			Timezone: u.Timezone.String,
+			Locale:   u.Locale.String,
+			Version:  int(u.Version),
 		}
 	}
 	return result, nil
 }
 
-func (r *UserRepository) Update(ctx context.Context, id int, name string, dob time.Time) error {
-	// First, verify the user exists
-	_, err := r.queries.GetUserByID(ctx, int32(id))
-	if err != nil {
+// Update writes the name, dob and profile of u only if the user is still at
+// the given version, bumping the version on success. ErrVersionConflict is
+// returned when the user has been changed since that version was read.
+func (r *UserRepository) Update(ctx context.Context, u models.User, version int) error {
+	_, err := r.UpdateInTx(ctx, u.ID, func(current *models.User) error {
+		if current.Version != version {
+			return ErrVersionConflict
+		}
+		current.Name = u.Name
+		current.DOB = u.DOB
+		current.Email = u.Email
+		current.Phone = u.Phone
+		current.Timezone = u.Timezone
+		current.Locale = u.Locale
+		return nil
+	})
+	return err
+}
+
+// UpdateInTx loads the user under a row lock, lets fn modify it and writes
+// the result back, together with a history entry and an outbox event, within
+// the same transaction. Nothing is written if fn returns an error. The
+// returned user carries the new version.
+func (r *UserRepository) UpdateInTx(ctx context.Context, id int, fn func(u *models.User) error) (*models.User, error) {
+	var updated *models.User
+	err := r.InTx(ctx, func(tx *UserTx) error {
+		var err error
+		updated, err = tx.Update(ctx, id, fn)
 		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return updated, nil
+}
+
+func (t *UserTx) Update(ctx context.Context, id int, fn func(u *models.User) error) (*models.User, error) {
+	before, err := lockUser(ctx, t.q, id)
+	if err != nil {
+		return nil, err
+	}
+
+	user := *before
+	if err := fn(&user); err != nil {
+		return nil, err
+	}
+
+	if _, err := t.q.UpdateUser(ctx, sqlc.UpdateUserParams{
+		Name:     user.Name,
+		Dob:      user.DOB,
+		Email:    nullString(user.Email),
+		Phone:    nullString(user.Phone),
+		Timezone: nullString(user.Timezone),
+		Locale:   nullString(user.Locale),
+		ID:       int32(before.ID),
+		Version:  int32(before.Version),
+	}); err != nil {
+		return nil, writeError(err)
 	}
+	user.ID = before.ID
+	user.Version = before.Version + 1
+
+	if err := t.recordChange(ctx, models.HistoryUpdated, before, &user); err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
 
-	// Now perform the update
-	_, err = r.db.ExecContext(ctx, "UPDATE users SET name = ?, dob = ?, updated_at = NOW() WHERE id = ?",
-		name, dob, id)
+// Delete soft-deletes the user by setting deleted_at and records the change
+// in the history. When version is non-zero the user is only deleted if it is
+// still at that version, otherwise ErrVersionConflict is returned.
+func (r *UserRepository) Delete(ctx context.Context, id int, version int) error {
+	return r.InTx(ctx, func(tx *UserTx) error {
+		return tx.Delete(ctx, id, version)
+	})
+}
+
+func (t *UserTx) Delete(ctx context.Context, id int, version int) error {
+	before, err := lockUser(ctx, t.q, id)
 	if err != nil {
 		return err
 	}
+	if version != 0 && before.Version != version {
+		return ErrVersionConflict
+	}
+
+	if _, err := t.q.DeleteUser(ctx, int32(id)); err != nil {
+		return err
+	}
+
+	deletedAt := time.Now()
+	after := *before
+	after.Version++
+	after.DeletedAt = &deletedAt
+	return t.recordChange(ctx, models.HistoryDeleted, before, &after)
+}
+
+// Restore clears deleted_at on a soft-deleted user and records the change in
+// the history. ErrUserNotFound is returned when there is no deleted user
+// with that ID.
+func (r *UserRepository) Restore(ctx context.Context, id int) error {
+	err := r.withTx(ctx, func(q *sqlc.Queries) error {
+		row, err := q.GetDeletedUserByIDForUpdate(ctx, int32(id))
+		if err != nil {
+			if err == sql.ErrNoRows {
+				return fmt.Errorf("%w: no deleted user with id %d", ErrUserNotFound, id)
+			}
+			return err
+		}
 
+		if _, err := q.RestoreUser(ctx, int32(id)); err != nil {
+			return err
+		}
+
+		before := &models.User{
+			ID:        int(row.ID),
+			Name:      row.Name,
+			DOB:       row.Dob,
+			Email:     row.Email.String,
+			Phone:     row.Phone.String,
+			Timezone:  row.Timezone.String,
+			Locale:    row.Locale.String,
+			Version:   int(row.Version),
+			DeletedAt: &row.DeletedAt.Time,
+		}
+		after := *before
+		after.Version++
+		after.DeletedAt = nil
+		return recordChange(ctx, q, models.HistoryRestored, before, &after)
+	})
+	if err != nil {
+		return err
+	}
+	metrics.UserChanged(models.HistoryRestored)
 	return nil
 }
 
-func (r *UserRepository) Delete(ctx context.Context, id int) error {
-	return r.queries.DeleteUser(ctx, int32(id))
+// Purge hard-deletes users that were soft-deleted before the cutoff and
+// returns how many rows were removed. Their history is kept.
+func (r *UserRepository) Purge(ctx context.Context, cutoff time.Time) (int64, error) {
+	return r.queries.PurgeDeletedUsers(ctx, sql.NullTime{Time: cutoff, Valid: true})
 }
 
-func (r *UserRepository) GetPaginated(ctx context.Context, limit, offset int) ([]*models.User, error) {
-	users, err := r.queries.GetUsersPaginated(ctx, sqlc.GetUsersPaginatedParams{
-		Limit:  int32(limit),
-		Offset: int32(offset),
-	})
+// lockUser reads an active user with a row lock held until the transaction
+// ends.
+func lockUser(ctx context.Context, q *sqlc.Queries, id int) (*models.User, error) {
+	row, err := q.GetUserByIDForUpdate(ctx, int32(id))
 	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("%w: id %d", ErrUserNotFound, id)
+		}
 		return nil, err
 	}
+	return &models.User{
+		ID:       int(row.ID),
+		Name:     row.Name,
+		DOB:      row.Dob,
+		Email:    row.Email.String,
+		Phone:    row.Phone.String,
+		Timezone: row.Timezone.String,
+		Locale:   row.Locale.String,
+		Version:  int(row.Version),
+	}, nil
+}
 
-	result := make([]*models.User, len(users))
-	for i, u := range users {
-		result[i] = &models.User{
-			ID:   int(u.ID),
-			Name: u.Name,
-			DOB:  u.Dob,
+func (r *UserRepository) GetPaginated(ctx context.Context, filter UserFilter, limit, offset int) ([]*models.User, error) {
+	where, args := filter.whereClause()
+	query := "SELECT " + userColumns + " FROM users" + where + filter.orderByClause() + " LIMIT ? OFFSET ?"
+	args = append(args, limit, offset)
+	return r.queryUsers(ctx, named("ListUsersFiltered", query), args...)
+}
+
+// GetAfter returns up to limit users that sort strictly after the keyset
+// position stored in filter.After, or the first users when it is empty.
+func (r *UserRepository) GetAfter(ctx context.Context, filter UserFilter, limit int) ([]*models.User, error) {
+	where, args := filter.whereClause()
+	if len(filter.After) > 0 {
+		keyset, keysetArgs, err := filter.keysetClause()
+		if err != nil {
+			return nil, err
 		}
+		if where == "" {
+			where = " WHERE " + keyset
+		} else {
+			where += " AND " + keyset
+		}
+		args = append(args, keysetArgs...)
+	}
+	query := "SELECT " + userColumns + " FROM users" + where + filter.orderByClause() + " LIMIT ?"
+	args = append(args, limit)
+	return r.queryUsers(ctx, named("ListUsersAfter", query), args...)
+}
+
+// Stream calls fn for every user matching filter, in the filter's sort
+// order, as rows arrive from the database, so memory use does not grow with
+// the number of users. It stops at the first error fn returns.
+func (r *UserRepository) Stream(ctx context.Context, filter UserFilter, fn func(u *models.User) error) error {
+	where, args := filter.whereClause()
+	query := "SELECT " + userColumns + " FROM users" + where + filter.orderByClause()
+	return r.eachUser(ctx, named("StreamUsers", query), args, fn)
+}
+
+func (r *UserRepository) queryUsers(ctx context.Context, query string, args ...interface{}) ([]*models.User, error) {
+	var result []*models.User
+	err := r.eachUser(ctx, query, args, func(u *models.User) error {
+		result = append(result, u)
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
 	return result, nil
 }
 
-func (r *UserRepository) Count(ctx context.Context) (int64, error) {
-	count, err := r.queries.CountUsers(ctx)
+// eachUser runs a named query selecting the columns of a user and calls fn
+// for each row.
+func (r *UserRepository) eachUser(ctx context.Context, query string, args []interface{}, fn func(u *models.User) error) error {
+	rows, err := r.instrumented.QueryContext(ctx, query, args...)
 	if err != nil {
-		return 0, err
+		return dbError(err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var u models.User
+		var email, phone, timezone, locale sql.NullString
+		var createdAt, updatedAt, deletedAt sql.NullTime
+		if err := rows.Scan(&u.ID, &u.Name, &u.DOB, &email, &phone, &timezone, &locale, &createdAt, &updatedAt, &u.Version, &deletedAt); err != nil {
+			return err
+		}
+		u.Email = email.String
+		u.Phone = phone.String
+		u.Timezone = timezone.String
+		u.Locale = locale.String
+		u.CreatedAt = createdAt.Time
+		u.UpdatedAt = updatedAt.Time
+		if deletedAt.Valid {
+			u.DeletedAt = &deletedAt.Time
+		}
+		if err := fn(&u); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+func (r *UserRepository) Count(ctx context.Context, filter UserFilter) (int64, error) {
+	where, args := filter.whereClause()
+	var count int64
+	if err := r.instrumented.QueryRowContext(ctx, named("CountUsersFiltered", "SELECT COUNT(*) FROM users"+where), args...).Scan(&count); err != nil {
+		return 0, dbError(err)
 	}
 	return count, nil
 }
 
+// withTx runs fn against queries bound to a new transaction, committing when
+// fn succeeds and rolling back otherwise.
+func (r *UserRepository) withTx(ctx context.Context, fn func(q *sqlc.Queries) error) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return dbError(err)
+	}
+	if err := fn(newQueries(tx)); err != nil {
+		_ = tx.Rollback()
+		return dbError(err)
+	}
+	return dbError(tx.Commit())
+}
+
+// nullString stores optional text columns as NULL when empty, so that the
+// unique index on email only applies to users that have one.
+func nullString(s string) sql.NullString {
+	return sql.NullString{String: s, Valid: s != ""}
+}
+
+// writeError translates the MySQL errors a user write can fail with.
+func writeError(err error) error {
+	var mysqlErr *mysql.MySQLError
+	if errors.As(err, &mysqlErr) && mysqlErr.Number == mysqlDuplicateEntry && strings.Contains(mysqlErr.Message, "uq_users_email") {
+		return ErrDuplicateEmail
+	}
+	return err
+}
+
+// dbError marks the errors of a database that cannot be reached or cannot
+// take queries as ErrDatabaseUnavailable, so that they are told apart from
+// failed queries. Other errors, including nil, are returned as they are.
+func dbError(err error) error {
+	var netErr net.Error
+	var mysqlErr *mysql.MySQLError
+	switch {
+	case err == nil, errors.Is(err, ErrDatabaseUnavailable):
+		return err
+	case errors.Is(err, driver.ErrBadConn),
+		errors.Is(err, mysql.ErrInvalidConn),
+		errors.Is(err, sql.ErrConnDone),
+		errors.Is(err, context.DeadlineExceeded),
+		errors.As(err, &netErr),
+		errors.As(err, &mysqlErr) && (mysqlErr.Number == mysqlTooManyConnections || mysqlErr.Number == mysqlServerShutdown):
+		return fmt.Errorf("%w: %w", ErrDatabaseUnavailable, err)
+	}
+	return err
+}
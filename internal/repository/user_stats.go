@@ -0,0 +1,99 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Pallavi566/Go-Backend/db/sqlc"
+	"github.com/Pallavi566/Go-Backend/internal/age"
+	"github.com/Pallavi566/Go-Backend/internal/models"
+)
+
+// AgeCount is the number of active users of an age.
+type AgeCount struct {
+	Age   int
+	Count int64
+}
+
+// PeriodCount is the number of users created in the period starting on
+// Start.
+type PeriodCount struct {
+	Start time.Time
+	Count int64
+}
+
+// CountByAge returns the number of active users of each age on the date
+// today, youngest first, computed in the database so that no user is
+// loaded. Ages agree with age.Years under the given leap day rule.
+func (r *UserRepository) CountByAge(ctx context.Context, today time.Time, rule age.LeapDayRule) ([]AgeCount, error) {
+	// Where people born on February 29 have their birthday this year.
+	leapBirthday := age.Birthday(time.Date(2000, time.February, 29, 0, 0, 0, 0, time.UTC), today.Year(), rule)
+	rows, err := r.queries.CountActiveUsersByAge(ctx, sqlc.CountActiveUsersByAgeParams{
+		Year:         today.Year(),
+		LeapBirthday: int(leapBirthday.Month())*100 + leapBirthday.Day(),
+		Today:        int(today.Month())*100 + today.Day(),
+	})
+	if err != nil {
+		return nil, dbError(err)
+	}
+	counts := make([]AgeCount, len(rows))
+	for i, row := range rows {
+		counts[i] = AgeCount{Age: int(row.Age), Count: row.Count}
+	}
+	return counts, nil
+}
+
+// CountByBirthDecade returns the number of active users born in each
+// decade, oldest first.
+func (r *UserRepository) CountByBirthDecade(ctx context.Context) ([]models.CohortCount, error) {
+	rows, err := r.queries.CountActiveUsersByBirthDecade(ctx)
+	if err != nil {
+		return nil, dbError(err)
+	}
+	counts := make([]models.CohortCount, len(rows))
+	for i, row := range rows {
+		counts[i] = models.CohortCount{Decade: int(row.Decade), Count: row.Count}
+	}
+	return counts, nil
+}
+
+// CountSignups returns the number of users created in each day, week or
+// month since the given time, oldest first. Periods without signups are
+// left out.
+func (r *UserRepository) CountSignups(ctx context.Context, interval string, since time.Time) ([]PeriodCount, error) {
+	var counts []PeriodCount
+	add := func(start time.Time, count int64) {
+		counts = append(counts, PeriodCount{Start: start, Count: count})
+	}
+
+	switch interval {
+	case "day":
+		rows, err := r.queries.CountSignupsByDay(ctx, since)
+		if err != nil {
+			return nil, dbError(err)
+		}
+		for _, row := range rows {
+			add(row.Period, row.Count)
+		}
+	case "week":
+		rows, err := r.queries.CountSignupsByWeek(ctx, since)
+		if err != nil {
+			return nil, dbError(err)
+		}
+		for _, row := range rows {
+			add(row.Period, row.Count)
+		}
+	case "month":
+		rows, err := r.queries.CountSignupsByMonth(ctx, since)
+		if err != nil {
+			return nil, dbError(err)
+		}
+		for _, row := range rows {
+			add(row.Period, row.Count)
+		}
+	default:
+		return nil, fmt.Errorf("unknown signup interval %q", interval)
+	}
+	return counts, nil
+}
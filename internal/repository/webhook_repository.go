@@ -0,0 +1,328 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Pallavi566/Go-Backend/db/sqlc"
+	"github.com/Pallavi566/Go-Backend/internal/apperr"
+	"github.com/Pallavi566/Go-Backend/internal/models"
+)
+
+// ErrWebhookNotFound is returned when a webhook subscription does not exist.
+var ErrWebhookNotFound = apperr.New(apperr.ErrNotFound, "webhook_not_found", "webhook subscription not found")
+
+type WebhookRepository struct {
+	db      *sql.DB
+	queries *sqlc.Queries
+}
+
+func NewWebhookRepository(db *sql.DB) *WebhookRepository {
+	return &WebhookRepository{
+		db:      db,
+		queries: newQueries(db),
+	}
+}
+
+// DueDelivery is a pending delivery whose next attempt is due, together with
+// the event and the subscription it is for.
+type DueDelivery struct {
+	ID             int64
+	EventID        int64
+	SubscriptionID int
+	Attempts       int
+	NextAttemptAt  time.Time
+	EventType      string
+	Payload        json.RawMessage
+	EventCreatedAt time.Time
+	URL            string
+	Secret         string
+}
+
+func (r *WebhookRepository) CreateSubscription(ctx context.Context, url, secret string, eventTypes []string) (*models.WebhookSubscription, error) {
+	result, err := r.queries.CreateWebhookSubscription(ctx, sqlc.CreateWebhookSubscriptionParams{
+		Url:        url,
+		Secret:     secret,
+		EventTypes: strings.Join(eventTypes, ","),
+	})
+	if err != nil {
+		return nil, err
+	}
+	id, err := result.LastInsertId()
+	if err != nil {
+		return nil, err
+	}
+	return r.GetSubscription(ctx, int(id))
+}
+
+func (r *WebhookRepository) GetSubscription(ctx context.Context, id int) (*models.WebhookSubscription, error) {
+	row, err := r.queries.GetWebhookSubscription(ctx, int32(id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("%w: id %d", ErrWebhookNotFound, id)
+		}
+		return nil, dbError(err)
+	}
+	return newSubscription(row), nil
+}
+
+func (r *WebhookRepository) ListSubscriptions(ctx context.Context) ([]*models.WebhookSubscription, error) {
+	rows, err := r.queries.ListWebhookSubscriptions(ctx)
+	if err != nil {
+		return nil, err
+	}
+	subscriptions := make([]*models.WebhookSubscription, 0, len(rows))
+	for _, row := range rows {
+		subscriptions = append(subscriptions, newSubscription(row))
+	}
+	return subscriptions, nil
+}
+
+// SetSubscriptionActive enables or disables a subscription. Events are not
+// fanned out to disabled subscriptions and their pending deliveries are held
+// until the subscription is enabled again.
+func (r *WebhookRepository) SetSubscriptionActive(ctx context.Context, id int, active bool) error {
+	// Affected rows are not reliable for a no-op update, so check existence
+	// explicitly.
+	if _, err := r.GetSubscription(ctx, id); err != nil {
+		return err
+	}
+	_, err := r.queries.SetWebhookSubscriptionActive(ctx, sqlc.SetWebhookSubscriptionActiveParams{
+		Active: active,
+		ID:     int32(id),
+	})
+	return err
+}
+
+// ListDeliveries returns the most recent deliveries of a subscription,
+// newest first.
+func (r *WebhookRepository) ListDeliveries(ctx context.Context, subscriptionID, limit int) ([]*models.WebhookDelivery, error) {
+	rows, err := r.queries.ListWebhookDeliveries(ctx, sqlc.ListWebhookDeliveriesParams{
+		SubscriptionID: int32(subscriptionID),
+		Limit:          int32(limit),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	deliveries := make([]*models.WebhookDelivery, 0, len(rows))
+	for _, row := range rows {
+		d := &models.WebhookDelivery{
+			ID:             row.ID,
+			EventID:        row.EventID,
+			SubscriptionID: int(row.SubscriptionID),
+			Status:         row.Status,
+			Attempts:       int(row.Attempts),
+			NextAttemptAt:  row.NextAttemptAt,
+			CreatedAt:      row.CreatedAt,
+		}
+		if row.LastStatusCode.Valid {
+			code := int(row.LastStatusCode.Int32)
+			d.LastStatusCode = &code
+		}
+		if row.LastError.Valid {
+			d.LastError = &row.LastError.String
+		}
+		if row.DeliveredAt.Valid {
+			d.DeliveredAt = &row.DeliveredAt.Time
+		}
+		deliveries = append(deliveries, d)
+	}
+	return deliveries, nil
+}
+
+// Replay resets deliveries of a subscription so they are attempted again
+// with a fresh retry budget. With a nil since only dead deliveries are
+// replayed; otherwise every delivery created at or after since is.
+func (r *WebhookRepository) Replay(ctx context.Context, subscriptionID int, since *time.Time) (int64, error) {
+	if _, err := r.GetSubscription(ctx, subscriptionID); err != nil {
+		return 0, err
+	}
+	if since == nil {
+		return r.queries.ReplayDeadWebhookDeliveries(ctx, int32(subscriptionID))
+	}
+	return r.queries.ReplayWebhookDeliveriesSince(ctx, sqlc.ReplayWebhookDeliveriesSinceParams{
+		SubscriptionID: int32(subscriptionID),
+		CreatedAt:      *since,
+	})
+}
+
+// FanOut creates a delivery for each undispatched outbox event and each
+// active subscription interested in it, and marks the events dispatched.
+// Each event is handled in its own transaction; deliveries are unique per
+// event and subscription, so an event is never delivered twice to the same
+// subscription even if it is fanned out again after a crash.
+func (r *WebhookRepository) FanOut(ctx context.Context, limit int) (int, error) {
+	events, err := r.queries.ListUndispatchedOutboxEvents(ctx, int32(limit))
+	if err != nil || len(events) == 0 {
+		return 0, err
+	}
+	subscriptions, err := r.queries.ListActiveWebhookSubscriptions(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	for i, event := range events {
+		err := r.withTx(ctx, func(q *sqlc.Queries) error {
+			for _, sub := range subscriptions {
+				if !subscribesTo(sub.EventTypes, event.EventType) {
+					continue
+				}
+				if err := q.CreateWebhookDelivery(ctx, sqlc.CreateWebhookDeliveryParams{
+					EventID:        event.ID,
+					SubscriptionID: sub.ID,
+				}); err != nil {
+					return err
+				}
+			}
+			return q.MarkOutboxEventDispatched(ctx, event.ID)
+		})
+		if err != nil {
+			return i, fmt.Errorf("fan out event %d: %w", event.ID, err)
+		}
+	}
+	return len(events), nil
+}
+
+// DueDeliveries returns up to limit pending deliveries due at now, for
+// active subscriptions only.
+func (r *WebhookRepository) DueDeliveries(ctx context.Context, now time.Time, limit int) ([]DueDelivery, error) {
+	rows, err := r.queries.ListDueWebhookDeliveries(ctx, sqlc.ListDueWebhookDeliveriesParams{
+		NextAttemptAt: now,
+		Limit:         int32(limit),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	deliveries := make([]DueDelivery, 0, len(rows))
+	for _, row := range rows {
+		deliveries = append(deliveries, DueDelivery{
+			ID:             row.ID,
+			EventID:        row.EventID,
+			SubscriptionID: int(row.SubscriptionID),
+			Attempts:       int(row.Attempts),
+			NextAttemptAt:  row.NextAttemptAt,
+			EventType:      row.EventType,
+			Payload:        row.Payload,
+			EventCreatedAt: row.EventCreatedAt,
+			URL:            row.Url,
+			Secret:         row.Secret,
+		})
+	}
+	return deliveries, nil
+}
+
+// Claim leases a due delivery until leaseUntil by moving its next attempt
+// forward. It reports false when another dispatcher claimed it first. If the
+// claimant dies mid-attempt, the delivery becomes due again once the lease
+// expires.
+func (r *WebhookRepository) Claim(ctx context.Context, d DueDelivery, leaseUntil time.Time) (bool, error) {
+	n, err := r.queries.ClaimWebhookDelivery(ctx, sqlc.ClaimWebhookDeliveryParams{
+		NextAttemptAt:   leaseUntil,
+		ID:              d.ID,
+		NextAttemptAt_2: d.NextAttemptAt,
+	})
+	if err != nil {
+		return false, err
+	}
+	return n == 1, nil
+}
+
+func (r *WebhookRepository) MarkDelivered(ctx context.Context, id int64, statusCode int) error {
+	return r.queries.MarkWebhookDeliveryDelivered(ctx, sqlc.MarkWebhookDeliveryDeliveredParams{
+		LastStatusCode: sql.NullInt32{Int32: int32(statusCode), Valid: true},
+		ID:             id,
+	})
+}
+
+// MarkFailed records a failed attempt. The delivery stays pending until
+// nextAttemptAt, or is dead-lettered when dead is set. A zero statusCode
+// means no response was received.
+func (r *WebhookRepository) MarkFailed(ctx context.Context, id int64, statusCode int, reason string, nextAttemptAt time.Time, dead bool) error {
+	status := models.DeliveryPending
+	if dead {
+		status = models.DeliveryDead
+	}
+	return r.queries.MarkWebhookDeliveryFailed(ctx, sqlc.MarkWebhookDeliveryFailedParams{
+		Status:         status,
+		LastStatusCode: sql.NullInt32{Int32: int32(statusCode), Valid: statusCode != 0},
+		LastError:      sql.NullString{String: reason, Valid: reason != ""},
+		NextAttemptAt:  nextAttemptAt,
+		ID:             id,
+	})
+}
+
+// PurgeDispatched deletes outbox events dispatched before before, in
+// batches, along with their deliveries, and returns how many events were
+// deleted. Events with a delivery still pending are kept until it is
+// delivered or dead.
+func (r *WebhookRepository) PurgeDispatched(ctx context.Context, before time.Time) (int64, error) {
+	const batchSize = 1000
+	var total int64
+	for {
+		n, err := r.queries.PurgeDispatchedOutboxEvents(ctx, sqlc.PurgeDispatchedOutboxEventsParams{
+			DispatchedAt: sql.NullTime{Time: before, Valid: true},
+			Limit:        batchSize,
+		})
+		total += n
+		if err != nil || n < batchSize {
+			return total, err
+		}
+	}
+}
+
+// withTx runs fn against queries bound to a new transaction, committing when
+// fn succeeds and rolling back otherwise.
+func (r *WebhookRepository) withTx(ctx context.Context, fn func(q *sqlc.Queries) error) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	if err := fn(newQueries(tx)); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+func newSubscription(row sqlc.WebhookSubscription) *models.WebhookSubscription {
+	return &models.WebhookSubscription{
+		ID:         int(row.ID),
+		URL:        row.Url,
+		EventTypes: splitEventTypes(row.EventTypes),
+		Active:     row.Active,
+		CreatedAt:  row.CreatedAt.Time,
+		UpdatedAt:  row.UpdatedAt.Time,
+	}
+}
+
+// splitEventTypes parses the comma separated event types of a subscription.
+func splitEventTypes(s string) []string {
+	types := []string{}
+	for _, t := range strings.Split(s, ",") {
+		if t = strings.TrimSpace(t); t != "" {
+			types = append(types, t)
+		}
+	}
+	return types
+}
+
+// subscribesTo reports whether a subscription to the comma separated
+// eventTypes receives events of type t. No event types means all of them.
+func subscribesTo(eventTypes, t string) bool {
+	types := splitEventTypes(eventTypes)
+	if len(types) == 0 {
+		return true
+	}
+	for _, want := range types {
+		if want == t {
+			return true
+		}
+	}
+	return false
+}
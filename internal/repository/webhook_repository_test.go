@@ -0,0 +1,23 @@
+package repository
+
+import "testing"
+
+func TestSubscribesTo(t *testing.T) {
+	tests := []struct {
+		eventTypes string
+		event      string
+		want       bool
+	}{
+		{"", "user.created", true},
+		{"user.created", "user.created", true},
+		{"user.created,user.deleted", "user.deleted", true},
+		{"user.created, user.deleted", "user.deleted", true},
+		{"user.created", "user.updated", false},
+	}
+
+	for _, tt := range tests {
+		if got := subscribesTo(tt.eventTypes, tt.event); got != tt.want {
+			t.Errorf("subscribesTo(%q, %q) = %v, want %v", tt.eventTypes, tt.event, got, tt.want)
+		}
+	}
+}
@@ -2,20 +2,29 @@ package routes
 
 import (
 	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/adaptor"
 	"github.com/Pallavi566/Go-Backend/internal/handler"
+	"github.com/Pallavi566/Go-Backend/internal/metrics"
 	"github.com/Pallavi566/Go-Backend/internal/middleware"
 	"go.uber.org/zap"
 )
 
-func SetupRoutes(app *fiber.App, userHandler *handler.UserHandler, logger *zap.Logger) {
+func SetupRoutes(app *fiber.App, userHandler *handler.UserHandler, eventHandler *handler.EventHandler, webhookHandler *handler.WebhookHandler, exportHandler *handler.ExportHandler, statsHandler *handler.StatsHandler, healthHandler *handler.HealthHandler, idempotency fiber.Handler, logger *zap.Logger) {
+	// Probes are answered ahead of the global middleware, so that they are
+	// not logged, traced or counted
+	app.Get("/healthz/live", healthHandler.Live)
+	app.Get("/healthz/ready", healthHandler.Ready)
+
 	// Apply global middleware
 	app.Use(middleware.RequestIDMiddleware())
+	app.Use(middleware.ActorMiddleware())
+	app.Use(middleware.LanguageMiddleware())
+	app.Use(middleware.TracingMiddleware())
 	app.Use(middleware.LoggerMiddleware(logger))
+	app.Use(middleware.MetricsMiddleware())
 
-	// Health check
-	app.Get("/api/health", func(c *fiber.Ctx) error {
-		return c.JSON(fiber.Map{"status": "ok"})
-	})
+	// Health check, kept for existing clients; it is a liveness check
+	app.Get("/api/health", healthHandler.Live)
 
 	// API v1 routes
 	api := app.Group("/api")
@@ -23,14 +32,50 @@ func SetupRoutes(app *fiber.App, userHandler *handler.UserHandler, logger *zap.L
 		// User routes
 		users := api.Group("/users")
 		{
-			users.Post("/", userHandler.CreateUser)
-			users.Get("/", userHandler.GetUsersPaginated) // Paginated by default
-			users.Get("/all", userHandler.GetAllUsers)    // Get all without pagination
+			users.Post("/", idempotency, userHandler.CreateUser)
+			users.Get("/", userHandler.GetUsersPaginated)       // Paginated by default
+			users.Get("/all", userHandler.GetAllUsers)          // Get all without pagination
+			users.Get("/events", eventHandler.StreamUserEvents) // Server-Sent Events change feed
+			users.Post("/batch", idempotency, userHandler.BatchUsers)
+			users.Post("/import", idempotency, userHandler.ImportUsers)
+			users.Get("/export", userHandler.ExportUsers)
+			users.Get("/birthdays", userHandler.GetBirthdays)
+			users.Get("/stats", statsHandler.GetUserStats)
+			users.Get("/by-email/:email", userHandler.GetUserByEmail)
 			users.Get("/:id", userHandler.GetUserByID)
 			users.Put("/:id", userHandler.UpdateUser)
+			users.Patch("/:id", userHandler.PatchUser)
 			users.Delete("/:id", userHandler.DeleteUser)
+			users.Post("/:id/restore", userHandler.RestoreUser)
+			users.Get("/:id/history", userHandler.GetUserHistory)
+			users.Get("/:id/age", userHandler.GetUserAge)
+			users.Post("/:id/age-check", userHandler.CheckUserAge)
+		}
+
+		// Export job routes
+		exports := api.Group("/exports")
+		{
+			exports.Post("/", idempotency, exportHandler.CreateExport)
+			exports.Get("/:id", exportHandler.GetExport)
+			exports.Get("/:id/download", exportHandler.DownloadExport)
+		}
+
+		// Admin routes
+		webhooks := api.Group("/admin/webhooks")
+		{
+			webhooks.Post("/", webhookHandler.CreateSubscription)
+			webhooks.Get("/", webhookHandler.ListSubscriptions)
+			webhooks.Get("/:id", webhookHandler.GetSubscription)
+			webhooks.Post("/:id/disable", webhookHandler.DisableSubscription)
+			webhooks.Post("/:id/enable", webhookHandler.EnableSubscription)
+			webhooks.Get("/:id/deliveries", webhookHandler.ListDeliveries)
+			webhooks.Post("/:id/replay", webhookHandler.ReplayDeliveries)
 		}
 	}
 }
 
-
+// SetupAdminRoutes registers the routes of the admin server, which is kept
+// off the public port.
+func SetupAdminRoutes(admin *fiber.App) {
+	admin.Get("/metrics", adaptor.HTTPHandler(metrics.Handler()))
+}
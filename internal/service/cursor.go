@@ -0,0 +1,34 @@
+package service
+
+import (
+	"encoding/base64"
+	"encoding/json"
+)
+
+// ErrInvalidCursor is returned when a pagination cursor cannot be decoded or
+// does not match the requested sort order.
+var ErrInvalidCursor = ErrInvalidFilter.Wrapf("invalid cursor")
+
+// cursorToken is the payload of an opaque pagination cursor. It records the
+// sort order it was issued for and the keyset position of the last row.
+type cursorToken struct {
+	Sort string   `json:"s"`
+	Key  []string `json:"k"`
+}
+
+func encodeCursor(t cursorToken) string {
+	b, _ := json.Marshal(t)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func decodeCursor(s string) (cursorToken, error) {
+	var t cursorToken
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return t, ErrInvalidCursor
+	}
+	if err := json.Unmarshal(b, &t); err != nil || len(t.Key) == 0 {
+		return t, ErrInvalidCursor
+	}
+	return t, nil
+}
@@ -0,0 +1,313 @@
+package service
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/Pallavi566/Go-Backend/internal/apperr"
+	"github.com/Pallavi566/Go-Backend/internal/audit"
+	"github.com/Pallavi566/Go-Backend/internal/models"
+	"github.com/Pallavi566/Go-Backend/internal/repository"
+)
+
+var (
+	// ErrExportJobNotFound is returned when the export job does not exist.
+	ErrExportJobNotFound = repository.ErrExportJobNotFound
+	// ErrExportNotReady is returned when downloading a job that has not
+	// succeeded (yet).
+	ErrExportNotReady = apperr.New(apperr.ErrConflict, "export_not_ready", "export is not ready")
+	// ErrExportExpired is returned when downloading a job past its expiry,
+	// whose file is about to be deleted.
+	ErrExportExpired = apperr.New(apperr.ErrNotFound, "export_expired", "export has expired")
+	// ErrExportJobSuperseded is returned when a running job was taken over
+	// by another attempt, which is left to finish it.
+	ErrExportJobSuperseded = repository.ErrExportJobSuperseded
+)
+
+const (
+	// progressInterval is how often a running job records its progress,
+	// which doubles as its heartbeat.
+	progressInterval = 2 * time.Second
+	// staleExportAfter is how long a running job may go without a
+	// heartbeat before it is considered orphaned and requeued.
+	staleExportAfter = 5 * time.Minute
+	// maxExportAttempts is the number of times a job is started before it
+	// is failed rather than requeued when it goes stale.
+	maxExportAttempts = 3
+)
+
+type ExportJobService struct {
+	repo      repository.ExportJobRepository
+	users     *UserService
+	dir       string
+	retention time.Duration
+}
+
+// NewExportJobService returns a service that writes finished exports to
+// dir, which must exist, and keeps them for retention.
+func NewExportJobService(repo repository.ExportJobRepository, users *UserService, dir string, retention time.Duration) *ExportJobService {
+	return &ExportJobService{repo: repo, users: users, dir: dir, retention: retention}
+}
+
+// CreateJob validates the export and queues it.
+func (s *ExportJobService) CreateJob(ctx context.Context, req models.CreateExportRequest) (*models.ExportJob, error) {
+	format, err := ParseExportFormat(req.Format)
+	if err != nil {
+		return nil, err
+	}
+	// Reject invalid filters now rather than when the job runs.
+	if _, err := s.users.NewExport(ctx, req.Filters, format); err != nil {
+		return nil, err
+	}
+
+	id, err := s.repo.Create(ctx, string(format), req.Filters, audit.Actor(ctx), audit.RequestID(ctx))
+	if err != nil {
+		return nil, err
+	}
+	return s.GetJob(ctx, id)
+}
+
+// GetJob returns a job requested by the actor in ctx. Jobs of other actors
+// are reported as not found, so that their IDs cannot be probed.
+func (s *ExportJobService) GetJob(ctx context.Context, id int64) (*models.ExportJob, error) {
+	job, err := s.repo.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if job.RequestedBy != audit.Actor(ctx) {
+		return nil, fmt.Errorf("%w: id %d", ErrExportJobNotFound, id)
+	}
+	job.Progress = exportProgress(job)
+	if job.FinishedAt != nil {
+		expiresAt := job.FinishedAt.Add(s.retention)
+		job.ExpiresAt = &expiresAt
+	}
+	return job, nil
+}
+
+// GetDownload returns a succeeded job of the actor in ctx, whose FilePath
+// is the finished file. ErrExportNotReady is returned for jobs that have not
+// succeeded and ErrExportExpired for jobs past their expiry.
+func (s *ExportJobService) GetDownload(ctx context.Context, id int64) (*models.ExportJob, error) {
+	job, err := s.GetJob(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if job.Status != models.ExportSucceeded {
+		return nil, ErrExportNotReady
+	}
+	if !time.Now().Before(*job.ExpiresAt) {
+		return nil, ErrExportExpired
+	}
+	return job, nil
+}
+
+// PurgeExpiredJobs deletes jobs that finished longer than the retention
+// ago, along with their files, and returns how many were deleted.
+func (s *ExportJobService) PurgeExpiredJobs(ctx context.Context) (int64, error) {
+	const batchSize = 100
+	var total int64
+	for {
+		jobs, err := s.repo.Expired(ctx, time.Now().Add(-s.retention), batchSize)
+		if err != nil {
+			return total, err
+		}
+		for _, job := range jobs {
+			// The file goes first, so that it is never left behind by a
+			// job that is gone.
+			if job.FilePath != "" {
+				if err := os.Remove(job.FilePath); err != nil && !errors.Is(err, os.ErrNotExist) {
+					return total, fmt.Errorf("remove file of export job %d: %w", job.ID, err)
+				}
+			}
+			if err := s.repo.Delete(ctx, job.ID); err != nil {
+				return total, err
+			}
+			total++
+		}
+		if len(jobs) < batchSize {
+			return total, nil
+		}
+	}
+}
+
+// QueuedJobs returns the IDs of up to limit queued jobs, oldest first.
+func (s *ExportJobService) QueuedJobs(ctx context.Context, limit int) ([]int64, error) {
+	return s.repo.Queued(ctx, limit)
+}
+
+// RequeueStaleJobs puts jobs orphaned by a crashed worker back in the queue,
+// or fails them once they have been attempted maxExportAttempts times, and
+// returns how many were requeued and failed.
+func (s *ExportJobService) RequeueStaleJobs(ctx context.Context) (requeued, failed int64, err error) {
+	cutoff := time.Now().Add(-staleExportAfter)
+	failed, err = s.repo.FailStale(ctx, cutoff, maxExportAttempts,
+		fmt.Sprintf("export was interrupted %d times", maxExportAttempts), time.Now())
+	if err != nil {
+		return 0, 0, err
+	}
+	requeued, err = s.repo.RequeueStale(ctx, cutoff, maxExportAttempts)
+	return requeued, failed, err
+}
+
+// RunJob claims a queued job and runs it to completion. It does nothing if
+// another worker claimed the job first. When ctx is cancelled mid-run, the
+// job is put back in the queue so that it runs again after a restart;
+// otherwise a failure is recorded on the job. ErrExportJobSuperseded is
+// returned if the job was requeued as stale and claimed again meanwhile,
+// in which case the outcome is left to the newer attempt.
+func (s *ExportJobService) RunJob(ctx context.Context, id int64) error {
+	claimed, err := s.repo.Claim(ctx, id, time.Now())
+	if err != nil || !claimed {
+		return err
+	}
+
+	// Without the attempt, nothing can be recorded on the job; it is
+	// requeued once it goes stale.
+	job, err := s.repo.Get(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	// The heartbeat runs apart from the export, so that the job is not
+	// taken for orphaned while a slow query or the finalization of the
+	// file holds the export up. It cancels the export if the job is taken
+	// over by another attempt.
+	runCtx, cancel := context.WithCancelCause(ctx)
+	var processed atomic.Int64
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		s.heartbeat(runCtx, cancel, job, &processed)
+	}()
+	err = s.runExport(runCtx, job, &processed)
+	cancel(nil)
+	wg.Wait()
+	if err == nil {
+		return nil
+	}
+	if cause := context.Cause(runCtx); errors.Is(cause, ErrExportJobSuperseded) {
+		return cause
+	}
+	if errors.Is(err, ErrExportJobSuperseded) {
+		return err
+	}
+
+	// ctx may be done, so record the outcome on a fresh one.
+	recordCtx, cancelRecord := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancelRecord()
+	if ctx.Err() != nil {
+		if rerr := s.repo.Requeue(recordCtx, id, job.Attempts); rerr != nil {
+			return fmt.Errorf("requeue export job %d: %w", id, rerr)
+		}
+		return ctx.Err()
+	}
+	if ferr := s.repo.Fail(recordCtx, id, job.Attempts, err.Error(), time.Now()); ferr != nil {
+		return fmt.Errorf("record failure of export job %d: %w", id, ferr)
+	}
+	return err
+}
+
+// heartbeat records the progress of the attempt at a running job every
+// progressInterval until ctx is done. It cancels ctx with
+// ErrExportJobSuperseded once the job is no longer running the attempt.
+func (s *ExportJobService) heartbeat(ctx context.Context, cancel context.CancelCauseFunc, job *models.ExportJob, processed *atomic.Int64) {
+	ticker := time.NewTicker(progressInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			// Other errors are retried on the next tick; the job is only
+			// requeued after staleExportAfter without a heartbeat.
+			err := s.repo.Heartbeat(ctx, job.ID, job.Attempts, processed.Load(), now)
+			if errors.Is(err, ErrExportJobSuperseded) {
+				cancel(err)
+				return
+			}
+		}
+	}
+}
+
+// runExport writes the export of a running job to a temporary file and
+// moves it into place once complete, so that a download never sees a
+// partial file. Each attempt writes its own files, so that an attempt that
+// was superseded cannot clobber the file of the one that took over.
+func (s *ExportJobService) runExport(ctx context.Context, job *models.ExportJob, processed *atomic.Int64) error {
+	format := ExportFormat(job.Format)
+	export, err := s.users.NewExport(ctx, job.Filters, format)
+	if err != nil {
+		return err
+	}
+
+	total, err := export.count(ctx)
+	if err != nil {
+		return err
+	}
+	if err := s.repo.UpdateProgress(ctx, job.ID, job.Attempts, total, 0, time.Now()); err != nil {
+		return err
+	}
+
+	path := filepath.Join(s.dir, fmt.Sprintf("users-export-%d-%d.%s", job.ID, job.Attempts, format))
+	tmp := path + ".part"
+	f, err := os.Create(tmp)
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp)
+	defer f.Close()
+
+	w := bufio.NewWriter(f)
+	err = export.write(ctx, w, func() error {
+		processed.Add(1)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	info, err := os.Stat(tmp)
+	if err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return err
+	}
+
+	err = s.repo.Complete(ctx, job.ID, job.Attempts, processed.Load(), path, info.Size(), time.Now())
+	if errors.Is(err, ErrExportJobSuperseded) {
+		os.Remove(path)
+	}
+	return err
+}
+
+// exportProgress returns the percentage of rows a job has written.
+func exportProgress(job *models.ExportJob) float64 {
+	switch {
+	case job.Status == models.ExportSucceeded:
+		return 100
+	case job.TotalRows == nil || *job.TotalRows == 0:
+		return 0
+	}
+	p := float64(job.ProcessedRows) / float64(*job.TotalRows) * 100
+	if p > 100 {
+		// Users created while the export runs can push it past the count.
+		p = 100
+	}
+	return p
+}
@@ -0,0 +1,30 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/Pallavi566/Go-Backend/internal/models"
+)
+
+func TestExportProgress(t *testing.T) {
+	total := func(n int64) *int64 { return &n }
+	tests := []struct {
+		name string
+		job  models.ExportJob
+		want float64
+	}{
+		{"queued", models.ExportJob{Status: models.ExportQueued}, 0},
+		{"counting", models.ExportJob{Status: models.ExportRunning}, 0},
+		{"running", models.ExportJob{Status: models.ExportRunning, TotalRows: total(200), ProcessedRows: 50}, 25},
+		{"overshoot", models.ExportJob{Status: models.ExportRunning, TotalRows: total(10), ProcessedRows: 12}, 100},
+		{"empty", models.ExportJob{Status: models.ExportSucceeded, TotalRows: total(0)}, 100},
+		{"failed", models.ExportJob{Status: models.ExportFailed, TotalRows: total(4), ProcessedRows: 1}, 25},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := exportProgress(&tt.job); got != tt.want {
+				t.Errorf("exportProgress() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
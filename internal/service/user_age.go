@@ -0,0 +1,52 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Pallavi566/Go-Backend/internal/age"
+	"github.com/Pallavi566/Go-Backend/internal/models"
+	"github.com/Pallavi566/Go-Backend/internal/tracing"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// CheckAge returns the age of a user on the calendar date asOf, or today in
+// the user's time zone when asOf is nil, and with minAge set, whether they
+// had reached it. Every answer is recorded for compliance before it is
+// returned; none is given if it cannot be.
+func (s *UserService) CheckAge(ctx context.Context, id int, asOf *time.Time, minAge *int) (_ *models.AgeCheck, err error) {
+	ctx, span := tracing.Start(ctx, "UserService.CheckAge", attribute.Int("user.id", id))
+	defer tracing.End(span, &err)
+
+	user, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	on := s.ages.Today(user.Timezone)
+	if asOf != nil {
+		on = age.Date(*asOf)
+	}
+	check := ageCheck(user, on, minAge, s.ages.Rule())
+
+	if err := s.repo.RecordAgeCheck(ctx, user, check, on, s.ages.Rule()); err != nil {
+		return nil, fmt.Errorf("record age check: %w", err)
+	}
+	return &check, nil
+}
+
+// ageCheck computes the age of u on the date on.
+func ageCheck(u *models.User, on time.Time, minAge *int, rule age.LeapDayRule) models.AgeCheck {
+	check := models.AgeCheck{
+		UserID: u.ID,
+		AsOf:   on.Format("2006-01-02"),
+		Age:    age.Years(u.DOB, on, rule),
+		MinAge: minAge,
+	}
+	if minAge != nil {
+		meets := check.Age >= *minAge
+		check.MeetsMinAge = &meets
+	}
+	return check
+}
@@ -0,0 +1,50 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Pallavi566/Go-Backend/internal/age"
+	"github.com/Pallavi566/Go-Backend/internal/models"
+)
+
+func TestAgeCheck(t *testing.T) {
+	date := func(y int, m time.Month, d int) time.Time {
+		return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+	}
+	u := &models.User{ID: 7, DOB: date(2006, 11, 5)}
+
+	tests := []struct {
+		name     string
+		user     *models.User
+		on       time.Time
+		minAge   *int
+		rule     age.LeapDayRule
+		wantAge  int
+		wantMeet *bool
+	}{
+		{"Age only", u, date(2024, 11, 5), nil, age.Feb28, 18, nil},
+		{"Eighteenth birthday", u, date(2024, 11, 5), intPtr(18), age.Feb28, 18, boolPtr(true)},
+		{"Day before", u, date(2024, 11, 4), intPtr(18), age.Feb28, 17, boolPtr(false)},
+		{"Before birth", u, date(2000, 1, 1), intPtr(0), age.Feb28, 0, boolPtr(true)},
+		{"Leap day, feb28 rule", &models.User{DOB: date(2004, 2, 29)}, date(2022, 2, 28), intPtr(18), age.Feb28, 18, boolPtr(true)},
+		{"Leap day, mar1 rule", &models.User{DOB: date(2004, 2, 29)}, date(2022, 2, 28), intPtr(18), age.Mar1, 17, boolPtr(false)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ageCheck(tt.user, tt.on, tt.minAge, tt.rule)
+			if got.Age != tt.wantAge {
+				t.Errorf("Age = %d, want %d", got.Age, tt.wantAge)
+			}
+			if got.AsOf != tt.on.Format("2006-01-02") {
+				t.Errorf("AsOf = %s, want %s", got.AsOf, tt.on.Format("2006-01-02"))
+			}
+			if (got.MeetsMinAge == nil) != (tt.wantMeet == nil) || (got.MeetsMinAge != nil && *got.MeetsMinAge != *tt.wantMeet) {
+				t.Errorf("MeetsMinAge = %v, want %v", got.MeetsMinAge, tt.wantMeet)
+			}
+		})
+	}
+}
+
+func boolPtr(v bool) *bool { return &v }
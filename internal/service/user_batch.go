@@ -0,0 +1,125 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/Pallavi566/Go-Backend/internal/models"
+	"github.com/Pallavi566/Go-Backend/internal/repository"
+	"github.com/Pallavi566/Go-Backend/internal/tracing"
+)
+
+// ErrBatchAborted is reported for operations of an atomic batch that were
+// rolled back or never attempted because another operation failed.
+var ErrBatchAborted = errors.New("not applied: batch aborted")
+
+// BatchOutcome is the result of one batch operation. ID and Version are set
+// when the operation succeeded; Version is zero after a delete.
+type BatchOutcome struct {
+	ID      int
+	Version int
+	Err     error
+}
+
+// BatchUsers applies ops, which must already be validated. When atomic is
+// set they run in a single transaction and the first failure rolls back
+// every operation; otherwise each runs in its own transaction and failures
+// do not affect the others. It reports whether anything was committed. The
+// span of the call records the first failure.
+func (s *UserService) BatchUsers(ctx context.Context, ops []models.BatchOperation, atomic bool) (outcomes []BatchOutcome, committed bool) {
+	ctx, span := tracing.Start(ctx, "UserService.BatchUsers")
+	var err error
+	defer tracing.End(span, &err)
+
+	outcomes = make([]BatchOutcome, len(ops))
+
+	if !atomic {
+		for i, op := range ops {
+			opErr := s.repo.InTx(ctx, func(tx *repository.UserTx) error {
+				var err error
+				outcomes[i], err = applyBatchOp(ctx, tx, op)
+				return err
+			})
+			outcomes[i].Err = opErr
+			committed = committed || opErr == nil
+			if err == nil {
+				err = opErr
+			}
+		}
+		return outcomes, committed
+	}
+
+	failed := -1
+	err = s.repo.InTx(ctx, func(tx *repository.UserTx) error {
+		for i, op := range ops {
+			outcome, err := applyBatchOp(ctx, tx, op)
+			if err != nil {
+				failed = i
+				return err
+			}
+			outcomes[i] = outcome
+		}
+		return nil
+	})
+	if err == nil {
+		return outcomes, true
+	}
+
+	for i := range outcomes {
+		switch {
+		case i == failed:
+			outcomes[i] = BatchOutcome{Err: err}
+		case failed < 0:
+			// The commit itself failed.
+			outcomes[i] = BatchOutcome{Err: err}
+		default:
+			outcomes[i] = BatchOutcome{Err: ErrBatchAborted}
+		}
+	}
+	return outcomes, false
+}
+
+func applyBatchOp(ctx context.Context, tx *repository.UserTx, op models.BatchOperation) (BatchOutcome, error) {
+	switch op.Op {
+	case models.BatchCreate:
+		user, err := newUser(*op.Data)
+		if err != nil {
+			return BatchOutcome{}, err
+		}
+		id, err := tx.Create(ctx, user)
+		if err != nil {
+			return BatchOutcome{}, err
+		}
+		return BatchOutcome{ID: int(id), Version: 1}, nil
+
+	case models.BatchUpdate:
+		dob, err := time.Parse("2006-01-02", op.Data.DOB)
+		if err != nil {
+			return BatchOutcome{}, err
+		}
+		user, err := tx.Update(ctx, op.ID, func(u *models.User) error {
+			if op.Version != 0 && u.Version != op.Version {
+				return ErrPreconditionFailed
+			}
+			u.Name = op.Data.Name
+			u.DOB = dob
+			setProfile(u, op.Data.Email, op.Data.Phone, op.Data.Timezone, op.Data.Locale)
+			return nil
+		})
+		if err != nil {
+			return BatchOutcome{}, err
+		}
+		return BatchOutcome{ID: user.ID, Version: user.Version}, nil
+
+	case models.BatchDelete:
+		if err := tx.Delete(ctx, op.ID, op.Version); err != nil {
+			if errors.Is(err, repository.ErrVersionConflict) {
+				return BatchOutcome{}, ErrPreconditionFailed
+			}
+			return BatchOutcome{}, err
+		}
+		return BatchOutcome{ID: op.ID}, nil
+	}
+	return BatchOutcome{}, errors.New("unknown batch operation " + op.Op)
+}
@@ -0,0 +1,185 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/Pallavi566/Go-Backend/internal/age"
+	"github.com/Pallavi566/Go-Backend/internal/models"
+	"github.com/Pallavi566/Go-Backend/internal/repository"
+	"github.com/Pallavi566/Go-Backend/internal/tracing"
+)
+
+// defaultBirthdayWindow is the number of days after from listed when no end
+// of the window is given.
+const defaultBirthdayWindow = 6
+
+// GetBirthdays returns a page of the active users whose birthday falls
+// between from and to, both YYYY-MM-DD and inclusive, in order of their
+// birthday. from defaults to today and to to a week from from. The window
+// may span New Year but must be shorter than a year, so that everyone has
+// at most one birthday in it.
+func (s *UserService) GetBirthdays(ctx context.Context, from, to string, page, limit int) (_ *models.BirthdaysResponse, err error) {
+	ctx, span := tracing.Start(ctx, "UserService.GetBirthdays")
+	defer tracing.End(span, &err)
+
+	start, end, err := s.birthdayDates(from, to)
+	if err != nil {
+		return nil, err
+	}
+	window := birthdayWindow(start, end, s.ages.Rule())
+
+	users, err := s.repo.GetByBirthday(ctx, window, limit, (page-1)*limit)
+	if err != nil {
+		return nil, err
+	}
+	total, err := s.repo.CountByBirthday(ctx, window)
+	if err != nil {
+		return nil, err
+	}
+
+	data := make([]models.UpcomingBirthday, 0, len(users))
+	for _, u := range users {
+		birthday := birthdayIn(u.DOB, start, end, s.ages.Rule())
+		data = append(data, models.UpcomingBirthday{
+			UserResponse: s.newUserResponse(ctx, u),
+			Birthday:     birthday.Format("2006-01-02"),
+			Turning:      birthday.Year() - u.DOB.Year(),
+		})
+	}
+
+	totalPages := (int(total) + limit - 1) / limit
+	return &models.BirthdaysResponse{
+		From:       start.Format("2006-01-02"),
+		To:         end.Format("2006-01-02"),
+		Data:       data,
+		Page:       page,
+		Limit:      limit,
+		Total:      total,
+		TotalPages: totalPages,
+	}, nil
+}
+
+// BirthdayDigest lists the users whose birthday it is on day. It reports
+// false, with no digest, when the day's digest has already been published.
+func (s *UserService) BirthdayDigest(ctx context.Context, day time.Time) (_ *models.BirthdayDigestData, _ bool, err error) {
+	ctx, span := tracing.Start(ctx, "UserService.BirthdayDigest")
+	defer tracing.End(span, &err)
+
+	day = age.Date(day)
+	sent, err := s.repo.BirthdayDigestSent(ctx, day)
+	if err != nil || sent {
+		return nil, false, err
+	}
+
+	digest := &models.BirthdayDigestData{
+		Date:  day.Format("2006-01-02"),
+		Users: []models.BirthdayPerson{},
+	}
+	err = s.repo.StreamByBirthday(ctx, birthdayWindow(day, day, s.ages.Rule()), func(u *models.User) error {
+		digest.Users = append(digest.Users, models.BirthdayPerson{
+			ID:       u.ID,
+			Name:     u.Name,
+			Email:    u.Email,
+			Timezone: u.Timezone,
+			Locale:   u.Locale,
+			Turning:  day.Year() - u.DOB.Year(),
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, false, err
+	}
+	return digest, true, nil
+}
+
+// PublishBirthdayDigest publishes the digest of today's birthdays, in the
+// default time zone, as a user.birthday_digest event, unless it has already
+// been published. It returns the number of users listed and whether it
+// published anything.
+func (s *UserService) PublishBirthdayDigest(ctx context.Context) (_ int, _ bool, err error) {
+	ctx, span := tracing.Start(ctx, "UserService.PublishBirthdayDigest")
+	defer tracing.End(span, &err)
+
+	today := s.ages.Today("")
+	digest, ok, err := s.BirthdayDigest(ctx, today)
+	if err != nil || !ok {
+		return 0, false, err
+	}
+	published, err := s.repo.PublishBirthdayDigest(ctx, today, *digest)
+	if err != nil || !published {
+		return 0, false, err
+	}
+	return len(digest.Users), true, nil
+}
+
+// birthdayDates parses the bounds of a birthday window.
+func (s *UserService) birthdayDates(from, to string) (time.Time, time.Time, error) {
+	start := s.ages.Today("")
+	if from != "" {
+		t, err := time.Parse("2006-01-02", from)
+		if err != nil {
+			return time.Time{}, time.Time{}, ErrInvalidFilter.Wrapf("from must be YYYY-MM-DD")
+		}
+		start = t
+	}
+	end := start.AddDate(0, 0, defaultBirthdayWindow)
+	if to != "" {
+		t, err := time.Parse("2006-01-02", to)
+		if err != nil {
+			return time.Time{}, time.Time{}, ErrInvalidFilter.Wrapf("to must be YYYY-MM-DD")
+		}
+		end = t
+	}
+
+	if end.Before(start) {
+		return time.Time{}, time.Time{}, ErrInvalidFilter.Wrapf("to must not be before from")
+	}
+	// Feb 29 counts as Feb 28 here, so that a window starting on it cannot
+	// hold two leap day birthdays.
+	if !end.Before(age.Birthday(start, start.Year()+1, age.Feb28)) {
+		return time.Time{}, time.Time{}, ErrInvalidFilter.Wrapf("the window must be shorter than a year")
+	}
+	return start, end, nil
+}
+
+// birthdayWindow translates a window of dates, shorter than a year, into
+// the birthday ranges to query: one per calendar year it touches.
+func birthdayWindow(from, to time.Time, rule age.LeapDayRule) repository.BirthdayWindow {
+	// Anyone born on February 29 will do to find where leap day birthdays
+	// fall.
+	leapling := time.Date(2000, time.February, 29, 0, 0, 0, 0, time.UTC)
+
+	var w repository.BirthdayWindow
+	for year := from.Year(); year <= to.Year(); year++ {
+		start, end := from, to
+		if year > from.Year() {
+			start = time.Date(year, time.January, 1, 0, 0, 0, 0, time.UTC)
+		}
+		if year < to.Year() {
+			end = time.Date(year, time.December, 31, 0, 0, 0, 0, time.UTC)
+		}
+		w.Ranges = append(w.Ranges, repository.BirthdayRange{From: monthDay(start), To: monthDay(end)})
+
+		if b := age.Birthday(leapling, year, rule); !b.Before(start) && !b.After(end) {
+			w.LeapDay = monthDay(b)
+		}
+	}
+	return w
+}
+
+// birthdayIn returns the birthday of someone born on dob that falls between
+// from and to.
+func birthdayIn(dob, from, to time.Time, rule age.LeapDayRule) time.Time {
+	for year := from.Year(); year <= to.Year(); year++ {
+		if b := age.Birthday(dob, year, rule); !b.Before(from) && !b.After(to) {
+			return b
+		}
+	}
+	return age.NextBirthday(dob, from, rule)
+}
+
+// monthDay returns the date d as an MMDD number, like the birthday column.
+func monthDay(d time.Time) int {
+	return int(d.Month())*100 + d.Day()
+}
@@ -0,0 +1,137 @@
+package service
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/Pallavi566/Go-Backend/internal/age"
+	"github.com/Pallavi566/Go-Backend/internal/repository"
+)
+
+func TestBirthdayWindow(t *testing.T) {
+	date := func(y int, m time.Month, d int) time.Time {
+		return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+	}
+	tests := []struct {
+		name string
+		from time.Time
+		to   time.Time
+		rule age.LeapDayRule
+		want repository.BirthdayWindow
+	}{
+		{
+			name: "Within a month",
+			from: date(2024, 5, 10), to: date(2024, 5, 16), rule: age.Feb28,
+			want: repository.BirthdayWindow{Ranges: []repository.BirthdayRange{{From: 510, To: 516}}},
+		},
+		{
+			name: "Across New Year",
+			from: date(2024, 12, 20), to: date(2025, 1, 10), rule: age.Feb28,
+			want: repository.BirthdayWindow{Ranges: []repository.BirthdayRange{{From: 1220, To: 1231}, {From: 101, To: 110}}},
+		},
+		{
+			name: "Leap year",
+			from: date(2024, 2, 25), to: date(2024, 3, 2), rule: age.Feb28,
+			want: repository.BirthdayWindow{Ranges: []repository.BirthdayRange{{From: 225, To: 302}}, LeapDay: 229},
+		},
+		{
+			name: "Common year ending Feb 28, feb28 rule",
+			from: date(2025, 2, 20), to: date(2025, 2, 28), rule: age.Feb28,
+			want: repository.BirthdayWindow{Ranges: []repository.BirthdayRange{{From: 220, To: 228}}, LeapDay: 228},
+		},
+		{
+			name: "Common year ending Feb 28, mar1 rule",
+			from: date(2025, 2, 20), to: date(2025, 2, 28), rule: age.Mar1,
+			want: repository.BirthdayWindow{Ranges: []repository.BirthdayRange{{From: 220, To: 228}}},
+		},
+		{
+			name: "Common year starting Mar 1, mar1 rule",
+			from: date(2025, 3, 1), to: date(2025, 3, 7), rule: age.Mar1,
+			want: repository.BirthdayWindow{Ranges: []repository.BirthdayRange{{From: 301, To: 307}}, LeapDay: 301},
+		},
+		{
+			name: "Almost a year across New Year",
+			from: date(2024, 3, 1), to: date(2025, 2, 27), rule: age.Feb28,
+			want: repository.BirthdayWindow{Ranges: []repository.BirthdayRange{{From: 301, To: 1231}, {From: 101, To: 227}}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := birthdayWindow(tt.from, tt.to, tt.rule); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("birthdayWindow() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBirthdayIn(t *testing.T) {
+	date := func(y int, m time.Month, d int) time.Time {
+		return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+	}
+	from, to := date(2024, 12, 20), date(2025, 3, 1)
+	tests := []struct {
+		name string
+		dob  time.Time
+		rule age.LeapDayRule
+		want time.Time
+	}{
+		{"Before New Year", date(1990, 12, 25), age.Feb28, date(2024, 12, 25)},
+		{"After New Year", date(1990, 1, 5), age.Feb28, date(2025, 1, 5)},
+		{"Leap day, feb28 rule", date(2000, 2, 29), age.Feb28, date(2025, 2, 28)},
+		{"Leap day, mar1 rule", date(2000, 2, 29), age.Mar1, date(2025, 3, 1)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := birthdayIn(tt.dob, from, to, tt.rule); !got.Equal(tt.want) {
+				t.Errorf("birthdayIn() = %s, want %s", got.Format("2006-01-02"), tt.want.Format("2006-01-02"))
+			}
+		})
+	}
+}
+
+func TestBirthdayDates(t *testing.T) {
+	clock := age.FixedClock(time.Date(2024, 12, 28, 12, 0, 0, 0, time.UTC))
+	s := &UserService{ages: age.NewCalculator(clock, age.Feb28, time.UTC)}
+
+	tests := []struct {
+		name     string
+		from, to string
+		wantFrom string
+		wantTo   string
+		wantErr  bool
+	}{
+		{name: "Defaults to the coming week", wantFrom: "2024-12-28", wantTo: "2025-01-03"},
+		{name: "Explicit window", from: "2024-12-20", to: "2025-01-10", wantFrom: "2024-12-20", wantTo: "2025-01-10"},
+		{name: "Single day", from: "2025-02-28", to: "2025-02-28", wantFrom: "2025-02-28", wantTo: "2025-02-28"},
+		{name: "Just under a year", from: "2024-03-01", to: "2025-02-28", wantFrom: "2024-03-01", wantTo: "2025-02-28"},
+		{name: "A full year", from: "2024-03-01", to: "2025-03-01", wantErr: true},
+		{name: "From Feb 29 to the next Feb 28", from: "2024-02-29", to: "2025-02-28", wantErr: true},
+		{name: "Backwards", from: "2025-01-10", to: "2024-12-20", wantErr: true},
+		{name: "Bad date", from: "12/20", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			from, to, err := s.birthdayDates(tt.from, tt.to)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidFilter) {
+					t.Errorf("birthdayDates() error = %v, want ErrInvalidFilter", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("birthdayDates() error = %v", err)
+			}
+			if got := from.Format("2006-01-02"); got != tt.wantFrom {
+				t.Errorf("from = %s, want %s", got, tt.wantFrom)
+			}
+			if got := to.Format("2006-01-02"); got != tt.wantTo {
+				t.Errorf("to = %s, want %s", got, tt.wantTo)
+			}
+		})
+	}
+}
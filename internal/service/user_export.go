@@ -0,0 +1,263 @@
+package service
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Pallavi566/Go-Backend/internal/apperr"
+	"github.com/Pallavi566/Go-Backend/internal/models"
+	"github.com/Pallavi566/Go-Backend/internal/repository"
+	"github.com/xuri/excelize/v2"
+)
+
+// ExportFormat identifies the file format of a user export.
+type ExportFormat string
+
+const (
+	ExportCSV    ExportFormat = "csv"
+	ExportNDJSON ExportFormat = "ndjson"
+	ExportXLSX   ExportFormat = "xlsx"
+)
+
+// ParseExportFormat parses a format name as given in the format query
+// parameter.
+func ParseExportFormat(s string) (ExportFormat, error) {
+	switch f := ExportFormat(strings.ToLower(s)); f {
+	case ExportCSV, ExportNDJSON, ExportXLSX:
+		return f, nil
+	}
+	return "", ErrInvalidFilter.Wrapf("unsupported export format %q, expected csv, ndjson or xlsx", s)
+}
+
+// ContentType returns the media type of files in the format.
+func (f ExportFormat) ContentType() string {
+	switch f {
+	case ExportCSV:
+		return "text/csv; charset=utf-8"
+	case ExportNDJSON:
+		return "application/x-ndjson"
+	case ExportXLSX:
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	}
+	return "application/octet-stream"
+}
+
+// exportColumns are the columns of CSV and XLSX exports.
+var exportColumns = []string{"id", "name", "dob", "email", "phone", "timezone", "locale", "age", "version", "deleted_at"}
+
+// maxXLSXRows is the number of users an XLSX export holds at most: a sheet
+// has room for 1,048,576 rows, the first of which is the header.
+const maxXLSXRows = excelize.TotalRows - 1
+
+// ErrExportTooLarge is returned when more users match an export than its
+// format can hold.
+var ErrExportTooLarge = apperr.New(apperr.ErrUnprocessable, "export_too_large", "export has too many rows for its format")
+
+// UserExport is a validated export, ready to be written.
+type UserExport struct {
+	users  *UserService
+	filter repository.UserFilter
+	format ExportFormat
+	now    time.Time
+}
+
+// NewExport validates the filters of an export up front, so that errors can
+// be reported before the response starts streaming. XLSX exports are
+// rejected with ErrExportTooLarge when more users match than a sheet holds.
+func (s *UserService) NewExport(ctx context.Context, f models.UserFilter, format ExportFormat) (*UserExport, error) {
+	now := s.ages.Now()
+	filter, err := buildUserFilter(f, s.ages.Today(""), s.ages.Rule())
+	if err != nil {
+		return nil, err
+	}
+	e := &UserExport{users: s, filter: filter, format: format, now: now}
+	if format == ExportXLSX {
+		n, err := e.count(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if err := checkExportSize(format, n); err != nil {
+			return nil, err
+		}
+	}
+	return e, nil
+}
+
+// checkExportSize returns ErrExportTooLarge if n users do not fit in the
+// format.
+func checkExportSize(format ExportFormat, n int64) error {
+	if format == ExportXLSX && n > maxXLSXRows {
+		return ErrExportTooLarge.Wrapf("%d users match, xlsx holds at most %d; use csv or ndjson", n, maxXLSXRows)
+	}
+	return nil
+}
+
+// WriteTo streams every matching user to w. Rows are written as they are
+// read from the database, so memory stays flat however many users match;
+// XLSX rows are spooled by the spreadsheet writer, which keeps large sheets
+// in a temporary file. Ages are computed as of the moment the export was
+// created, so that all rows agree.
+func (e *UserExport) WriteTo(ctx context.Context, w io.Writer) error {
+	return e.write(ctx, w, nil)
+}
+
+// write streams the export to w, calling onRow, if set, after each row.
+func (e *UserExport) write(ctx context.Context, w io.Writer, onRow func() error) error {
+	var rw exportWriter
+	switch e.format {
+	case ExportCSV:
+		rw = newCSVExportWriter(w)
+	case ExportNDJSON:
+		rw = &ndjsonExportWriter{enc: newJSONEncoder(w)}
+	case ExportXLSX:
+		xw, err := newXLSXExportWriter(w)
+		if err != nil {
+			return err
+		}
+		defer xw.file.Close()
+		rw = xw
+	default:
+		return ErrInvalidFilter.Wrapf("unsupported export format %q", e.format)
+	}
+
+	err := e.users.repo.Stream(ctx, e.filter, func(u *models.User) error {
+		if err := rw.Write(e.users.newUserResponseAt(ctx, u, e.now)); err != nil {
+			return err
+		}
+		if onRow != nil {
+			return onRow()
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	return rw.Close()
+}
+
+// count returns the number of users the export will write.
+func (e *UserExport) count(ctx context.Context) (int64, error) {
+	return e.users.repo.Count(ctx, e.filter)
+}
+
+// exportWriter encodes users in an export format.
+type exportWriter interface {
+	Write(u models.UserResponse) error
+	// Close flushes the remaining output.
+	Close() error
+}
+
+// exportRecord returns the values of the export columns for u.
+func exportRecord(u models.UserResponse) []string {
+	age, deletedAt := "", ""
+	if u.Age != nil {
+		age = strconv.Itoa(*u.Age)
+	}
+	if u.DeletedAt != nil {
+		deletedAt = *u.DeletedAt
+	}
+	return []string{strconv.Itoa(u.ID), u.Name, u.DOB, u.Email, u.Phone, u.Timezone, u.Locale, age, strconv.Itoa(u.Version), deletedAt}
+}
+
+type csvExportWriter struct {
+	w *csv.Writer
+}
+
+func newCSVExportWriter(w io.Writer) *csvExportWriter {
+	cw := &csvExportWriter{w: csv.NewWriter(w)}
+	_ = cw.w.Write(exportColumns)
+	return cw
+}
+
+func (cw *csvExportWriter) Write(u models.UserResponse) error {
+	return cw.w.Write(exportRecord(u))
+}
+
+func (cw *csvExportWriter) Close() error {
+	cw.w.Flush()
+	return cw.w.Error()
+}
+
+// newJSONEncoder returns an encoder that leaves <, > and & unescaped, as
+// they are data, not HTML.
+func newJSONEncoder(w io.Writer) *json.Encoder {
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+	return enc
+}
+
+type ndjsonExportWriter struct {
+	enc *json.Encoder
+}
+
+func (nw *ndjsonExportWriter) Write(u models.UserResponse) error {
+	return nw.enc.Encode(u)
+}
+
+func (nw *ndjsonExportWriter) Close() error {
+	return nil
+}
+
+type xlsxExportWriter struct {
+	out    io.Writer
+	file   *excelize.File
+	stream *excelize.StreamWriter
+	row    int
+}
+
+func newXLSXExportWriter(w io.Writer) (*xlsxExportWriter, error) {
+	file := excelize.NewFile()
+	stream, err := file.NewStreamWriter("Sheet1")
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+	xw := &xlsxExportWriter{out: w, file: file, stream: stream, row: 1}
+
+	header := make([]interface{}, len(exportColumns))
+	for i, c := range exportColumns {
+		header[i] = c
+	}
+	if err := xw.setRow(header); err != nil {
+		file.Close()
+		return nil, err
+	}
+	return xw, nil
+}
+
+func (xw *xlsxExportWriter) Write(u models.UserResponse) error {
+	var age, deletedAt interface{}
+	if u.Age != nil {
+		age = *u.Age
+	}
+	if u.DeletedAt != nil {
+		deletedAt = *u.DeletedAt
+	}
+	return xw.setRow([]interface{}{u.ID, u.Name, u.DOB, u.Email, u.Phone, u.Timezone, u.Locale, age, u.Version, deletedAt})
+}
+
+func (xw *xlsxExportWriter) setRow(values []interface{}) error {
+	// Users created since the export was checked can still overflow the
+	// sheet.
+	if xw.row > excelize.TotalRows {
+		return ErrExportTooLarge.Wrapf("xlsx holds at most %d users", maxXLSXRows)
+	}
+	cell, err := excelize.CoordinatesToCellName(1, xw.row)
+	if err != nil {
+		return err
+	}
+	xw.row++
+	return xw.stream.SetRow(cell, values)
+}
+
+func (xw *xlsxExportWriter) Close() error {
+	if err := xw.stream.Flush(); err != nil {
+		return err
+	}
+	return xw.file.Write(xw.out)
+}
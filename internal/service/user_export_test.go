@@ -0,0 +1,136 @@
+package service
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/Pallavi566/Go-Backend/internal/apperr"
+	"github.com/Pallavi566/Go-Backend/internal/models"
+	"github.com/xuri/excelize/v2"
+)
+
+func exportTestUsers() []models.UserResponse {
+	age := 34
+	deletedAt := "2024-03-01T12:00:00Z"
+	return []models.UserResponse{
+		{ID: 1, Name: "Alice", DOB: "1990-05-10", Email: "alice@example.com", Timezone: "Europe/Paris", Age: &age, Version: 2},
+		{ID: 2, Name: "Smith, Bob", DOB: "1990-05-10", Age: &age, Version: 1, DeletedAt: &deletedAt},
+	}
+}
+
+func TestExportWriters(t *testing.T) {
+	tests := []struct {
+		name string
+		new  func(buf *bytes.Buffer) exportWriter
+		want string
+	}{
+		{
+			name: "CSV",
+			new:  func(buf *bytes.Buffer) exportWriter { return newCSVExportWriter(buf) },
+			want: "id,name,dob,email,phone,timezone,locale,age,version,deleted_at\n" +
+				"1,Alice,1990-05-10,alice@example.com,,Europe/Paris,,34,2,\n" +
+				"2,\"Smith, Bob\",1990-05-10,,,,,34,1,2024-03-01T12:00:00Z\n",
+		},
+		{
+			name: "NDJSON",
+			new:  func(buf *bytes.Buffer) exportWriter { return &ndjsonExportWriter{enc: newJSONEncoder(buf)} },
+			want: `{"id":1,"name":"Alice","dob":"1990-05-10","email":"alice@example.com","timezone":"Europe/Paris","age":34,"version":2}` + "\n" +
+				`{"id":2,"name":"Smith, Bob","dob":"1990-05-10","age":34,"version":1,"deleted_at":"2024-03-01T12:00:00Z"}` + "\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			w := tt.new(&buf)
+			for _, u := range exportTestUsers() {
+				if err := w.Write(u); err != nil {
+					t.Fatalf("Write() error = %v", err)
+				}
+			}
+			if err := w.Close(); err != nil {
+				t.Fatalf("Close() error = %v", err)
+			}
+			if got := buf.String(); got != tt.want {
+				t.Errorf("output = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestXLSXExportWriter(t *testing.T) {
+	var buf bytes.Buffer
+	w, err := newXLSXExportWriter(&buf)
+	if err != nil {
+		t.Fatalf("newXLSXExportWriter() error = %v", err)
+	}
+	defer w.file.Close()
+	for _, u := range exportTestUsers() {
+		if err := w.Write(u); err != nil {
+			t.Fatalf("Write() error = %v", err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	f, err := excelize.OpenReader(&buf)
+	if err != nil {
+		t.Fatalf("output is not a workbook: %v", err)
+	}
+	defer f.Close()
+	rows, err := f.GetRows("Sheet1")
+	if err != nil {
+		t.Fatalf("GetRows() error = %v", err)
+	}
+	if len(rows) != 3 {
+		t.Fatalf("got %d rows, want 3", len(rows))
+	}
+	if rows[0][0] != "id" || rows[2][1] != "Smith, Bob" || rows[1][3] != "alice@example.com" || rows[2][9] != "2024-03-01T12:00:00Z" {
+		t.Errorf("rows = %v", rows)
+	}
+}
+
+func TestCheckExportSize(t *testing.T) {
+	tests := []struct {
+		name    string
+		format  ExportFormat
+		n       int64
+		wantErr bool
+	}{
+		{"XLSX full sheet", ExportXLSX, 1048575, false},
+		{"XLSX one row over", ExportXLSX, 1048576, true},
+		{"CSV has no limit", ExportCSV, 1048576, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkExportSize(tt.format, tt.n)
+			if tt.wantErr != (err != nil) {
+				t.Fatalf("checkExportSize() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && !errors.Is(err, apperr.ErrUnprocessable) {
+				t.Errorf("error = %v, want an unprocessable error", err)
+			}
+		})
+	}
+}
+
+func TestXLSXExportWriterFullSheet(t *testing.T) {
+	var buf bytes.Buffer
+	w, err := newXLSXExportWriter(&buf)
+	if err != nil {
+		t.Fatalf("newXLSXExportWriter() error = %v", err)
+	}
+	defer w.file.Close()
+
+	// Skip ahead to the last row of the sheet.
+	w.row = excelize.TotalRows
+	users := exportTestUsers()
+	if err := w.Write(users[0]); err != nil {
+		t.Fatalf("Write() of the last row error = %v", err)
+	}
+	if err := w.Write(users[1]); !errors.Is(err, ErrExportTooLarge) {
+		t.Errorf("Write() past the last row error = %v, want ErrExportTooLarge", err)
+	}
+}
@@ -0,0 +1,66 @@
+package service
+
+import (
+	"time"
+
+	"github.com/Pallavi566/Go-Backend/internal/apperr"
+	"github.com/Pallavi566/Go-Backend/internal/age"
+	"github.com/Pallavi566/Go-Backend/internal/models"
+	"github.com/Pallavi566/Go-Backend/internal/repository"
+)
+
+// ErrInvalidFilter is returned when listing parameters cannot be applied.
+var ErrInvalidFilter = apperr.New(apperr.ErrValidation, "invalid_filter", "invalid filter")
+
+// buildUserFilter converts the query-level filter into a repository filter.
+// Age bounds are translated into date of birth bounds relative to today,
+// applying the leap day rule, and combined with any explicit
+// dob_from/dob_to, keeping the tighter bound.
+func buildUserFilter(f models.UserFilter, today time.Time, rule age.LeapDayRule) (repository.UserFilter, error) {
+	filter := repository.UserFilter{
+		NameContains:   f.NameContains,
+		IncludeDeleted: f.IncludeDeleted,
+	}
+
+	if f.DOBFrom != "" {
+		from, err := time.Parse("2006-01-02", f.DOBFrom)
+		if err != nil {
+			return filter, ErrInvalidFilter.Wrapf("dob_from must be YYYY-MM-DD")
+		}
+		filter.DOBFrom = &from
+	}
+	if f.DOBTo != "" {
+		to, err := time.Parse("2006-01-02", f.DOBTo)
+		if err != nil {
+			return filter, ErrInvalidFilter.Wrapf("dob_to must be YYYY-MM-DD")
+		}
+		filter.DOBTo = &to
+	}
+
+	if f.MinAge != nil && f.MaxAge != nil && *f.MinAge > *f.MaxAge {
+		return filter, ErrInvalidFilter.Wrapf("min_age must not exceed max_age")
+	}
+
+	// Anyone at least min_age years old was born on or before this date.
+	if f.MinAge != nil {
+		to := age.LatestBirthDate(today, *f.MinAge, rule)
+		if filter.DOBTo == nil || to.Before(*filter.DOBTo) {
+			filter.DOBTo = &to
+		}
+	}
+	// Anyone at most max_age years old has not yet reached max_age+1.
+	if f.MaxAge != nil {
+		from := age.LatestBirthDate(today, *f.MaxAge+1, rule).AddDate(0, 0, 1)
+		if filter.DOBFrom == nil || from.After(*filter.DOBFrom) {
+			filter.DOBFrom = &from
+		}
+	}
+
+	sort, err := repository.ParseSort(f.Sort)
+	if err != nil {
+		return filter, ErrInvalidFilter.Wrapf("%v", err)
+	}
+	filter.Sort = sort
+
+	return filter, nil
+}
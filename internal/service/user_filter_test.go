@@ -0,0 +1,180 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Pallavi566/Go-Backend/internal/age"
+	"github.com/Pallavi566/Go-Backend/internal/models"
+	"github.com/Pallavi566/Go-Backend/internal/repository"
+)
+
+func intPtr(v int) *int { return &v }
+
+func TestBuildUserFilter(t *testing.T) {
+	today := time.Date(2024, 6, 15, 0, 0, 0, 0, time.UTC)
+	date := func(y int, m time.Month, d int) time.Time {
+		return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+	}
+
+	tests := []struct {
+		name     string
+		input    models.UserFilter
+		wantFrom *time.Time
+		wantTo   *time.Time
+		wantSort []repository.SortField
+	}{
+		{
+			name:  "Empty filter",
+			input: models.UserFilter{},
+		},
+		{
+			name:     "Explicit date range",
+			input:    models.UserFilter{DOBFrom: "1990-01-01", DOBTo: "1999-12-31"},
+			wantFrom: ptrTime(date(1990, 1, 1)),
+			wantTo:   ptrTime(date(1999, 12, 31)),
+		},
+		{
+			name:     "Age range",
+			input:    models.UserFilter{MinAge: intPtr(18), MaxAge: intPtr(30)},
+			wantFrom: ptrTime(date(1993, 6, 16)),
+			wantTo:   ptrTime(date(2006, 6, 15)),
+		},
+		{
+			name:     "Age bound tighter than explicit date",
+			input:    models.UserFilter{DOBTo: "2010-01-01", MinAge: intPtr(18)},
+			wantTo:   ptrTime(date(2006, 6, 15)),
+			wantFrom: nil,
+		},
+		{
+			name:   "Explicit date tighter than age bound",
+			input:  models.UserFilter{DOBTo: "2000-01-01", MinAge: intPtr(18)},
+			wantTo: ptrTime(date(2000, 1, 1)),
+		},
+		{
+			name:  "Sort fields",
+			input: models.UserFilter{Sort: "-dob,name"},
+			wantSort: []repository.SortField{
+				{Column: "dob", Desc: true},
+				{Column: "name"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := buildUserFilter(tt.input, today, age.Feb28)
+			if err != nil {
+				t.Fatalf("buildUserFilter() error = %v", err)
+			}
+			if !equalTimePtr(got.DOBFrom, tt.wantFrom) {
+				t.Errorf("DOBFrom = %v, want %v", got.DOBFrom, tt.wantFrom)
+			}
+			if !equalTimePtr(got.DOBTo, tt.wantTo) {
+				t.Errorf("DOBTo = %v, want %v", got.DOBTo, tt.wantTo)
+			}
+			if len(got.Sort) != len(tt.wantSort) {
+				t.Fatalf("Sort = %v, want %v", got.Sort, tt.wantSort)
+			}
+			for i := range got.Sort {
+				if got.Sort[i] != tt.wantSort[i] {
+					t.Errorf("Sort[%d] = %v, want %v", i, got.Sort[i], tt.wantSort[i])
+				}
+			}
+		})
+	}
+}
+
+func TestBuildUserFilterLeapDay(t *testing.T) {
+	date := func(y int, m time.Month, d int) time.Time {
+		return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+	}
+	tests := []struct {
+		name     string
+		today    time.Time
+		rule     age.LeapDayRule
+		input    models.UserFilter
+		wantFrom *time.Time
+		wantTo   *time.Time
+	}{
+		{
+			// Someone born on 2020-02-29 turns 3 on 2023-02-28.
+			name:   "Min age on Feb 28, feb28 rule",
+			today:  date(2023, 2, 28),
+			rule:   age.Feb28,
+			input:  models.UserFilter{MinAge: intPtr(3)},
+			wantTo: ptrTime(date(2020, 2, 29)),
+		},
+		{
+			// ...but only on 2023-03-01 under the mar1 rule.
+			name:   "Min age on Feb 28, mar1 rule",
+			today:  date(2023, 2, 28),
+			rule:   age.Mar1,
+			input:  models.UserFilter{MinAge: intPtr(3)},
+			wantTo: ptrTime(date(2020, 2, 28)),
+		},
+		{
+			name:     "Max age on Feb 28, mar1 rule",
+			today:    date(2023, 2, 28),
+			rule:     age.Mar1,
+			input:    models.UserFilter{MaxAge: intPtr(2)},
+			wantFrom: ptrTime(date(2020, 2, 29)),
+		},
+		{
+			name:   "Min age on Feb 29",
+			today:  date(2024, 2, 29),
+			rule:   age.Feb28,
+			input:  models.UserFilter{MinAge: intPtr(1)},
+			wantTo: ptrTime(date(2023, 2, 28)),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := buildUserFilter(tt.input, tt.today, tt.rule)
+			if err != nil {
+				t.Fatalf("buildUserFilter() error = %v", err)
+			}
+			if !equalTimePtr(got.DOBFrom, tt.wantFrom) {
+				t.Errorf("DOBFrom = %v, want %v", got.DOBFrom, tt.wantFrom)
+			}
+			if !equalTimePtr(got.DOBTo, tt.wantTo) {
+				t.Errorf("DOBTo = %v, want %v", got.DOBTo, tt.wantTo)
+			}
+		})
+	}
+}
+
+func TestBuildUserFilterInvalid(t *testing.T) {
+	today := time.Date(2024, 6, 15, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name  string
+		input models.UserFilter
+	}{
+		{"Bad dob_from", models.UserFilter{DOBFrom: "15/06/2000"}},
+		{"Bad dob_to", models.UserFilter{DOBTo: "2000-13-01"}},
+		{"Min age above max age", models.UserFilter{MinAge: intPtr(40), MaxAge: intPtr(30)}},
+		{"Unknown sort field", models.UserFilter{Sort: "password"}},
+		{"Injected sort field", models.UserFilter{Sort: "name; DROP TABLE users"}},
+		{"Duplicate sort field", models.UserFilter{Sort: "name,-name"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := buildUserFilter(tt.input, today, age.Feb28)
+			if !errors.Is(err, ErrInvalidFilter) {
+				t.Errorf("buildUserFilter() error = %v, want ErrInvalidFilter", err)
+			}
+		})
+	}
+}
+
+func ptrTime(t time.Time) *time.Time { return &t }
+
+func equalTimePtr(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Equal(*b)
+}
@@ -0,0 +1,377 @@
+package service
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/Pallavi566/Go-Backend/internal/apperr"
+	"github.com/Pallavi566/Go-Backend/internal/models"
+	"github.com/Pallavi566/Go-Backend/internal/repository"
+	"github.com/Pallavi566/Go-Backend/internal/tracing"
+)
+
+// ImportFormat identifies the file format of a user import.
+type ImportFormat string
+
+const (
+	ImportCSV    ImportFormat = "csv"
+	ImportNDJSON ImportFormat = "ndjson"
+)
+
+// DefaultImportChunkSize is the number of rows inserted per transaction.
+const DefaultImportChunkSize = 500
+
+// ErrInvalidImport is returned when an import file cannot be read at all, as
+// opposed to individual rows being invalid.
+var ErrInvalidImport = apperr.New(apperr.ErrValidation, "invalid_import", "invalid import file")
+
+// ParseImportFormat parses a format name as given in a query parameter, a
+// CLI flag or a file extension.
+func ParseImportFormat(s string) (ImportFormat, error) {
+	switch strings.ToLower(strings.TrimPrefix(s, ".")) {
+	case "csv":
+		return ImportCSV, nil
+	case "ndjson", "jsonl":
+		return ImportNDJSON, nil
+	}
+	return "", ErrInvalidImport.Wrapf("unsupported format %q, expected csv or ndjson", s)
+}
+
+// ImportOptions controls an import.
+type ImportOptions struct {
+	// DryRun validates every row, and checks that its email is not taken,
+	// without writing anything.
+	DryRun bool
+	// ChunkSize is the number of rows inserted per transaction; zero means
+	// DefaultImportChunkSize.
+	ChunkSize int
+}
+
+// ValidateCreateUser checks a user as sent to create it and returns the
+// message to report, or "" when it is valid. It is shared by the create,
+// batch and import paths so that they accept exactly the same users.
+func ValidateCreateUser(ctx context.Context, req models.CreateUserRequest) string {
+	if err := CheckCreateUser(ctx, req); err != nil {
+		return err.Error()
+	}
+	return ""
+}
+
+// importRow is a valid row waiting to be inserted.
+type importRow struct {
+	line int
+	user models.User
+}
+
+// ImportUsers reads user rows from r and creates a user for each valid
+// one. Rows are streamed and inserted in chunks, each in its own
+// transaction; when a chunk fails to insert its rows are retried one by one,
+// so that only the rows that cannot be inserted are rejected, and the import
+// goes on. An email used by an earlier row of the file is rejected. The
+// report lists every rejected row by line number.
+func (s *UserService) ImportUsers(ctx context.Context, r io.Reader, format ImportFormat, opts ImportOptions) (_ *models.ImportReport, err error) {
+	ctx, span := tracing.Start(ctx, "UserService.ImportUsers")
+	defer tracing.End(span, &err)
+
+	if opts.ChunkSize <= 0 {
+		opts.ChunkSize = DefaultImportChunkSize
+	}
+	report := &models.ImportReport{
+		Format: string(format),
+		DryRun: opts.DryRun,
+		Errors: []models.ImportRowError{},
+	}
+
+	chunk := make([]importRow, 0, opts.ChunkSize)
+	var flushErr error
+	flush := func() {
+		if len(chunk) == 0 {
+			return
+		}
+		if opts.DryRun {
+			flushErr = s.checkChunk(ctx, chunk, report)
+		} else {
+			s.insertChunk(ctx, chunk, report)
+		}
+		chunk = chunk[:0]
+	}
+	// emails holds the line each email was first used on.
+	emails := make(map[string]int)
+
+	row := func(line int, req models.CreateUserRequest, parseErr error) error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		report.Rows++
+		msg := ""
+		if parseErr != nil {
+			msg = parseErr.Error()
+		} else {
+			msg = ValidateCreateUser(ctx, req)
+		}
+		user, _ := newUser(req)
+		if first, ok := emails[user.Email]; msg == "" && ok {
+			msg = fmt.Sprintf("email already used on line %d", first)
+		}
+		if msg != "" {
+			report.Failed++
+			report.Errors = append(report.Errors, models.ImportRowError{Line: line, Error: msg})
+			return nil
+		}
+
+		report.Valid++
+		if user.Email != "" {
+			emails[user.Email] = line
+		}
+		chunk = append(chunk, importRow{line: line, user: user})
+		if len(chunk) == opts.ChunkSize {
+			flush()
+		}
+		return flushErr
+	}
+
+	switch format {
+	case ImportCSV:
+		err = readCSV(r, row)
+	case ImportNDJSON:
+		err = readNDJSON(r, row)
+	default:
+		err = ErrInvalidImport.Wrapf("unsupported format %q", format)
+	}
+	if err != nil {
+		return nil, err
+	}
+	flush()
+	if flushErr != nil {
+		return nil, flushErr
+	}
+
+	// Rows rejected when their chunk was written come after the rows read
+	// since.
+	sort.SliceStable(report.Errors, func(i, j int) bool {
+		return report.Errors[i].Line < report.Errors[j].Line
+	})
+	return report, nil
+}
+
+// insertChunk creates the users of a chunk in a single transaction. When it
+// fails, each row is retried in its own transaction and only the rows that
+// fail again are rejected.
+func (s *UserService) insertChunk(ctx context.Context, chunk []importRow, report *models.ImportReport) {
+	err := s.repo.InTx(ctx, func(tx *repository.UserTx) error {
+		for _, row := range chunk {
+			if _, err := tx.Create(ctx, row.user); err != nil {
+				return fmt.Errorf("line %d: %w", row.line, err)
+			}
+		}
+		return nil
+	})
+	if err == nil {
+		report.Imported += len(chunk)
+		return
+	}
+
+	// Retrying would fail the same way.
+	retry := !errors.Is(err, repository.ErrDatabaseUnavailable) && ctx.Err() == nil
+	for _, row := range chunk {
+		rowErr := err
+		if retry {
+			if _, rowErr = s.repo.Create(ctx, row.user); rowErr == nil {
+				report.Imported++
+				continue
+			}
+		}
+		report.Failed++
+		report.Errors = append(report.Errors, models.ImportRowError{
+			Line:  row.line,
+			Error: "not imported: " + importError(rowErr),
+		})
+	}
+}
+
+// importError describes why a row could not be inserted. Database errors
+// are described by the apperr error they were translated to, without the
+// driver message they wrap.
+func importError(err error) string {
+	var appErr *apperr.Error
+	if errors.As(err, &appErr) {
+		return appErr.Detail
+	}
+	return err.Error()
+}
+
+// checkChunk rejects, for a dry run, the rows of a chunk whose email
+// already belongs to a user.
+func (s *UserService) checkChunk(ctx context.Context, chunk []importRow, report *models.ImportReport) error {
+	var emails []string
+	for _, row := range chunk {
+		if row.user.Email != "" {
+			emails = append(emails, row.user.Email)
+		}
+	}
+	if len(emails) == 0 {
+		return nil
+	}
+	existing, err := s.existingEmails(ctx, emails)
+	if err != nil {
+		return err
+	}
+	for _, row := range chunk {
+		if existing[row.user.Email] {
+			report.Valid--
+			report.Failed++
+			report.Errors = append(report.Errors, models.ImportRowError{
+				Line:  row.line,
+				Error: ErrDuplicateEmail.Error(),
+			})
+		}
+	}
+	return nil
+}
+
+// rowFunc receives each row of an import file with its line number, or the
+// reason the row could not be parsed. Returning an error stops the import.
+type rowFunc func(line int, req models.CreateUserRequest, err error) error
+
+// readCSV reads user records. A header row naming the columns is optional;
+// without one the columns are taken in name,dob order. The email, phone,
+// timezone and locale columns are only read when named in a header.
+func readCSV(r io.Reader, fn rowFunc) error {
+	cr := csv.NewReader(skipBOM(r))
+	cr.FieldsPerRecord = -1
+	cr.LazyQuotes = true
+	cr.TrimLeadingSpace = true
+
+	cols := csvColumns{name: 0, dob: 1, email: -1, phone: -1, timezone: -1, locale: -1}
+	first := true
+	for {
+		record, err := cr.Read()
+		if err == io.EOF {
+			return nil
+		}
+		var parseErr *csv.ParseError
+		if errors.As(err, &parseErr) {
+			if err := fn(parseErr.StartLine, models.CreateUserRequest{}, parseErr.Err); err != nil {
+				return err
+			}
+			continue
+		}
+		if err != nil {
+			return ErrInvalidImport.Wrapf("%v", err)
+		}
+		line, _ := cr.FieldPos(0)
+
+		if first {
+			first = false
+			if header, ok := csvHeader(record); ok {
+				cols = header
+				continue
+			}
+		}
+
+		if len(record) == 1 && strings.TrimSpace(record[0]) == "" {
+			continue
+		}
+		if cols.name >= len(record) || cols.dob >= len(record) {
+			if err := fn(line, models.CreateUserRequest{}, fmt.Errorf("expected name and dob columns, got %d", len(record))); err != nil {
+				return err
+			}
+			continue
+		}
+		req := models.CreateUserRequest{
+			Name:     csvField(record, cols.name),
+			DOB:      csvField(record, cols.dob),
+			Email:    csvField(record, cols.email),
+			Phone:    csvField(record, cols.phone),
+			Timezone: csvField(record, cols.timezone),
+			Locale:   csvField(record, cols.locale),
+		}
+		if err := fn(line, req, nil); err != nil {
+			return err
+		}
+	}
+}
+
+// csvColumns holds the index of each column of a CSV import, or -1 for
+// columns the file does not have.
+type csvColumns struct {
+	name, dob, email, phone, timezone, locale int
+}
+
+// csvHeader reports whether record is a header row, which must name at least
+// the name and dob columns, and if so where each column is.
+func csvHeader(record []string) (csvColumns, bool) {
+	cols := csvColumns{-1, -1, -1, -1, -1, -1}
+	for i, field := range record {
+		switch strings.ToLower(strings.TrimSpace(field)) {
+		case "name":
+			cols.name = i
+		case "dob":
+			cols.dob = i
+		case "email":
+			cols.email = i
+		case "phone":
+			cols.phone = i
+		case "timezone":
+			cols.timezone = i
+		case "locale":
+			cols.locale = i
+		}
+	}
+	return cols, cols.name >= 0 && cols.dob >= 0
+}
+
+// csvField returns the trimmed value of column i, or "" when the record does
+// not have it.
+func csvField(record []string, i int) string {
+	if i < 0 || i >= len(record) {
+		return ""
+	}
+	return strings.TrimSpace(record[i])
+}
+
+// readNDJSON reads one JSON object with name and dob per line. Blank lines
+// are skipped.
+func readNDJSON(r io.Reader, fn rowFunc) error {
+	scanner := bufio.NewScanner(skipBOM(r))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	line := 0
+	for scanner.Scan() {
+		line++
+		data := bytes.TrimSpace(scanner.Bytes())
+		if len(data) == 0 {
+			continue
+		}
+
+		var req models.CreateUserRequest
+		var parseErr error
+		if err := json.Unmarshal(data, &req); err != nil {
+			parseErr = fmt.Errorf("invalid JSON: %v", err)
+		}
+		if err := fn(line, req, parseErr); err != nil {
+			return err
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return ErrInvalidImport.Wrapf("line %d: %v", line+1, err)
+	}
+	return nil
+}
+
+// skipBOM drops the UTF-8 byte order mark spreadsheet tools like to prepend.
+func skipBOM(r io.Reader) io.Reader {
+	br := bufio.NewReader(r)
+	if b, err := br.Peek(3); err == nil && bytes.Equal(b, []byte{0xEF, 0xBB, 0xBF}) {
+		_, _ = br.Discard(3)
+	}
+	return br
+}
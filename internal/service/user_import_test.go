@@ -0,0 +1,141 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/Pallavi566/Go-Backend/internal/models"
+)
+
+func TestImportUsersDryRun(t *testing.T) {
+	tests := []struct {
+		name      string
+		format    ImportFormat
+		input     string
+		wantRows  int
+		wantValid int
+		wantLines []int
+	}{
+		{
+			name:      "CSV with header",
+			format:    ImportCSV,
+			input:     "\ufeffname,dob\nAlice,1990-05-10\nBob,10/05/1990\n,1991-01-01\n",
+			wantRows:  3,
+			wantValid: 1,
+			wantLines: []int{3, 4},
+		},
+		{
+			name:      "CSV without header",
+			format:    ImportCSV,
+			input:     "Alice,1990-05-10\n\nCarol\n\"Dave, Jr.\",1985-12-01\n",
+			wantRows:  3,
+			wantValid: 2,
+			wantLines: []int{3},
+		},
+		{
+			name:      "CSV header in other order",
+			format:    ImportCSV,
+			input:     "dob,name\n1990-05-10,Alice\n",
+			wantRows:  1,
+			wantValid: 1,
+		},
+		{
+			name:      "CSV with profile columns",
+			format:    ImportCSV,
+			input:     "name,email,dob,timezone\nAlice,alice@example.com,1990-05-10,Europe/Paris\nBob,not-an-email,1990-05-10,\nCarol,,1990-05-10,Mars/Olympus\n",
+			wantRows:  3,
+			wantValid: 1,
+			wantLines: []int{3, 4},
+		},
+		{
+			name:      "Email used twice",
+			format:    ImportCSV,
+			input:     "name,dob,email\nAlice,1990-05-10,alice@example.com\nAlicia,1991-05-10,ALICE@example.com\n",
+			wantRows:  2,
+			wantValid: 1,
+			wantLines: []int{3},
+		},
+		{
+			name:      "Email taken",
+			format:    ImportCSV,
+			input:     "name,dob,email\nAlice,1990-05-10,alice@example.com\nBob,,\nTaken,1991-05-10,taken@example.com\n",
+			wantRows:  3,
+			wantValid: 1,
+			wantLines: []int{3, 4},
+		},
+		{
+			name:      "NDJSON",
+			format:    ImportNDJSON,
+			input:     "{\"name\":\"Alice\",\"dob\":\"1990-05-10\"}\n\n{\"name\":\"Bob\"}\nnot json\n{\"name\":\"Eve\",\"dob\":\"2001-02-30\"}\n",
+			wantRows:  4,
+			wantValid: 1,
+			wantLines: []int{3, 4, 5},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &UserService{existingEmails: func(_ context.Context, emails []string) (map[string]bool, error) {
+				existing := make(map[string]bool)
+				for _, email := range emails {
+					existing[email] = email == "taken@example.com"
+				}
+				return existing, nil
+			}}
+			// Chunks of two rows make the taken email be found after the
+			// invalid row before it.
+			report, err := s.ImportUsers(context.Background(), strings.NewReader(tt.input), tt.format, ImportOptions{DryRun: true, ChunkSize: 2})
+			if err != nil {
+				t.Fatalf("ImportUsers() error = %v", err)
+			}
+			if report.Rows != tt.wantRows || report.Valid != tt.wantValid || report.Imported != 0 {
+				t.Errorf("report = %d rows, %d valid, %d imported; want %d, %d, 0",
+					report.Rows, report.Valid, report.Imported, tt.wantRows, tt.wantValid)
+			}
+			if report.Failed != len(tt.wantLines) {
+				t.Errorf("Failed = %d, want %d", report.Failed, len(tt.wantLines))
+			}
+			var lines []int
+			for _, e := range report.Errors {
+				lines = append(lines, e.Line)
+			}
+			if !equalInts(lines, tt.wantLines) {
+				t.Errorf("error lines = %v, want %v (%+v)", lines, tt.wantLines, report.Errors)
+			}
+		})
+	}
+}
+
+func TestParseImportFormat(t *testing.T) {
+	for in, want := range map[string]ImportFormat{"csv": ImportCSV, ".CSV": ImportCSV, "ndjson": ImportNDJSON, ".jsonl": ImportNDJSON} {
+		if got, err := ParseImportFormat(in); err != nil || got != want {
+			t.Errorf("ParseImportFormat(%q) = %q, %v; want %q", in, got, err, want)
+		}
+	}
+	if _, err := ParseImportFormat(".xlsx"); !errors.Is(err, ErrInvalidImport) {
+		t.Errorf("ParseImportFormat(.xlsx) error = %v, want ErrInvalidImport", err)
+	}
+}
+
+func TestValidateCreateUser(t *testing.T) {
+	if msg := ValidateCreateUser(context.Background(), models.CreateUserRequest{Name: "Alice", DOB: "1990-05-10"}); msg != "" {
+		t.Errorf("ValidateCreateUser(valid) = %q", msg)
+	}
+	if msg := ValidateCreateUser(context.Background(), models.CreateUserRequest{Name: "Alice", DOB: "1990-5-10"}); msg == "" {
+		t.Error("ValidateCreateUser(bad date) accepted")
+	}
+}
+
+func equalInts(a, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,51 @@
+package service
+
+import (
+	"context"
+	"strings"
+
+	"github.com/Pallavi566/Go-Backend/internal/apperr"
+)
+
+// ErrInvalidInclude is returned when ?include= names an unknown field.
+var ErrInvalidInclude = apperr.New(apperr.ErrValidation, "invalid_include", "invalid include")
+
+// Include selects the optional fields of user responses.
+type Include struct {
+	// AgeDetail adds the age broken down into years, months and days.
+	AgeDetail bool
+	// NextBirthday adds the date of the next birthday and the days until it.
+	NextBirthday bool
+}
+
+// ParseInclude parses a comma separated list of optional fields, as given in
+// the include query parameter.
+func ParseInclude(s string) (Include, error) {
+	var inc Include
+	for _, field := range strings.Split(s, ",") {
+		switch field = strings.TrimSpace(field); field {
+		case "":
+		case "age_detail":
+			inc.AgeDetail = true
+		case "next_birthday":
+			inc.NextBirthday = true
+		default:
+			return Include{}, ErrInvalidInclude.Wrapf("unknown field %q, expected age_detail or next_birthday", field)
+		}
+	}
+	return inc, nil
+}
+
+type includeKey struct{}
+
+// WithInclude returns a copy of ctx asking for the optional fields of inc in
+// the user responses built under it.
+func WithInclude(ctx context.Context, inc Include) context.Context {
+	return context.WithValue(ctx, includeKey{}, inc)
+}
+
+// includeFrom returns the optional fields requested in ctx, if any.
+func includeFrom(ctx context.Context) Include {
+	inc, _ := ctx.Value(includeKey{}).(Include)
+	return inc
+}
@@ -0,0 +1,67 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Pallavi566/Go-Backend/internal/age"
+	"github.com/Pallavi566/Go-Backend/internal/models"
+)
+
+func TestParseInclude(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    Include
+		wantErr bool
+	}{
+		{"", Include{}, false},
+		{"age_detail", Include{AgeDetail: true}, false},
+		{"age_detail, next_birthday", Include{AgeDetail: true, NextBirthday: true}, false},
+		{"next_birthday,", Include{NextBirthday: true}, false},
+		{"age_detail,horoscope", Include{}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseInclude(tt.input)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidInclude) {
+					t.Errorf("ParseInclude() error = %v, want ErrInvalidInclude", err)
+				}
+				return
+			}
+			if err != nil || got != tt.want {
+				t.Errorf("ParseInclude() = %+v, %v, want %+v", got, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewUserResponseInclude(t *testing.T) {
+	at := time.Date(2024, 6, 12, 12, 0, 0, 0, time.UTC)
+	s := &UserService{ages: age.NewCalculator(age.FixedClock(at), age.Feb28, time.UTC)}
+	u := &models.User{DOB: time.Date(2021, 2, 10, 0, 0, 0, 0, time.UTC)}
+
+	plain := s.newUserResponse(context.Background(), u)
+	if plain.AgeDetail != nil || plain.NextBirthday != nil || plain.DaysUntilBirthday != nil {
+		t.Fatalf("newUserResponse() without include = %+v, want no optional fields", plain)
+	}
+
+	ctx := WithInclude(context.Background(), Include{AgeDetail: true, NextBirthday: true})
+	got := s.newUserResponse(ctx, u)
+	wantDetail := models.AgeDetail{Years: 3, Months: 4, Days: 2, TotalDays: 1218}
+	if got.AgeDetail == nil || *got.AgeDetail != wantDetail {
+		t.Errorf("AgeDetail = %+v, want %+v", got.AgeDetail, wantDetail)
+	}
+	if got.AgeDetail != nil && got.Age != nil && got.AgeDetail.Years != *got.Age {
+		t.Errorf("AgeDetail.Years = %d, want Age %d", got.AgeDetail.Years, *got.Age)
+	}
+	if got.NextBirthday == nil || *got.NextBirthday != "2025-02-10" {
+		t.Errorf("NextBirthday = %v, want 2025-02-10", got.NextBirthday)
+	}
+	if got.DaysUntilBirthday == nil || *got.DaysUntilBirthday != 243 {
+		t.Errorf("DaysUntilBirthday = %v, want 243", got.DaysUntilBirthday)
+	}
+}
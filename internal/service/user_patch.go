@@ -0,0 +1,151 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	jsonpatch "github.com/evanphx/json-patch/v5"
+	"github.com/Pallavi566/Go-Backend/internal/apperr"
+	"github.com/Pallavi566/Go-Backend/internal/models"
+	"github.com/Pallavi566/Go-Backend/internal/tracing"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// PatchFormat identifies the format of a PATCH request body.
+type PatchFormat int
+
+const (
+	// MergePatch is a JSON Merge Patch document (RFC 7396).
+	MergePatch PatchFormat = iota
+	// JSONPatch is a JSON Patch operation list (RFC 6902).
+	JSONPatch
+)
+
+var (
+	// ErrInvalidPatch is returned when the patch document itself is malformed.
+	ErrInvalidPatch = apperr.New(apperr.ErrValidation, "invalid_patch", "invalid patch document")
+	// ErrUnprocessablePatch is returned when a well-formed patch cannot be
+	// applied or yields an invalid user.
+	ErrUnprocessablePatch = apperr.New(apperr.ErrUnprocessable, "unprocessable_patch", "patch cannot be applied")
+)
+
+// patchDocument is the JSON representation patches are applied to. ID and
+// Version are exposed so JSON Patch "test" operations can check them, but
+// they are read-only. Unset profile fields are left out, so that removing or
+// nulling one clears it.
+type patchDocument struct {
+	ID       int    `json:"id"`
+	Name     string `json:"name" validate:"required,min=1,max=255"`
+	DOB      string `json:"dob" validate:"required"`
+	Email    string `json:"email,omitempty" validate:"omitempty,email,max=254"`
+	Phone    string `json:"phone,omitempty" validate:"omitempty,e164"`
+	Timezone string `json:"timezone,omitempty" validate:"omitempty,timezone"`
+	Locale   string `json:"locale,omitempty" validate:"omitempty,bcp47_language_tag,max=35"`
+	Version  int    `json:"version"`
+}
+
+// PatchUser applies a merge patch or JSON patch to the user, validates the
+// resulting document and saves it, all within one transaction.
+func (s *UserService) PatchUser(ctx context.Context, id int, format PatchFormat, patch []byte, ifMatch []int) (_ *models.UserResponse, err error) {
+	ctx, span := tracing.Start(ctx, "UserService.PatchUser", attribute.Int("user.id", id))
+	defer tracing.End(span, &err)
+
+	apply, err := compilePatch(format, patch)
+	if err != nil {
+		return nil, err
+	}
+
+	updated, err := s.repo.UpdateInTx(ctx, id, func(u *models.User) error {
+		if !versionMatches(u.Version, ifMatch) {
+			return ErrPreconditionFailed
+		}
+		return applyPatch(ctx, u, apply)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	response := s.newUserResponse(ctx, updated)
+	return &response, nil
+}
+
+// compilePatch checks that the patch is well-formed and returns a function
+// applying it to a JSON document.
+func compilePatch(format PatchFormat, patch []byte) (func(doc []byte) ([]byte, error), error) {
+	switch format {
+	case MergePatch:
+		var obj map[string]json.RawMessage
+		if err := json.Unmarshal(patch, &obj); err != nil {
+			return nil, ErrInvalidPatch.Wrapf("merge patch must be a JSON object")
+		}
+		return func(doc []byte) ([]byte, error) {
+			return jsonpatch.MergePatch(doc, patch)
+		}, nil
+	case JSONPatch:
+		ops, err := jsonpatch.DecodePatch(patch)
+		if err != nil {
+			return nil, ErrInvalidPatch.Wrapf("%v", err)
+		}
+		return ops.Apply, nil
+	}
+	return nil, ErrInvalidPatch.Wrapf("unsupported patch format")
+}
+
+// applyPatch patches u in place. The patched document must still describe
+// the same user and pass the same rules as a full update.
+func applyPatch(ctx context.Context, u *models.User, apply func(doc []byte) ([]byte, error)) error {
+	doc, err := json.Marshal(patchDocument{
+		ID:       u.ID,
+		Name:     u.Name,
+		DOB:      u.DOB.Format("2006-01-02"),
+		Email:    u.Email,
+		Phone:    u.Phone,
+		Timezone: u.Timezone,
+		Locale:   u.Locale,
+		Version:  u.Version,
+	})
+	if err != nil {
+		return err
+	}
+
+	patched, err := apply(doc)
+	if err != nil {
+		return ErrUnprocessablePatch.Wrapf("%v", err)
+	}
+
+	var result patchDocument
+	dec := json.NewDecoder(bytes.NewReader(patched))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&result); err != nil {
+		return ErrUnprocessablePatch.Wrapf("%v", err)
+	}
+
+	if result.ID != u.ID {
+		return ErrUnprocessablePatch.Wrapf("id is read-only")
+	}
+	if result.Version != u.Version {
+		return ErrUnprocessablePatch.Wrapf("version is read-only")
+	}
+	if err := validate.Struct(result); err != nil {
+		if fields := fieldErrors(ctx, err); fields != nil {
+			return ErrUnprocessablePatch.WithDetail(fmt.Sprintf("%s: %s", ErrUnprocessablePatch, fieldsDetail(fields))).WithFields(fields...)
+		}
+		return ErrUnprocessablePatch.Wrapf("%v", err)
+	}
+	dob, err := time.Parse("2006-01-02", result.DOB)
+	if err != nil {
+		field := dobFieldError(ctx)
+		return ErrUnprocessablePatch.Wrapf("%s", field.Message).WithFields(field)
+	}
+
+	u.Name = result.Name
+	u.DOB = dob
+	u.Email = normalizeEmail(result.Email)
+	u.Phone = result.Phone
+	u.Timezone = result.Timezone
+	u.Locale = result.Locale
+	return nil
+}
@@ -0,0 +1,134 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/Pallavi566/Go-Backend/internal/apperr"
+	"github.com/Pallavi566/Go-Backend/internal/i18n"
+	"github.com/Pallavi566/Go-Backend/internal/models"
+)
+
+func TestApplyPatch(t *testing.T) {
+	tests := []struct {
+		name     string
+		format   PatchFormat
+		patch    string
+		wantName string
+		wantDOB  string
+	}{
+		{
+			name:     "Merge patch name only",
+			format:   MergePatch,
+			patch:    `{"name":"Bob"}`,
+			wantName: "Bob",
+			wantDOB:  "1990-05-10",
+		},
+		{
+			name:     "Merge patch dob only",
+			format:   MergePatch,
+			patch:    `{"dob":"1991-01-02"}`,
+			wantName: "Alice",
+			wantDOB:  "1991-01-02",
+		},
+		{
+			name:     "JSON patch with test",
+			format:   JSONPatch,
+			patch:    `[{"op":"test","path":"/version","value":3},{"op":"replace","path":"/name","value":"Carol"}]`,
+			wantName: "Carol",
+			wantDOB:  "1990-05-10",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u := &models.User{ID: 1, Name: "Alice", DOB: time.Date(1990, 5, 10, 0, 0, 0, 0, time.UTC), Version: 3}
+			apply, err := compilePatch(tt.format, []byte(tt.patch))
+			if err != nil {
+				t.Fatalf("compilePatch() error = %v", err)
+			}
+			if err := applyPatch(context.Background(), u, apply); err != nil {
+				t.Fatalf("applyPatch() error = %v", err)
+			}
+			if u.Name != tt.wantName {
+				t.Errorf("Name = %q, want %q", u.Name, tt.wantName)
+			}
+			if got := u.DOB.Format("2006-01-02"); got != tt.wantDOB {
+				t.Errorf("DOB = %q, want %q", got, tt.wantDOB)
+			}
+		})
+	}
+}
+
+func TestApplyPatchProfile(t *testing.T) {
+	u := &models.User{ID: 1, Name: "Alice", DOB: time.Date(1990, 5, 10, 0, 0, 0, 0, time.UTC), Phone: "+14155550123", Version: 3}
+
+	apply, err := compilePatch(MergePatch, []byte(`{"email":"Alice@Example.com","phone":null,"timezone":"Europe/Paris","locale":"fr-FR"}`))
+	if err != nil {
+		t.Fatalf("compilePatch() error = %v", err)
+	}
+	if err := applyPatch(context.Background(), u, apply); err != nil {
+		t.Fatalf("applyPatch() error = %v", err)
+	}
+	if u.Email != "alice@example.com" || u.Phone != "" || u.Timezone != "Europe/Paris" || u.Locale != "fr-FR" {
+		t.Errorf("profile = %q, %q, %q, %q", u.Email, u.Phone, u.Timezone, u.Locale)
+	}
+}
+
+func TestApplyPatchErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		format  PatchFormat
+		patch   string
+		wantErr error
+	}{
+		{"Merge patch not an object", MergePatch, `["name"]`, ErrInvalidPatch},
+		{"JSON patch not a list", JSONPatch, `{"op":"replace"}`, ErrInvalidPatch},
+		{"Merge patch removes name", MergePatch, `{"name":null}`, ErrUnprocessablePatch},
+		{"Merge patch bad date", MergePatch, `{"dob":"10/05/1990"}`, ErrUnprocessablePatch},
+		{"Merge patch unknown field", MergePatch, `{"nickname":"Al"}`, ErrUnprocessablePatch},
+		{"Merge patch bad email", MergePatch, `{"email":"alice"}`, ErrUnprocessablePatch},
+		{"Merge patch bad phone", MergePatch, `{"phone":"555-0123"}`, ErrUnprocessablePatch},
+		{"Merge patch bad timezone", MergePatch, `{"timezone":"Mars/Olympus"}`, ErrUnprocessablePatch},
+		{"Merge patch changes id", MergePatch, `{"id":2}`, ErrUnprocessablePatch},
+		{"JSON patch failing test", JSONPatch, `[{"op":"test","path":"/version","value":2}]`, ErrUnprocessablePatch},
+		{"JSON patch missing path", JSONPatch, `[{"op":"remove","path":"/age"}]`, ErrUnprocessablePatch},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u := &models.User{ID: 1, Name: "Alice", DOB: time.Date(1990, 5, 10, 0, 0, 0, 0, time.UTC), Version: 3}
+			apply, err := compilePatch(tt.format, []byte(tt.patch))
+			if err == nil {
+				err = applyPatch(context.Background(), u, apply)
+			}
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("error = %v, want %v", err, tt.wantErr)
+			}
+			if u.Name != "Alice" && tt.wantErr != nil {
+				t.Errorf("user modified on error: %+v", u)
+			}
+		})
+	}
+}
+
+func TestApplyPatchBadDate(t *testing.T) {
+	u := &models.User{ID: 1, Name: "Alice", DOB: time.Date(1990, 5, 10, 0, 0, 0, 0, time.UTC), Version: 3}
+	apply, err := compilePatch(MergePatch, []byte(`{"dob":"10/05/1990"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = applyPatch(i18n.WithLanguage(context.Background(), "es"), u, apply)
+
+	want := []apperr.FieldError{{Field: "dob", Code: "date", Param: "YYYY-MM-DD", Message: "dob debe ser una fecha con el formato AAAA-MM-DD"}}
+	var appErr *apperr.Error
+	if !errors.As(err, &appErr) || !errors.Is(err, ErrUnprocessablePatch) {
+		t.Fatalf("error = %v, want ErrUnprocessablePatch", err)
+	}
+	if !reflect.DeepEqual(appErr.Fields, want) {
+		t.Errorf("fields = %+v, want %+v", appErr.Fields, want)
+	}
+}
@@ -2,56 +2,110 @@ package service
 
 import (
 	"context"
+	"errors"
+	"strings"
 	"time"
 
+	"github.com/Pallavi566/Go-Backend/internal/age"
+	"github.com/Pallavi566/Go-Backend/internal/apperr"
 	"github.com/Pallavi566/Go-Backend/internal/models"
 	"github.com/Pallavi566/Go-Backend/internal/repository"
+	"github.com/Pallavi566/Go-Backend/internal/tracing"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+var (
+	// ErrUserNotFound is returned when the user does not exist.
+	ErrUserNotFound = repository.ErrUserNotFound
+	// ErrDuplicateEmail is returned when another user already has the email
+	// address.
+	ErrDuplicateEmail = repository.ErrDuplicateEmail
+	// ErrPreconditionFailed is returned when an If-Match version does not
+	// match the user's current version.
+	ErrPreconditionFailed = apperr.New(apperr.ErrPrecondition, "precondition_failed", "user version precondition failed")
+	// ErrConcurrentUpdate is returned when the user changed between being
+	// read and written and the caller did not supply an expected version.
+	ErrConcurrentUpdate = apperr.New(apperr.ErrConflict, "concurrent_update", "user was modified concurrently")
 )
 
 type UserService struct {
 	repo repository.UserRepository
+	ages *age.Calculator
+	// existingEmails is repo.ExistingEmails, replaced in tests.
+	existingEmails func(ctx context.Context, emails []string) (map[string]bool, error)
 }
 
-func NewUserService(repo repository.UserRepository) *UserService {
-	return &UserService{repo: repo}
+// NewUserService returns a service that computes user ages with ages.
+func NewUserService(repo repository.UserRepository, ages *age.Calculator) *UserService {
+	return &UserService{repo: repo, ages: ages, existingEmails: repo.ExistingEmails}
 }
 
-func (s *UserService) CreateUser(ctx context.Context, req models.CreateUserRequest) (*models.UserResponse, error) {
-	dob, err := time.Parse("2006-01-02", req.DOB)
+func (s *UserService) CreateUser(ctx context.Context, req models.CreateUserRequest) (_ *models.UserResponse, err error) {
+	ctx, span := tracing.Start(ctx, "UserService.CreateUser")
+	defer tracing.End(span, &err)
+
+	user, err := newUser(req)
 	if err != nil {
 		return nil, err
 	}
 
-	id, err := s.repo.Create(ctx, req.Name, dob)
+	id, err := s.repo.Create(ctx, user)
 	if err != nil {
 		return nil, err
 	}
 
-	age := calculateAge(dob)
-	return &models.UserResponse{
-		ID:   int(id),
-		Name: req.Name,
-		DOB:  dob.Format("2006-01-02"),
-		Age:  &age,
-	}, nil
+	user.ID = int(id)
+	user.Version = 1
+	response := s.newUserResponse(ctx, &user)
+	return &response, nil
 }
 
-func (s *UserService) GetUserByID(ctx context.Context, id int) (*models.UserResponse, error) {
+func (s *UserService) GetUserByID(ctx context.Context, id int) (_ *models.UserResponse, err error) {
+	ctx, span := tracing.Start(ctx, "UserService.GetUserByID", attribute.Int("user.id", id))
+	defer tracing.End(span, &err)
+
 	user, err := s.repo.GetByID(ctx, id)
 	if err != nil {
 		return nil, err
 	}
 
-	age := calculateAge(user.DOB)
-	return &models.UserResponse{
-		ID:   user.ID,
-		Name: user.Name,
-		DOB:  user.DOB.Format("2006-01-02"),
-		Age:  &age,
-	}, nil
+	response := s.newUserResponse(ctx, user)
+	return &response, nil
+}
+
+// GetUserByEmail looks up an active user by email address, ignoring case.
+func (s *UserService) GetUserByEmail(ctx context.Context, email string) (_ *models.UserResponse, err error) {
+	ctx, span := tracing.Start(ctx, "UserService.GetUserByEmail")
+	defer tracing.End(span, &err)
+
+	user, err := s.repo.GetByEmail(ctx, normalizeEmail(email))
+	if err != nil {
+		return nil, err
+	}
+
+	response := s.newUserResponse(ctx, user)
+	return &response, nil
 }
 
-func (s *UserService) GetAllUsers(ctx context.Context) ([]models.UserResponse, error) {
+// GetUserByIDAsOf returns the user as it was at the given instant, with the
+// age computed as of that instant too.
+func (s *UserService) GetUserByIDAsOf(ctx context.Context, id int, at time.Time) (_ *models.UserResponse, err error) {
+	ctx, span := tracing.Start(ctx, "UserService.GetUserByIDAsOf", attribute.Int("user.id", id))
+	defer tracing.End(span, &err)
+
+	user, err := s.repo.GetAsOf(ctx, id, at)
+	if err != nil {
+		return nil, err
+	}
+
+	response := s.newUserResponseAt(ctx, user, at)
+	return &response, nil
+}
+
+func (s *UserService) GetAllUsers(ctx context.Context) (_ []models.UserResponse, err error) {
+	ctx, span := tracing.Start(ctx, "UserService.GetAllUsers")
+	defer tracing.End(span, &err)
+
 	users, err := s.repo.GetAll(ctx)
 	if err != nil {
 		return nil, err
@@ -59,108 +113,315 @@ func (s *UserService) GetAllUsers(ctx context.Context) ([]models.UserResponse, e
 
 	var response []models.UserResponse
 	for _, u := range users {
-		age := calculateAge(u.DOB)
-		response = append(response, models.UserResponse{
-			ID:   u.ID,
-			Name: u.Name,
-			DOB:  u.DOB.Format("2006-01-02"),
-			Age:  &age,
-		})
+		response = append(response, s.newUserResponse(ctx, u))
 	}
 
 	return response, nil
 }
 
-func (s *UserService) UpdateUser(ctx context.Context, id int, req models.UpdateUserRequest) (*models.UserResponse, error) {
-    // Get the existing user to preserve fields not being updated
-    existingUser, err := s.repo.GetByID(ctx, id)
-    if err != nil {
-        return nil, err
-    }
-
-    // Use existing values if not provided in request
-    name := existingUser.Name
-    if req.Name != "" {
-        name = req.Name
-    }
-
-    // Parse DOB if provided, otherwise use existing DOB
-    var dob time.Time
-    if req.DOB != "" {
-        dob, err = time.Parse("2006-01-02", req.DOB)
-        if err != nil {
-            return nil, err
-        }
-    } else {
-        dob = existingUser.DOB
-    }
-
-    // Update the user
-    err = s.repo.Update(ctx, id, name, dob)
-    if err != nil {
-        return nil, err
-    }
-
-    // Get the updated user to ensure we return the latest data
-    updatedUser, err := s.repo.GetByID(ctx, id)
-    if err != nil {
-        return nil, err
-    }
-
-    age := calculateAge(updatedUser.DOB)
-    return &models.UserResponse{
-        ID:   updatedUser.ID,
-        Name: updatedUser.Name,
-        DOB:  updatedUser.DOB.Format("2006-01-02"),
-        Age:  &age,
-    }, nil
-}
-
-func (s *UserService) DeleteUser(ctx context.Context, id int) error {
-	return s.repo.Delete(ctx, id)
-}
-
-func (s *UserService) GetUsersPaginated(ctx context.Context, page, limit int) (*models.PaginatedResponse, error) {
+// UpdateUser updates the user. ifMatch lists the versions the caller accepts
+// as current; nil means any version.
+func (s *UserService) UpdateUser(ctx context.Context, id int, req models.UpdateUserRequest, ifMatch []int) (_ *models.UserResponse, err error) {
+	ctx, span := tracing.Start(ctx, "UserService.UpdateUser", attribute.Int("user.id", id))
+	defer tracing.End(span, &err)
+
+	// Get the existing user to preserve fields not being updated
+	existingUser, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if !versionMatches(existingUser.Version, ifMatch) {
+		return nil, ErrPreconditionFailed
+	}
+
+	// Use existing values if not provided in request
+	name := existingUser.Name
+	if req.Name != "" {
+		name = req.Name
+	}
+
+	// Parse DOB if provided, otherwise use existing DOB
+	var dob time.Time
+	if req.DOB != "" {
+		dob, err = time.Parse("2006-01-02", req.DOB)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		dob = existingUser.DOB
+	}
+
+	updated := *existingUser
+	updated.Name = name
+	updated.DOB = dob
+	setProfile(&updated, req.Email, req.Phone, req.Timezone, req.Locale)
+
+	// Update the user, guarded by the version we read
+	err = s.repo.Update(ctx, updated, existingUser.Version)
+	if err != nil {
+		return nil, versionError(err, ifMatch)
+	}
+
+	// Get the updated user to ensure we return the latest data
+	updatedUser, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	response := s.newUserResponse(ctx, updatedUser)
+	return &response, nil
+}
+
+// DeleteUser deletes the user. When ifMatch is non-nil the user is only
+// deleted if its current version is one of the listed versions.
+func (s *UserService) DeleteUser(ctx context.Context, id int, ifMatch []int) (err error) {
+	ctx, span := tracing.Start(ctx, "UserService.DeleteUser", attribute.Int("user.id", id))
+	defer tracing.End(span, &err)
+
+	if ifMatch == nil {
+		return s.repo.Delete(ctx, id, 0)
+	}
+
+	user, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if !versionMatches(user.Version, ifMatch) {
+		return ErrPreconditionFailed
+	}
+
+	return versionError(s.repo.Delete(ctx, id, user.Version), ifMatch)
+}
+
+// GetUserHistory returns the recorded changes of a user, oldest first.
+func (s *UserService) GetUserHistory(ctx context.Context, id int) (_ []models.UserHistoryEntry, err error) {
+	ctx, span := tracing.Start(ctx, "UserService.GetUserHistory", attribute.Int("user.id", id))
+	defer tracing.End(span, &err)
+
+	entries, err := s.repo.GetHistory(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if len(entries) == 0 {
+		// Users created before history was recorded have no entries yet.
+		if _, err := s.repo.GetByID(ctx, id); err != nil {
+			return nil, err
+		}
+	}
+	return entries, nil
+}
+
+// RestoreUser undoes a soft delete and returns the restored user.
+func (s *UserService) RestoreUser(ctx context.Context, id int) (_ *models.UserResponse, err error) {
+	ctx, span := tracing.Start(ctx, "UserService.RestoreUser", attribute.Int("user.id", id))
+	defer tracing.End(span, &err)
+
+	if err := s.repo.Restore(ctx, id); err != nil {
+		return nil, err
+	}
+	return s.GetUserByID(ctx, id)
+}
+
+// PurgeDeletedUsers hard-deletes users that were soft-deleted more than
+// retention ago and returns how many were removed.
+func (s *UserService) PurgeDeletedUsers(ctx context.Context, retention time.Duration) (_ int64, err error) {
+	ctx, span := tracing.Start(ctx, "UserService.PurgeDeletedUsers")
+	defer tracing.End(span, &err)
+
+	return s.repo.Purge(ctx, time.Now().Add(-retention))
+}
+
+func (s *UserService) GetUsersPaginated(ctx context.Context, page, limit int, f models.UserFilter) (_ *models.PaginatedResponse, err error) {
+	ctx, span := tracing.Start(ctx, "UserService.GetUsersPaginated")
+	defer tracing.End(span, &err)
+
+	filter, err := buildUserFilter(f, s.ages.Today(""), s.ages.Rule())
+	if err != nil {
+		return nil, err
+	}
+
 	offset := (page - 1) * limit
-	users, err := s.repo.GetPaginated(ctx, limit, offset)
+	users, err := s.repo.GetPaginated(ctx, filter, limit, offset)
 	if err != nil {
 		return nil, err
 	}
 
-	total, err := s.repo.Count(ctx)
+	total, err := s.repo.Count(ctx, filter)
 	if err != nil {
 		return nil, err
 	}
 
 	var response []models.UserResponse
 	for _, u := range users {
-		age := calculateAge(u.DOB)
-		response = append(response, models.UserResponse{
-			ID:   u.ID,
-			Name: u.Name,
-			DOB:  u.DOB.Format("2006-01-02"),
-			Age:  &age,
-		})
+		response = append(response, s.newUserResponse(ctx, u))
 	}
 
+	totalPages := (int(total) + limit - 1) / limit
 	return &models.PaginatedResponse{
 		Data:       response,
-		Total:      total,
+		Total:      &total,
 		Page:       page,
 		Limit:      limit,
-		TotalPages: (int(total) + limit - 1) / limit,
+		TotalPages: &totalPages,
+	}, nil
+}
+
+// GetUsersByCursor pages through users by keyset instead of offset. An empty
+// cursor starts from the beginning. No total is computed; NextCursor is set
+// only when more rows follow.
+func (s *UserService) GetUsersByCursor(ctx context.Context, cursor string, limit int, f models.UserFilter) (_ *models.PaginatedResponse, err error) {
+	ctx, span := tracing.Start(ctx, "UserService.GetUsersByCursor")
+	defer tracing.End(span, &err)
+
+	filter, err := buildUserFilter(f, s.ages.Today(""), s.ages.Rule())
+	if err != nil {
+		return nil, err
+	}
+	sort := repository.FormatSort(filter.Sort)
+
+	if cursor != "" {
+		token, err := decodeCursor(cursor)
+		if err != nil {
+			return nil, err
+		}
+		if token.Sort != sort {
+			return nil, ErrInvalidCursor
+		}
+		filter.After = token.Key
+	}
+
+	// Fetch one extra row to learn whether another page follows.
+	users, err := s.repo.GetAfter(ctx, filter, limit+1)
+	if err != nil {
+		if errors.Is(err, repository.ErrInvalidKeyset) {
+			return nil, ErrInvalidCursor
+		}
+		return nil, err
+	}
+
+	var next *string
+	if len(users) > limit {
+		users = users[:limit]
+		c := encodeCursor(cursorToken{Sort: sort, Key: filter.KeyOf(users[len(users)-1])})
+		next = &c
+	}
+
+	var response []models.UserResponse
+	for _, u := range users {
+		response = append(response, s.newUserResponse(ctx, u))
+	}
+
+	return &models.PaginatedResponse{
+		Data:       response,
+		Limit:      limit,
+		NextCursor: next,
 	}, nil
 }
 
-// calculateAge calculates age from date of birth
-func calculateAge(dob time.Time) int {
-	now := time.Now()
-	years := now.Year() - dob.Year()
+// newUserResponse builds the API representation of u, computing its current
+// age and the optional fields requested in ctx.
+func (s *UserService) newUserResponse(ctx context.Context, u *models.User) models.UserResponse {
+	return s.newUserResponseAt(ctx, u, s.ages.Now())
+}
+
+// newUserResponseAt builds the API representation of u with its age, and
+// the optional fields requested in ctx, as of the given instant in the
+// user's time zone.
+func (s *UserService) newUserResponseAt(ctx context.Context, u *models.User, at time.Time) models.UserResponse {
+	age := s.ages.AgeAt(u.DOB, at, u.Timezone)
+	response := models.UserResponse{
+		ID:       u.ID,
+		Name:     u.Name,
+		DOB:      u.DOB.Format("2006-01-02"),
+		Email:    u.Email,
+		Phone:    u.Phone,
+		Timezone: u.Timezone,
+		Locale:   u.Locale,
+		Age:      &age,
+		Version:  u.Version,
+	}
+	if u.DeletedAt != nil {
+		deletedAt := u.DeletedAt.UTC().Format(time.RFC3339)
+		response.DeletedAt = &deletedAt
+	}
+
+	include := includeFrom(ctx)
+	if include.AgeDetail {
+		d := s.ages.DetailAt(u.DOB, at, u.Timezone)
+		response.AgeDetail = &models.AgeDetail{
+			Years:     d.Years,
+			Months:    d.Months,
+			Days:      d.Days,
+			TotalDays: d.TotalDays,
+		}
+	}
+	if include.NextBirthday {
+		next, days := s.ages.NextBirthdayAt(u.DOB, at, u.Timezone)
+		nextBirthday := next.Format("2006-01-02")
+		response.NextBirthday = &nextBirthday
+		response.DaysUntilBirthday = &days
+	}
+	return response
+}
+
+// newUser builds the user described by a validated create request.
+func newUser(req models.CreateUserRequest) (models.User, error) {
+	dob, err := time.Parse("2006-01-02", req.DOB)
+	if err != nil {
+		return models.User{}, err
+	}
+	user := models.User{Name: req.Name, DOB: dob}
+	setProfile(&user, req.Email, req.Phone, req.Timezone, req.Locale)
+	return user, nil
+}
+
+// setProfile sets the profile fields of u that are given, leaving the
+// others unchanged.
+func setProfile(u *models.User, email, phone, timezone, locale string) {
+	if email != "" {
+		u.Email = normalizeEmail(email)
+	}
+	if phone != "" {
+		u.Phone = phone
+	}
+	if timezone != "" {
+		u.Timezone = timezone
+	}
+	if locale != "" {
+		u.Locale = locale
+	}
+}
+
+// normalizeEmail returns the form emails are stored and looked up in.
+// Addresses are compared case-insensitively.
+func normalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
 
-	// If birthday hasn't occurred yet this year, subtract one year
-	if now.YearDay() < dob.YearDay() {
-		years--
+// versionMatches reports whether current is one of the accepted versions.
+// A nil list accepts any version.
+func versionMatches(current int, accepted []int) bool {
+	if accepted == nil {
+		return true
+	}
+	for _, v := range accepted {
+		if v == current {
+			return true
+		}
 	}
+	return false
+}
 
-	return years
+// versionError translates a repository version conflict into the error the
+// caller should see: a failed precondition when it asked for a specific
+// version, a concurrent update otherwise.
+func versionError(err error, ifMatch []int) error {
+	if !errors.Is(err, repository.ErrVersionConflict) {
+		return err
+	}
+	if ifMatch != nil {
+		return ErrPreconditionFailed
+	}
+	return ErrConcurrentUpdate
 }
@@ -1,77 +1,67 @@
 package service
 
 import (
+	"context"
 	"testing"
 	"time"
+
+	"github.com/Pallavi566/Go-Backend/internal/age"
+	"github.com/Pallavi566/Go-Backend/internal/models"
 )
 
-func TestCalculateAge(t *testing.T) {
+func TestNewUserResponseAge(t *testing.T) {
+	// 2024-05-10 03:00 UTC is still May 9 in New York.
+	at := time.Date(2024, 5, 10, 3, 0, 0, 0, time.UTC)
+	s := &UserService{ages: age.NewCalculator(age.FixedClock(at), age.Feb28, time.UTC)}
 	tests := []struct {
 		name     string
-		dob      time.Time
+		user     models.User
 		expected int
 	}{
-		{
-			name:     "Birthday this year already passed",
-			dob:      time.Date(1990, 5, 10, 0, 0, 0, 0, time.UTC),
-			expected: calculateAge(time.Date(1990, 5, 10, 0, 0, 0, 0, time.UTC)),
-		},
-		{
-			name:     "Birthday this year not yet passed",
-			dob:      time.Date(2000, 12, 31, 0, 0, 0, 0, time.UTC),
-			expected: calculateAge(time.Date(2000, 12, 31, 0, 0, 0, 0, time.UTC)),
-		},
-		{
-			name:     "Born today",
-			dob:      time.Now(),
-			expected: 0,
-		},
-		{
-			name:     "Born one year ago",
-			dob:      time.Now().AddDate(-1, 0, 0),
-			expected: 1,
-		},
+		{"Birthday in default time zone", models.User{DOB: time.Date(1990, 5, 10, 0, 0, 0, 0, time.UTC)}, 34},
+		{"Birthday not yet reached in user's time zone", models.User{DOB: time.Date(1990, 5, 10, 0, 0, 0, 0, time.UTC), Timezone: "America/New_York"}, 33},
+		{"Leap day birthday", models.User{DOB: time.Date(2000, 2, 29, 0, 0, 0, 0, time.UTC)}, 24},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			age := calculateAge(tt.dob)
-			// Since we're testing with dynamic dates, we'll verify the calculation logic
-			now := time.Now()
-			expectedAge := now.Year() - tt.dob.Year()
-			if now.YearDay() < tt.dob.YearDay() {
-				expectedAge--
-			}
-
-			if age != expectedAge {
-				t.Errorf("calculateAge() = %v, want %v", age, expectedAge)
-			}
-
-			// Verify age is reasonable (not negative, not too large)
-			if age < 0 {
-				t.Errorf("calculateAge() returned negative age: %v", age)
-			}
-			if age > 150 {
-				t.Errorf("calculateAge() returned unrealistic age: %v", age)
+			got := s.newUserResponse(context.Background(), &tt.user)
+			if got.Age == nil || *got.Age != tt.expected {
+				t.Errorf("newUserResponse().Age = %v, want %v", got.Age, tt.expected)
 			}
 		})
 	}
 }
 
-func TestCalculateAgeEdgeCases(t *testing.T) {
-	// Test leap year birthday
-	leapYearDOB := time.Date(2000, 2, 29, 0, 0, 0, 0, time.UTC)
-	age := calculateAge(leapYearDOB)
-	if age < 0 {
-		t.Errorf("calculateAge() returned negative age for leap year: %v", age)
-	}
+func TestSetProfile(t *testing.T) {
+	u := models.User{Email: "old@example.com", Phone: "+14155550123", Timezone: "UTC"}
+	setProfile(&u, "  Alice@Example.COM ", "", "Europe/Paris", "fr-FR")
 
-	// Test very old date
-	oldDOB := time.Date(1900, 1, 1, 0, 0, 0, 0, time.UTC)
-	age = calculateAge(oldDOB)
-	if age < 100 {
-		t.Errorf("calculateAge() seems incorrect for old date: %v", age)
+	want := models.User{Email: "alice@example.com", Phone: "+14155550123", Timezone: "Europe/Paris", Locale: "fr-FR"}
+	if u != want {
+		t.Errorf("setProfile() = %+v, want %+v", u, want)
 	}
 }
 
+func TestValidateCreateUserProfile(t *testing.T) {
+	tests := []struct {
+		name  string
+		req   models.CreateUserRequest
+		valid bool
+	}{
+		{"Full profile", models.CreateUserRequest{Name: "Alice", DOB: "1990-05-10", Email: "alice@example.com", Phone: "+14155550123", Timezone: "America/New_York", Locale: "en-US"}, true},
+		{"No profile", models.CreateUserRequest{Name: "Alice", DOB: "1990-05-10"}, true},
+		{"Bad email", models.CreateUserRequest{Name: "Alice", DOB: "1990-05-10", Email: "alice@"}, false},
+		{"Phone without country code", models.CreateUserRequest{Name: "Alice", DOB: "1990-05-10", Phone: "4155550123"}, false},
+		{"Unknown timezone", models.CreateUserRequest{Name: "Alice", DOB: "1990-05-10", Timezone: "Europe/Atlantis"}, false},
+		{"Bad locale", models.CreateUserRequest{Name: "Alice", DOB: "1990-05-10", Locale: "english please"}, false},
+	}
 
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if msg := ValidateCreateUser(context.Background(), tt.req); (msg == "") != tt.valid {
+				t.Errorf("ValidateCreateUser() = %q, want valid %v", msg, tt.valid)
+			}
+		})
+	}
+}
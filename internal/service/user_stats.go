@@ -0,0 +1,295 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Pallavi566/Go-Backend/internal/models"
+	"github.com/Pallavi566/Go-Backend/internal/repository"
+)
+
+const (
+	// defaultAgeBuckets are the lower bounds of the default age histogram
+	// buckets.
+	defaultAgeBuckets = "0,18,25,35,45,55,65"
+	// maxAgeBuckets is the most age histogram buckets one may ask for.
+	maxAgeBuckets = 50
+	// maxSignupPeriods is the most signup periods one may ask for.
+	maxSignupPeriods = 1000
+)
+
+// defaultSignupPeriods is the number of periods, up to and including the
+// current one, counted when no since date is given.
+var defaultSignupPeriods = map[string]int{"day": 30, "week": 12, "month": 12}
+
+// StatsOptions shape the user statistics.
+type StatsOptions struct {
+	// Buckets are the ascending lower bounds of the age histogram buckets,
+	// starting with 0.
+	Buckets []int
+	// Interval is the length of the signup periods: day, week or month.
+	Interval string
+	// Since is the start of the first signup period.
+	Since time.Time
+}
+
+// key identifies the options in the stats cache.
+func (o StatsOptions) key() string {
+	return fmt.Sprint(o.Buckets, o.Interval, o.Since.Format("2006-01-02"))
+}
+
+type cachedStats struct {
+	stats   *models.UserStats
+	expires time.Time
+}
+
+// StatsService computes user statistics. Results are cached for a short
+// while, as computing them scans the whole users table.
+type StatsService struct {
+	users *UserService
+	ttl   time.Duration
+
+	mu    sync.Mutex
+	cache map[string]cachedStats
+}
+
+// NewStatsService returns a service that caches statistics for ttl. A zero
+// ttl disables the cache.
+func NewStatsService(users *UserService, ttl time.Duration) *StatsService {
+	return &StatsService{users: users, ttl: ttl, cache: make(map[string]cachedStats)}
+}
+
+// TTL returns how long statistics are cached for.
+func (s *StatsService) TTL() time.Duration {
+	return s.ttl
+}
+
+// ParseOptions parses the statistics options as given in the query: a comma
+// separated list of age bucket lower bounds, the signup interval and the
+// YYYY-MM-DD date to count signups from. Each defaults when empty.
+func (s *StatsService) ParseOptions(buckets, interval, since string) (StatsOptions, error) {
+	return parseStatsOptions(buckets, interval, since, s.users.ages.Today(""))
+}
+
+// GetUserStats returns the statistics of the active users, from the cache
+// when they were computed recently.
+func (s *StatsService) GetUserStats(ctx context.Context, opts StatsOptions) (*models.UserStats, error) {
+	key := opts.key()
+	now := s.users.ages.Now()
+
+	s.mu.Lock()
+	cached, ok := s.cache[key]
+	s.mu.Unlock()
+	if ok && now.Before(cached.expires) {
+		return cached.stats, nil
+	}
+
+	stats, err := s.computeStats(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	if s.ttl > 0 {
+		s.mu.Lock()
+		for k, c := range s.cache {
+			if !now.Before(c.expires) {
+				delete(s.cache, k)
+			}
+		}
+		s.cache[key] = cachedStats{stats: stats, expires: now.Add(s.ttl)}
+		s.mu.Unlock()
+	}
+	return stats, nil
+}
+
+// computeStats aggregates the statistics in the database.
+func (s *StatsService) computeStats(ctx context.Context, opts StatsOptions) (*models.UserStats, error) {
+	repo := s.users.repo
+	today := s.users.ages.Today("")
+
+	ages, err := repo.CountByAge(ctx, today, s.users.ages.Rule())
+	if err != nil {
+		return nil, err
+	}
+	decades, err := repo.CountByBirthDecade(ctx)
+	if err != nil {
+		return nil, err
+	}
+	signups, err := repo.CountSignups(ctx, opts.Interval, opts.Since)
+	if err != nil {
+		return nil, err
+	}
+
+	var total int64
+	for _, a := range ages {
+		total += a.Count
+	}
+	mean, median := ageMeanMedian(ages, total)
+
+	return &models.UserStats{
+		Total: total,
+		Age: models.AgeStats{
+			Mean:      mean,
+			Median:    median,
+			Histogram: ageHistogram(ages, opts.Buckets),
+		},
+		Signups: models.SignupStats{
+			Interval: opts.Interval,
+			Since:    opts.Since.Format("2006-01-02"),
+			Periods:  signupPeriods(signups, opts.Interval, opts.Since, today),
+		},
+		BirthDecades: decades,
+		GeneratedAt:  s.users.ages.Now().UTC(),
+	}, nil
+}
+
+// parseStatsOptions parses the statistics options; today anchors the
+// default since date.
+func parseStatsOptions(buckets, interval, since string, today time.Time) (StatsOptions, error) {
+	var opts StatsOptions
+
+	if buckets == "" {
+		buckets = defaultAgeBuckets
+	}
+	for _, b := range strings.Split(buckets, ",") {
+		n, err := strconv.Atoi(strings.TrimSpace(b))
+		if err != nil || n < 0 {
+			return StatsOptions{}, ErrInvalidFilter.Wrapf("buckets must be a comma separated list of ages")
+		}
+		if len(opts.Buckets) > 0 && n <= opts.Buckets[len(opts.Buckets)-1] {
+			return StatsOptions{}, ErrInvalidFilter.Wrapf("buckets must be in ascending order")
+		}
+		opts.Buckets = append(opts.Buckets, n)
+	}
+	// Everyone falls in some bucket.
+	if opts.Buckets[0] != 0 {
+		opts.Buckets = append([]int{0}, opts.Buckets...)
+	}
+	if len(opts.Buckets) > maxAgeBuckets {
+		return StatsOptions{}, ErrInvalidFilter.Wrapf("at most %d buckets are allowed", maxAgeBuckets)
+	}
+
+	if interval == "" {
+		interval = "day"
+	}
+	periods, ok := defaultSignupPeriods[interval]
+	if !ok {
+		return StatsOptions{}, ErrInvalidFilter.Wrapf("interval must be day, week or month")
+	}
+	opts.Interval = interval
+
+	current := periodStart(today, interval)
+	if since == "" {
+		opts.Since = addPeriods(current, interval, 1-periods)
+		return opts, nil
+	}
+	t, err := time.Parse("2006-01-02", since)
+	if err != nil {
+		return StatsOptions{}, ErrInvalidFilter.Wrapf("since must be YYYY-MM-DD")
+	}
+	opts.Since = periodStart(t, interval)
+	if opts.Since.After(current) {
+		return StatsOptions{}, ErrInvalidFilter.Wrapf("since must not be in the future")
+	}
+	if !addPeriods(opts.Since, interval, maxSignupPeriods).After(current) {
+		return StatsOptions{}, ErrInvalidFilter.Wrapf("at most %d signup periods are allowed", maxSignupPeriods)
+	}
+	return opts, nil
+}
+
+// periodStart returns the first day of the day, week (starting on Monday)
+// or month that d is in, matching the signup queries.
+func periodStart(d time.Time, interval string) time.Time {
+	d = time.Date(d.Year(), d.Month(), d.Day(), 0, 0, 0, 0, time.UTC)
+	switch interval {
+	case "week":
+		return d.AddDate(0, 0, -(int(d.Weekday())+6)%7)
+	case "month":
+		return d.AddDate(0, 0, 1-d.Day())
+	}
+	return d
+}
+
+// addPeriods moves the start of a period n periods on.
+func addPeriods(start time.Time, interval string, n int) time.Time {
+	switch interval {
+	case "week":
+		return start.AddDate(0, 0, 7*n)
+	case "month":
+		return start.AddDate(0, n, 0)
+	}
+	return start.AddDate(0, 0, n)
+}
+
+// signupPeriods lists every period from since up to and including the one
+// today is in, with the number of signups in it.
+func signupPeriods(counts []repository.PeriodCount, interval string, since, today time.Time) []models.PeriodCount {
+	byStart := make(map[string]int64, len(counts))
+	for _, c := range counts {
+		byStart[c.Start.Format("2006-01-02")] += c.Count
+	}
+
+	periods := []models.PeriodCount{}
+	end := periodStart(today, interval)
+	for start := since; !start.After(end); start = addPeriods(start, interval, 1) {
+		day := start.Format("2006-01-02")
+		periods = append(periods, models.PeriodCount{Start: day, Count: byStart[day]})
+	}
+	return periods
+}
+
+// ageHistogram sorts the per-age counts into buckets with the given lower
+// bounds, which start with 0.
+func ageHistogram(ages []repository.AgeCount, bounds []int) []models.AgeBucket {
+	histogram := make([]models.AgeBucket, len(bounds))
+	for i, min := range bounds {
+		histogram[i].Min = min
+		if i+1 < len(bounds) {
+			max := bounds[i+1] - 1
+			histogram[i].Max = &max
+		}
+	}
+	for _, a := range ages {
+		// The last bucket whose lower bound is at most the age.
+		i := sort.SearchInts(bounds, a.Age+1) - 1
+		if i >= 0 {
+			histogram[i].Count += a.Count
+		}
+	}
+	return histogram
+}
+
+// ageMeanMedian returns the mean and median age from the per-age counts,
+// youngest first, of total users. Both are nil when there are no users.
+func ageMeanMedian(ages []repository.AgeCount, total int64) (*float64, *float64) {
+	if total == 0 {
+		return nil, nil
+	}
+
+	var sum float64
+	for _, a := range ages {
+		sum += float64(a.Age) * float64(a.Count)
+	}
+	mean := sum / float64(total)
+
+	// The median is the middle age, or the average of the two middle ages
+	// when the number of users is even, counting from 0.
+	lower, upper := (total-1)/2, total/2
+	var median float64
+	var seen int64
+	for _, a := range ages {
+		if seen <= lower && lower < seen+a.Count {
+			median += float64(a.Age) / 2
+		}
+		if seen <= upper && upper < seen+a.Count {
+			median += float64(a.Age) / 2
+		}
+		seen += a.Count
+	}
+	return &mean, &median
+}
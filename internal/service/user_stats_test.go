@@ -0,0 +1,150 @@
+package service
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/Pallavi566/Go-Backend/internal/models"
+	"github.com/Pallavi566/Go-Backend/internal/repository"
+)
+
+func TestParseStatsOptions(t *testing.T) {
+	date := func(y int, m time.Month, d int) time.Time {
+		return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+	}
+	// A Thursday.
+	today := date(2024, 5, 16)
+
+	tests := []struct {
+		name     string
+		buckets  string
+		interval string
+		since    string
+		want     StatsOptions
+		wantErr  bool
+	}{
+		{
+			name: "Defaults",
+			want: StatsOptions{Buckets: []int{0, 18, 25, 35, 45, 55, 65}, Interval: "day", Since: date(2024, 4, 17)},
+		},
+		{
+			name:    "Custom buckets",
+			buckets: "0, 21, 65",
+			want:    StatsOptions{Buckets: []int{0, 21, 65}, Interval: "day", Since: date(2024, 4, 17)},
+		},
+		{
+			name:    "Buckets not starting at zero",
+			buckets: "18,30",
+			want:    StatsOptions{Buckets: []int{0, 18, 30}, Interval: "day", Since: date(2024, 4, 17)},
+		},
+		{
+			name:     "Weeks start on Monday",
+			interval: "week",
+			want:     StatsOptions{Buckets: []int{0, 18, 25, 35, 45, 55, 65}, Interval: "week", Since: date(2024, 2, 26)},
+		},
+		{
+			name:     "Months",
+			interval: "month",
+			want:     StatsOptions{Buckets: []int{0, 18, 25, 35, 45, 55, 65}, Interval: "month", Since: date(2023, 6, 1)},
+		},
+		{
+			name:     "Since is aligned to its period",
+			interval: "month",
+			since:    "2024-01-20",
+			want:     StatsOptions{Buckets: []int{0, 18, 25, 35, 45, 55, 65}, Interval: "month", Since: date(2024, 1, 1)},
+		},
+		{name: "Descending buckets", buckets: "0,30,18", wantErr: true},
+		{name: "Negative bucket", buckets: "-1,18", wantErr: true},
+		{name: "Non-numeric bucket", buckets: "0,adult", wantErr: true},
+		{name: "Unknown interval", interval: "year", wantErr: true},
+		{name: "Invalid since", since: "16/05/2024", wantErr: true},
+		{name: "Since in the future", since: "2024-05-17", wantErr: true},
+		{name: "Too many periods", since: "2020-01-01", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseStatsOptions(tt.buckets, tt.interval, tt.since, today)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidFilter) {
+					t.Errorf("parseStatsOptions() error = %v, want ErrInvalidFilter", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseStatsOptions() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseStatsOptions() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAgeHistogram(t *testing.T) {
+	ages := []repository.AgeCount{{Age: 5, Count: 2}, {Age: 17, Count: 1}, {Age: 18, Count: 3}, {Age: 40, Count: 4}, {Age: 90, Count: 1}}
+	seventeen, thirtyNine := 17, 39
+
+	got := ageHistogram(ages, []int{0, 18, 40})
+	want := []models.AgeBucket{
+		{Min: 0, Max: &seventeen, Count: 3},
+		{Min: 18, Max: &thirtyNine, Count: 3},
+		{Min: 40, Count: 5},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ageHistogram() = %+v, want %+v", got, want)
+	}
+}
+
+func TestAgeMeanMedian(t *testing.T) {
+	tests := []struct {
+		name       string
+		ages       []repository.AgeCount
+		wantMean   float64
+		wantMedian float64
+	}{
+		{"Single user", []repository.AgeCount{{Age: 30, Count: 1}}, 30, 30},
+		{"Odd count", []repository.AgeCount{{Age: 20, Count: 1}, {Age: 30, Count: 1}, {Age: 70, Count: 1}}, 40, 30},
+		{"Even count", []repository.AgeCount{{Age: 20, Count: 2}, {Age: 31, Count: 2}}, 25.5, 25.5},
+		{"Middle within one age", []repository.AgeCount{{Age: 20, Count: 1}, {Age: 25, Count: 3}}, 23.75, 25},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var total int64
+			for _, a := range tt.ages {
+				total += a.Count
+			}
+			mean, median := ageMeanMedian(tt.ages, total)
+			if mean == nil || *mean != tt.wantMean {
+				t.Errorf("mean = %v, want %v", mean, tt.wantMean)
+			}
+			if median == nil || *median != tt.wantMedian {
+				t.Errorf("median = %v, want %v", median, tt.wantMedian)
+			}
+		})
+	}
+
+	if mean, median := ageMeanMedian(nil, 0); mean != nil || median != nil {
+		t.Errorf("ageMeanMedian() with no users = %v, %v, want nil, nil", mean, median)
+	}
+}
+
+func TestSignupPeriods(t *testing.T) {
+	date := func(y int, m time.Month, d int) time.Time {
+		return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+	}
+	counts := []repository.PeriodCount{{Start: date(2024, 4, 1), Count: 2}, {Start: date(2024, 4, 15), Count: 5}}
+
+	got := signupPeriods(counts, "week", date(2024, 4, 1), date(2024, 4, 17))
+	want := []models.PeriodCount{
+		{Start: "2024-04-01", Count: 2},
+		{Start: "2024-04-08", Count: 0},
+		{Start: "2024-04-15", Count: 5},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("signupPeriods() = %+v, want %+v", got, want)
+	}
+}
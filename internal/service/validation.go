@@ -0,0 +1,124 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/Pallavi566/Go-Backend/internal/apperr"
+	"github.com/Pallavi566/Go-Backend/internal/i18n"
+	"github.com/Pallavi566/Go-Backend/internal/models"
+)
+
+// validate checks user input against the struct tags of the request models.
+var validate = newValidator()
+
+// Validator returns the validator of request structs. It names fields the
+// way clients send them, by their json, query or form tag, and its errors
+// can be translated by ValidationError. It is safe for concurrent use.
+func Validator() *validator.Validate {
+	return validate
+}
+
+func newValidator() *validator.Validate {
+	v := validator.New()
+	v.RegisterTagNameFunc(func(f reflect.StructField) string {
+		for _, key := range []string{"json", "query", "form"} {
+			if name, _, _ := strings.Cut(f.Tag.Get(key), ","); name != "" && name != "-" {
+				return name
+			}
+		}
+		return f.Name
+	})
+	if err := i18n.RegisterTranslations(v); err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// ValidationError turns the error of a failed validator check into an
+// apperr.ErrValidation listing the invalid fields, with messages in the
+// language stored in ctx. Other errors are returned as they are.
+func ValidationError(ctx context.Context, err error) error {
+	fields := fieldErrors(ctx, err)
+	if fields == nil {
+		return err
+	}
+	return apperr.ErrValidation.WithDetail(fieldsDetail(fields)).WithFields(fields...)
+}
+
+// CheckCreateUser checks a user as sent to create it, returning an
+// apperr.ErrValidation listing the invalid fields.
+func CheckCreateUser(ctx context.Context, req models.CreateUserRequest) error {
+	if err := validate.Struct(req); err != nil {
+		return ValidationError(ctx, err)
+	}
+
+	return checkDOB(ctx, req.DOB)
+}
+
+// CheckUpdateUser checks a user as sent to replace it, returning an
+// apperr.ErrValidation listing the invalid fields.
+func CheckUpdateUser(ctx context.Context, req models.UpdateUserRequest) error {
+	if err := validate.Struct(req); err != nil {
+		return ValidationError(ctx, err)
+	}
+	return checkDOB(ctx, req.DOB)
+}
+
+// checkDOB checks that a date of birth is in the YYYY-MM-DD format.
+func checkDOB(ctx context.Context, dob string) error {
+	if _, err := time.Parse("2006-01-02", dob); err != nil {
+		field := dobFieldError(ctx)
+		return apperr.ErrValidation.WithDetail(field.Message).WithFields(field)
+	}
+	return nil
+}
+
+// dobFieldError describes a date of birth that is not in the YYYY-MM-DD
+// format, in the language stored in ctx.
+func dobFieldError(ctx context.Context) apperr.FieldError {
+	return apperr.FieldError{Field: "dob", Code: "date", Param: "YYYY-MM-DD", Message: i18n.Message(ctx, "date", "dob")}
+}
+
+// fieldErrors describes the fields a validator check failed on, or returns
+// nil when err is not a failed check.
+func fieldErrors(ctx context.Context, err error) []apperr.FieldError {
+	var errs validator.ValidationErrors
+	if !errors.As(err, &errs) {
+		return nil
+	}
+	fields := make([]apperr.FieldError, len(errs))
+	for i, fe := range errs {
+		// The namespace starts with the name of the validated struct.
+		_, name, _ := strings.Cut(fe.Namespace(), ".")
+		msg, ok := i18n.TranslateField(ctx, fe)
+		if !ok {
+			msg = fieldMessage(name, fe)
+		}
+		fields[i] = apperr.FieldError{Field: name, Code: fe.Tag(), Param: fe.Param(), Message: msg}
+	}
+	return fields
+}
+
+// fieldMessage describes the rule a field broke, for checks without a
+// translation.
+func fieldMessage(name string, fe validator.FieldError) string {
+	if fe.Param() != "" {
+		return fmt.Sprintf("%s failed the %s=%s check", name, fe.Tag(), fe.Param())
+	}
+	return fmt.Sprintf("%s failed the %s check", name, fe.Tag())
+}
+
+// fieldsDetail summarizes invalid fields in one line.
+func fieldsDetail(fields []apperr.FieldError) string {
+	messages := make([]string, len(fields))
+	for i, f := range fields {
+		messages[i] = f.Message
+	}
+	return strings.Join(messages, "; ")
+}
@@ -0,0 +1,107 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/Pallavi566/Go-Backend/internal/apperr"
+	"github.com/Pallavi566/Go-Backend/internal/i18n"
+	"github.com/Pallavi566/Go-Backend/internal/models"
+)
+
+func TestCheckCreateUser(t *testing.T) {
+	tests := []struct {
+		name       string
+		lang       string
+		req        models.CreateUserRequest
+		wantFields []apperr.FieldError
+	}{
+		{"Valid", "", models.CreateUserRequest{Name: "Alice", DOB: "1990-05-10"}, nil},
+		{
+			name: "Fields named as sent",
+			req:  models.CreateUserRequest{DOB: "1990-05-10", Email: "alice"},
+			wantFields: []apperr.FieldError{
+				{Field: "name", Code: "required", Message: "name is a required field"},
+				{Field: "email", Code: "email", Message: "email must be a valid email address"},
+			},
+		},
+		{
+			name:       "Rule parameters",
+			req:        models.CreateUserRequest{Name: strings.Repeat("a", 256), DOB: "1990-05-10"},
+			wantFields: []apperr.FieldError{{Field: "name", Code: "max", Param: "255", Message: "name must be a maximum of 255 characters in length"}},
+		},
+		{
+			name:       "Bad date",
+			req:        models.CreateUserRequest{Name: "Alice", DOB: "10/05/1990"},
+			wantFields: []apperr.FieldError{{Field: "dob", Code: "date", Param: "YYYY-MM-DD", Message: "dob must be a date in the YYYY-MM-DD format"}},
+		},
+		{
+			name:       "Spanish",
+			lang:       "es",
+			req:        models.CreateUserRequest{DOB: "1990-05-10"},
+			wantFields: []apperr.FieldError{{Field: "name", Code: "required", Message: "name es un campo requerido"}},
+		},
+		{
+			name:       "Spanish date",
+			lang:       "es",
+			req:        models.CreateUserRequest{Name: "Alice", DOB: "10/05/1990"},
+			wantFields: []apperr.FieldError{{Field: "dob", Code: "date", Param: "YYYY-MM-DD", Message: "dob debe ser una fecha con el formato AAAA-MM-DD"}},
+		},
+		{
+			name:       "French falls back to English",
+			lang:       "fr",
+			req:        models.CreateUserRequest{Name: "Alice", DOB: "1990-05-10", Phone: "555"},
+			wantFields: []apperr.FieldError{{Field: "phone", Code: "e164", Message: "phone must be a valid E.164 formatted phone number"}},
+		},
+		{
+			name:       "Rule without translation",
+			lang:       "fr",
+			req:        models.CreateUserRequest{Name: "Alice", DOB: "1990-05-10", Timezone: "Mars/Olympus"},
+			wantFields: []apperr.FieldError{{Field: "timezone", Code: "timezone", Message: "timezone failed the timezone check"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			if tt.lang != "" {
+				ctx = i18n.WithLanguage(ctx, tt.lang)
+			}
+			err := CheckCreateUser(ctx, tt.req)
+			if tt.wantFields == nil {
+				if err != nil {
+					t.Fatalf("CheckCreateUser() error = %v", err)
+				}
+				return
+			}
+			var appErr *apperr.Error
+			if !errors.As(err, &appErr) || !errors.Is(err, apperr.ErrValidation) {
+				t.Fatalf("CheckCreateUser() error = %v, want a validation error", err)
+			}
+			if !reflect.DeepEqual(appErr.Fields, tt.wantFields) {
+				t.Errorf("fields = %+v, want %+v", appErr.Fields, tt.wantFields)
+			}
+		})
+	}
+}
+
+func TestCheckUpdateUser(t *testing.T) {
+	ctx := i18n.WithLanguage(context.Background(), "es")
+	err := CheckUpdateUser(ctx, models.UpdateUserRequest{Name: "Alice", DOB: "10/05/1990"})
+
+	want := []apperr.FieldError{{Field: "dob", Code: "date", Param: "YYYY-MM-DD", Message: "dob debe ser una fecha con el formato AAAA-MM-DD"}}
+	var appErr *apperr.Error
+	if !errors.As(err, &appErr) || !errors.Is(err, apperr.ErrValidation) {
+		t.Fatalf("CheckUpdateUser() error = %v, want a validation error", err)
+	}
+	if !reflect.DeepEqual(appErr.Fields, want) {
+		t.Errorf("fields = %+v, want %+v", appErr.Fields, want)
+	}
+
+	if err := CheckUpdateUser(ctx, models.UpdateUserRequest{Name: "Alice", DOB: "1990-05-10"}); err != nil {
+		t.Errorf("CheckUpdateUser() of a valid user error = %v", err)
+	}
+}
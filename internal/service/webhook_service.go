@@ -0,0 +1,101 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"net/url"
+	"time"
+
+	"github.com/Pallavi566/Go-Backend/internal/apperr"
+	"github.com/Pallavi566/Go-Backend/internal/models"
+	"github.com/Pallavi566/Go-Backend/internal/repository"
+)
+
+var (
+	// ErrWebhookNotFound is returned when the webhook subscription does not
+	// exist.
+	ErrWebhookNotFound = repository.ErrWebhookNotFound
+	// ErrInvalidWebhook is returned when a subscription cannot be created.
+	ErrInvalidWebhook = apperr.New(apperr.ErrValidation, "invalid_webhook", "invalid webhook subscription")
+)
+
+type WebhookService struct {
+	repo repository.WebhookRepository
+}
+
+func NewWebhookService(repo repository.WebhookRepository) *WebhookService {
+	return &WebhookService{repo: repo}
+}
+
+// CreateSubscription registers a URL for user events and generates the
+// secret its deliveries are signed with. The secret is only ever returned
+// here.
+func (s *WebhookService) CreateSubscription(ctx context.Context, req models.CreateWebhookRequest) (*models.WebhookSubscription, error) {
+	u, err := url.Parse(req.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, ErrInvalidWebhook.Wrapf("url must be an absolute http or https URL")
+	}
+
+	secret, err := newWebhookSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	sub, err := s.repo.CreateSubscription(ctx, req.URL, secret, dedupe(req.EventTypes))
+	if err != nil {
+		return nil, err
+	}
+	sub.Secret = secret
+	return sub, nil
+}
+
+func (s *WebhookService) GetSubscription(ctx context.Context, id int) (*models.WebhookSubscription, error) {
+	return s.repo.GetSubscription(ctx, id)
+}
+
+func (s *WebhookService) ListSubscriptions(ctx context.Context) ([]*models.WebhookSubscription, error) {
+	return s.repo.ListSubscriptions(ctx)
+}
+
+// SetSubscriptionActive enables or disables a subscription and returns it.
+func (s *WebhookService) SetSubscriptionActive(ctx context.Context, id int, active bool) (*models.WebhookSubscription, error) {
+	if err := s.repo.SetSubscriptionActive(ctx, id, active); err != nil {
+		return nil, err
+	}
+	return s.repo.GetSubscription(ctx, id)
+}
+
+func (s *WebhookService) ListDeliveries(ctx context.Context, id, limit int) ([]*models.WebhookDelivery, error) {
+	if _, err := s.repo.GetSubscription(ctx, id); err != nil {
+		return nil, err
+	}
+	return s.repo.ListDeliveries(ctx, id, limit)
+}
+
+// ReplayDeliveries schedules deliveries of a subscription to be sent again.
+// With a nil since, dead-lettered deliveries are replayed; otherwise every
+// delivery created at or after since is. It returns how many were replayed.
+func (s *WebhookService) ReplayDeliveries(ctx context.Context, id int, since *time.Time) (int64, error) {
+	return s.repo.Replay(ctx, id, since)
+}
+
+func newWebhookSecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return "whsec_" + hex.EncodeToString(b), nil
+}
+
+func dedupe(values []string) []string {
+	seen := make(map[string]bool, len(values))
+	out := make([]string, 0, len(values))
+	for _, v := range values {
+		if !seen[v] {
+			seen[v] = true
+			out = append(out, v)
+		}
+	}
+	return out
+}
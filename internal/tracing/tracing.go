@@ -0,0 +1,145 @@
+// Package tracing sets up OpenTelemetry tracing and starts the spans of the
+// service: one per HTTP request, continuing the trace of the caller from its
+// W3C traceparent header, one per service call and one per SQL query.
+package tracing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/Pallavi566/Go-Backend/internal/apperr"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+)
+
+// instrumentation names the tracer the spans of the service are started
+// with.
+const instrumentation = "github.com/Pallavi566/Go-Backend"
+
+// The exporters spans can be sent to.
+const (
+	// ExporterNone drops spans. Traces are still propagated.
+	ExporterNone = "none"
+	// ExporterOTLP sends spans to an OpenTelemetry collector over OTLP/HTTP.
+	ExporterOTLP = "otlp"
+	// ExporterStdout writes spans to stdout, for local debugging.
+	ExporterStdout = "stdout"
+)
+
+// Config configures tracing.
+type Config struct {
+	// Exporter is where spans are sent: ExporterNone, ExporterOTLP or
+	// ExporterStdout.
+	Exporter string
+	// Endpoint is the URL of the OTLP/HTTP collector, e.g.
+	// http://localhost:4318. Empty uses the OTEL_EXPORTER_OTLP_* variables.
+	Endpoint string
+	// ServiceName names the service in traces.
+	ServiceName string
+}
+
+// Setup installs the global tracer provider and the W3C trace context
+// propagator. The returned function flushes the spans not yet exported and
+// must be called before exiting.
+func Setup(ctx context.Context, cfg Config) (func(context.Context) error, error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
+	exporter, err := newExporter(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+	if exporter == nil {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(resource.NewSchemaless(semconv.ServiceName(cfg.ServiceName))),
+		// Follow the sampling decision of the caller, sampling traces that
+		// start here.
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.AlwaysSample())),
+	)
+	otel.SetTracerProvider(provider)
+	return provider.Shutdown, nil
+}
+
+// SetupInMemory installs a tracer provider that keeps every span in
+// memory, for tests, and returns the exporter holding them. Spans are
+// exported as soon as they end.
+func SetupInMemory() *tracetest.InMemoryExporter {
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	exporter := tracetest.NewInMemoryExporter()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter)))
+	return exporter
+}
+
+// newExporter returns the exporter cfg asks for, or nil for none.
+func newExporter(ctx context.Context, cfg Config) (sdktrace.SpanExporter, error) {
+	switch cfg.Exporter {
+	case ExporterNone, "":
+		return nil, nil
+	case ExporterOTLP:
+		var opts []otlptracehttp.Option
+		if cfg.Endpoint != "" {
+			opts = append(opts, otlptracehttp.WithEndpointURL(cfg.Endpoint))
+		}
+		return otlptracehttp.New(ctx, opts...)
+	case ExporterStdout:
+		return stdouttrace.New(stdouttrace.WithWriter(os.Stdout))
+	}
+	return nil, fmt.Errorf("unknown trace exporter %q: must be none, otlp or stdout", cfg.Exporter)
+}
+
+// Tracer returns the tracer of the service, from the global tracer provider.
+func Tracer() trace.Tracer {
+	return otel.Tracer(instrumentation)
+}
+
+// Start starts a span as a child of the one in ctx, returning a copy of ctx
+// carrying it.
+func Start(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return Tracer().Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// End ends span, recording *err. Errors clients are told about, such as a
+// user not being found, are the outcome of the call rather than a failure,
+// so they only mark the span with their code. It is meant to be deferred
+// with a pointer to a named result.
+func End(span trace.Span, err *error) {
+	defer span.End()
+	if err == nil || *err == nil {
+		return
+	}
+	var appErr *apperr.Error
+	if errors.As(*err, &appErr) && appErr.KindOf() != apperr.ErrUnavailable {
+		span.SetAttributes(attribute.String("error.code", appErr.Code))
+		return
+	}
+	span.RecordError(*err)
+	span.SetStatus(codes.Error, (*err).Error())
+}
+
+// LogFields returns the zap fields identifying the span in ctx, so that log
+// lines can be found from a trace. There are none outside a trace.
+func LogFields(ctx context.Context) []zap.Field {
+	sc := trace.SpanContextFromContext(ctx)
+	if !sc.IsValid() {
+		return nil
+	}
+	return []zap.Field{
+		zap.String("trace_id", sc.TraceID().String()),
+		zap.String("span_id", sc.SpanID().String()),
+	}
+}
@@ -0,0 +1,73 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/Pallavi566/Go-Backend/internal/apperr"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+)
+
+func TestEnd(t *testing.T) {
+	exporter := SetupInMemory()
+	errMissing := apperr.New(apperr.ErrNotFound, "user_not_found", "user not found")
+	errDown := apperr.New(apperr.ErrUnavailable, "database_unavailable", "database unavailable")
+
+	tests := []struct {
+		name       string
+		err        error
+		wantStatus codes.Code
+		wantCode   string
+	}{
+		{"Success", nil, codes.Unset, ""},
+		{"Client error", fmt.Errorf("%w: id 5", errMissing), codes.Unset, "user_not_found"},
+		{"Unavailable", fmt.Errorf("%w: %w", errDown, errors.New("connection refused")), codes.Error, ""},
+		{"Unexpected error", errors.New("boom"), codes.Error, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			exporter.Reset()
+			call := func() (err error) {
+				_, span := Start(context.Background(), "call")
+				defer End(span, &err)
+				return tt.err
+			}
+			_ = call()
+
+			spans := exporter.GetSpans()
+			if len(spans) != 1 {
+				t.Fatalf("got %d spans, want 1", len(spans))
+			}
+			if got := spans[0].Status.Code; got != tt.wantStatus {
+				t.Errorf("status = %v, want %v", got, tt.wantStatus)
+			}
+			var code string
+			for _, attr := range spans[0].Attributes {
+				if attr.Key == attribute.Key("error.code") {
+					code = attr.Value.AsString()
+				}
+			}
+			if code != tt.wantCode {
+				t.Errorf("error.code = %q, want %q", code, tt.wantCode)
+			}
+		})
+	}
+}
+
+func TestLogFields(t *testing.T) {
+	SetupInMemory()
+	if fields := LogFields(context.Background()); fields != nil {
+		t.Errorf("LogFields() outside a trace = %v, want none", fields)
+	}
+
+	ctx, span := Start(context.Background(), "call")
+	defer span.End()
+	fields := LogFields(ctx)
+	if len(fields) != 2 || fields[0].String != span.SpanContext().TraceID().String() {
+		t.Errorf("LogFields() = %v, want the trace and span IDs", fields)
+	}
+}
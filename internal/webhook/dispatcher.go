@@ -0,0 +1,197 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/Pallavi566/Go-Backend/internal/models"
+	"github.com/Pallavi566/Go-Backend/internal/repository"
+	"go.uber.org/zap"
+)
+
+// Config controls how the dispatcher polls and retries.
+type Config struct {
+	// PollInterval is how often the outbox and due deliveries are polled.
+	PollInterval time.Duration
+	// Timeout bounds a single delivery attempt.
+	Timeout time.Duration
+	// MaxAttempts is the number of attempts after which a delivery is
+	// dead-lettered.
+	MaxAttempts int
+	// BackoffBase is the delay after the first failed attempt; it doubles
+	// with every further attempt up to BackoffMax.
+	BackoffBase time.Duration
+	BackoffMax  time.Duration
+	// BatchSize is the number of events and deliveries handled per poll.
+	BatchSize int
+}
+
+// Dispatcher moves user events from the outbox to webhook subscriptions.
+// Deliveries are at least once: receivers should deduplicate on the
+// X-Webhook-Id header, which carries the event ID.
+type Dispatcher struct {
+	repo   *repository.WebhookRepository
+	client *http.Client
+	cfg    Config
+	logger *zap.Logger
+}
+
+func NewDispatcher(repo *repository.WebhookRepository, cfg Config, logger *zap.Logger) *Dispatcher {
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = 100
+	}
+	return &Dispatcher{
+		repo:   repo,
+		client: &http.Client{Timeout: cfg.Timeout},
+		cfg:    cfg,
+		logger: logger,
+	}
+}
+
+// Run polls the outbox and delivers due webhooks every PollInterval. It
+// blocks until ctx is cancelled.
+func (d *Dispatcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(d.cfg.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		if n, err := d.repo.FanOut(ctx, d.cfg.BatchSize); err != nil {
+			d.logger.Error("Failed to fan out outbox events", zap.Error(err))
+		} else if n > 0 {
+			d.logger.Debug("Fanned out outbox events", zap.Int("count", n))
+		}
+		if err := d.deliverDue(ctx); err != nil && ctx.Err() == nil {
+			d.logger.Error("Failed to deliver webhooks", zap.Error(err))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (d *Dispatcher) deliverDue(ctx context.Context) error {
+	due, err := d.repo.DueDeliveries(ctx, time.Now(), d.cfg.BatchSize)
+	if err != nil {
+		return err
+	}
+
+	for _, delivery := range due {
+		if ctx.Err() != nil {
+			return nil
+		}
+		// Lease the delivery for longer than an attempt can take so that
+		// concurrent dispatchers skip it.
+		claimed, err := d.repo.Claim(ctx, delivery, time.Now().Add(2*d.cfg.Timeout))
+		if err != nil {
+			return err
+		}
+		if !claimed {
+			continue
+		}
+		if err := d.attempt(ctx, delivery); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// attempt sends a claimed delivery once and records the outcome.
+func (d *Dispatcher) attempt(ctx context.Context, delivery repository.DueDelivery) error {
+	body, err := eventBody(delivery)
+	if err != nil {
+		return err
+	}
+
+	status, sendErr := d.send(ctx, delivery.URL, delivery.Secret, delivery.EventID, delivery.EventType, body)
+	if sendErr == nil {
+		return d.repo.MarkDelivered(ctx, delivery.ID, status)
+	}
+
+	attempts := delivery.Attempts + 1
+	dead := attempts >= d.cfg.MaxAttempts
+	next := time.Now().Add(Backoff(d.cfg.BackoffBase, d.cfg.BackoffMax, attempts))
+	fields := []zap.Field{
+		zap.Int64("delivery_id", delivery.ID),
+		zap.Int("subscription_id", delivery.SubscriptionID),
+		zap.Int("attempts", attempts),
+		zap.Error(sendErr),
+	}
+	if dead {
+		d.logger.Warn("Webhook delivery dead-lettered", fields...)
+	} else {
+		d.logger.Info("Webhook delivery failed, will retry", append(fields, zap.Time("next_attempt_at", next))...)
+	}
+	return d.repo.MarkFailed(ctx, delivery.ID, status, sendErr.Error(), next, dead)
+}
+
+// send POSTs a signed event body to url. It returns the response status, or
+// zero when no response was received, and an error unless the receiver
+// answered with a 2xx status.
+func (d *Dispatcher) send(ctx context.Context, url, secret string, eventID int64, eventType string, body []byte) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	timestamp := time.Now().Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "Go-Backend-Webhooks/1.0")
+	req.Header.Set(HeaderID, strconv.FormatInt(eventID, 10))
+	req.Header.Set(HeaderEvent, eventType)
+	req.Header.Set(HeaderTimestamp, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(HeaderSignature, Sign(secret, timestamp, body))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("receiver responded with status %d", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
+
+// eventBody renders the JSON body delivered for an event.
+func eventBody(delivery repository.DueDelivery) ([]byte, error) {
+	body, err := json.Marshal(models.Event{
+		ID:         delivery.EventID,
+		Type:       delivery.EventType,
+		OccurredAt: delivery.EventCreatedAt.UTC(),
+		Data:       delivery.Payload,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("event %d: %w", delivery.EventID, err)
+	}
+	return body, nil
+}
+
+// Backoff returns the delay before the attempt following the given number
+// of failed attempts: base doubled for every attempt after the first,
+// capped at max.
+func Backoff(base, max time.Duration, attempts int) time.Duration {
+	if attempts < 1 {
+		attempts = 1
+	}
+	delay := base
+	for i := 1; i < attempts; i++ {
+		delay *= 2
+		if delay >= max || delay <= 0 {
+			return max
+		}
+	}
+	if delay > max {
+		return max
+	}
+	return delay
+}
@@ -0,0 +1,105 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Pallavi566/Go-Backend/internal/models"
+	"github.com/Pallavi566/Go-Backend/internal/repository"
+)
+
+func TestSendSignsDelivery(t *testing.T) {
+	var got *http.Request
+	var gotBody []byte
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r
+		gotBody, _ = io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer receiver.Close()
+
+	body, err := eventBody(repository.DueDelivery{
+		EventID:        42,
+		EventType:      models.EventUserCreated,
+		Payload:        json.RawMessage(`{"user":{"id":7,"name":"Alice","dob":"1990-05-10","version":1,"deleted":false},"actor":"admin"}`),
+		EventCreatedAt: time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC),
+	})
+	if err != nil {
+		t.Fatalf("eventBody() error = %v", err)
+	}
+
+	d := &Dispatcher{client: receiver.Client()}
+	status, err := d.send(context.Background(), receiver.URL, "s3cret", 42, models.EventUserCreated, body)
+	if err != nil {
+		t.Fatalf("send() error = %v", err)
+	}
+	if status != http.StatusNoContent {
+		t.Errorf("send() status = %d, want %d", status, http.StatusNoContent)
+	}
+
+	if id := got.Header.Get(HeaderID); id != "42" {
+		t.Errorf("%s = %q, want 42", HeaderID, id)
+	}
+	if event := got.Header.Get(HeaderEvent); event != models.EventUserCreated {
+		t.Errorf("%s = %q, want %q", HeaderEvent, event, models.EventUserCreated)
+	}
+	err = Verify("s3cret", got.Header.Get(HeaderTimestamp), got.Header.Get(HeaderSignature), gotBody, time.Now(), time.Minute)
+	if err != nil {
+		t.Errorf("Verify() error = %v", err)
+	}
+
+	var event models.Event
+	if err := json.Unmarshal(gotBody, &event); err != nil {
+		t.Fatalf("received body is not an event: %v", err)
+	}
+	var data models.UserEventData
+	if err := json.Unmarshal(event.Data, &data); err != nil {
+		t.Fatalf("received event data is not a user event: %v", err)
+	}
+	if event.ID != 42 || data.User == nil || data.User.Name != "Alice" || data.Actor != "admin" {
+		t.Errorf("received event = %+v, data = %+v", event, data)
+	}
+}
+
+func TestSendFailureStatus(t *testing.T) {
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer receiver.Close()
+
+	d := &Dispatcher{client: receiver.Client()}
+	status, err := d.send(context.Background(), receiver.URL, "s3cret", 1, models.EventUserDeleted, []byte(`{}`))
+	if err == nil {
+		t.Fatal("send() error = nil, want error")
+	}
+	if status != http.StatusServiceUnavailable {
+		t.Errorf("send() status = %d, want %d", status, http.StatusServiceUnavailable)
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	base, max := 30*time.Second, 10*time.Minute
+	tests := []struct {
+		attempts int
+		want     time.Duration
+	}{
+		{0, 30 * time.Second},
+		{1, 30 * time.Second},
+		{2, time.Minute},
+		{3, 2 * time.Minute},
+		{5, 8 * time.Minute},
+		{6, 10 * time.Minute},
+		{100, 10 * time.Minute},
+	}
+
+	for _, tt := range tests {
+		if got := Backoff(base, max, tt.attempts); got != tt.want {
+			t.Errorf("Backoff(%d) = %v, want %v", tt.attempts, got, tt.want)
+		}
+	}
+}
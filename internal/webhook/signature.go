@@ -0,0 +1,61 @@
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Headers set on every delivery.
+const (
+	HeaderID        = "X-Webhook-Id"
+	HeaderEvent     = "X-Webhook-Event"
+	HeaderTimestamp = "X-Webhook-Timestamp"
+	HeaderSignature = "X-Webhook-Signature"
+)
+
+var (
+	// ErrInvalidSignature is returned by Verify when the signature does not
+	// match the body.
+	ErrInvalidSignature = errors.New("invalid webhook signature")
+	// ErrStaleTimestamp is returned by Verify when the delivery is older
+	// than the allowed tolerance, which protects receivers from replays.
+	ErrStaleTimestamp = errors.New("webhook timestamp outside tolerance")
+)
+
+// Sign returns the signature header value for a delivery body sent at
+// timestamp (Unix seconds). The signature is the hex encoded HMAC-SHA256 of
+// "<timestamp>.<body>" keyed with the subscription secret, prefixed with
+// "sha256=".
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify checks the timestamp and signature headers of a received delivery
+// against its body. It is what receivers are expected to do and is used in
+// tests.
+func Verify(secret, timestamp, signature string, body []byte, now time.Time, tolerance time.Duration) error {
+	ts, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return fmt.Errorf("%w: bad timestamp %q", ErrInvalidSignature, timestamp)
+	}
+	if age := now.Sub(time.Unix(ts, 0)); age > tolerance || age < -tolerance {
+		return ErrStaleTimestamp
+	}
+	if !strings.HasPrefix(signature, "sha256=") {
+		return ErrInvalidSignature
+	}
+	if !hmac.Equal([]byte(signature), []byte(Sign(secret, ts, body))) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
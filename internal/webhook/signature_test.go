@@ -0,0 +1,41 @@
+package webhook
+
+import (
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestVerify(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	body := []byte(`{"id":1,"type":"user.created"}`)
+	ts := strconv.FormatInt(now.Unix(), 10)
+	sig := Sign("secret", now.Unix(), body)
+
+	tests := []struct {
+		name      string
+		secret    string
+		timestamp string
+		signature string
+		body      []byte
+		wantErr   error
+	}{
+		{"Valid", "secret", ts, sig, body, nil},
+		{"Wrong secret", "other", ts, sig, body, ErrInvalidSignature},
+		{"Tampered body", "secret", ts, sig, []byte(`{"id":2,"type":"user.created"}`), ErrInvalidSignature},
+		{"Tampered timestamp", "secret", strconv.FormatInt(now.Unix()-1, 10), sig, body, ErrInvalidSignature},
+		{"Missing prefix", "secret", ts, sig[len("sha256="):], body, ErrInvalidSignature},
+		{"Bad timestamp", "secret", "yesterday", sig, body, ErrInvalidSignature},
+		{"Stale", "secret", strconv.FormatInt(now.Add(-10*time.Minute).Unix(), 10), sig, body, ErrStaleTimestamp},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Verify(tt.secret, tt.timestamp, tt.signature, tt.body, now, 5*time.Minute)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Verify() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}